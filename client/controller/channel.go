@@ -72,11 +72,13 @@ func (c *channelController) Commit(ctx context.Context) error {
 		Graph:   c.graph.FilePath,
 		Channel: c.existingName,
 		Config:  cfg,
+		Version: c.gc.version,
 	}
-	_, err := c.client.SetChannel(ctx, req)
+	resp, err := c.client.SetChannel(ctx, req)
 	if err != nil {
 		return err // TODO: contextualise
 	}
+	c.gc.edited(resp)
 	if cfg.Name != c.existingName {
 		delete(c.graph.Channels, c.existingName)
 		c.channel.Name = cfg.Name
@@ -94,13 +96,15 @@ func (c *channelController) Delete(ctx context.Context) error {
 	if c.existingName == "" {
 		return nil
 	}
-	_, err := c.client.SetChannel(ctx, &pb.SetChannelRequest{
+	resp, err := c.client.SetChannel(ctx, &pb.SetChannelRequest{
 		Graph:   c.graph.FilePath,
 		Channel: c.existingName,
+		Version: c.gc.version,
 	})
 	if err != nil {
 		return err // TODO: contextualise
 	}
+	c.gc.edited(resp)
 	c.graph.DeleteChannel(c.channel)
 	return nil
 }
@@ -17,6 +17,7 @@ package controller
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"log"
 	"strconv"
@@ -44,6 +45,12 @@ type graphController struct {
 	graph  *model.Graph
 	client pb.ShenzhenGoClient
 
+	// version is the version of the graph on the server that the model
+	// reflects. ownVersions are versions resulting from edits made by this
+	// client that are yet to arrive from WatchGraph.
+	version     uint64
+	ownVersions map[uint64]bool
+
 	// RHS panels
 	currentRHSPanel        dom.Element
 	channelPropertiesPanel dom.Element
@@ -62,7 +69,11 @@ type graphController struct {
 	// Graph properties panel inputs
 	graphNameTextInput        dom.Element
 	graphPackagePathTextInput dom.Element
+	graphOutputDirTextInput   dom.Element
 	graphIsCommandCheckbox    dom.Element
+	graphUseContextCheckbox   dom.Element
+	graphTelemetryCheckbox    dom.Element
+	graphGenericsCheckbox     dom.Element
 
 	// Components that are connected to whatever is selected.
 	channelSharedOutlets *channelSharedOutlets
@@ -78,8 +89,9 @@ func setupAceView(id, mode string) *dom.AceSession {
 	return e.Session().SetMode(mode)
 }
 
-// NewGraphController returns a new controller for a graph, and binds outlets.
-func NewGraphController(doc dom.Document, graph *model.Graph, client pb.ShenzhenGoClient) view.GraphController {
+// NewGraphController returns a new controller for a graph at a given version,
+// and binds outlets.
+func NewGraphController(doc dom.Document, graph *model.Graph, version uint64, client pb.ShenzhenGoClient) view.GraphController {
 	pes := make(map[string]*partEditor, len(model.PartTypes))
 	for n, t := range model.PartTypes {
 		p := make(map[string]*subpanel, len(t.Panels))
@@ -105,6 +117,9 @@ func NewGraphController(doc dom.Document, graph *model.Graph, client pb.Shenzhen
 		client: client,
 		graph:  graph,
 
+		version:     version,
+		ownVersions: make(map[uint64]bool),
+
 		currentRHSPanel: doc.ElementByID("graph-properties"),
 
 		channelPropertiesPanel: doc.ElementByID("channel-properties"),
@@ -121,7 +136,11 @@ func NewGraphController(doc dom.Document, graph *model.Graph, client pb.Shenzhen
 
 		graphNameTextInput:        doc.ElementByID("graph-prop-name"),
 		graphPackagePathTextInput: doc.ElementByID("graph-prop-package-path"),
+		graphOutputDirTextInput:   doc.ElementByID("graph-prop-output-dir"),
 		graphIsCommandCheckbox:    doc.ElementByID("graph-prop-is-command"),
+		graphUseContextCheckbox:   doc.ElementByID("graph-prop-use-context"),
+		graphTelemetryCheckbox:    doc.ElementByID("graph-prop-telemetry"),
+		graphGenericsCheckbox:     doc.ElementByID("graph-prop-generics"),
 
 		channelSharedOutlets: &channelSharedOutlets{
 			inputName:     doc.ElementByID("channel-name"),
@@ -136,6 +155,11 @@ func NewGraphController(doc dom.Document, graph *model.Graph, client pb.Shenzhen
 			inputEnabled:      doc.ElementByID("node-enabled"),
 			inputMultiplicity: doc.ElementByID("node-multiplicity"),
 			inputWait:         doc.ElementByID("node-wait"),
+			inputRecover:      doc.ElementByID("node-recover"),
+			inputRestart:      doc.ElementByID("node-restart"),
+			inputMaxRestarts:  doc.ElementByID("node-max-restarts"),
+			inputBackoff:      doc.ElementByID("node-restart-backoff"),
+			inputMetrics:      doc.ElementByID("node-restart-metrics"),
 			partEditors:       pes,
 		},
 	}
@@ -244,8 +268,9 @@ func (c *graphController) CreateNode(ctx context.Context, partType string) (view
 		Y: 150,
 	}
 
-	_, err = c.client.SetNode(ctx, &pb.SetNodeRequest{
-		Graph: c.graph.FilePath,
+	resp, err := c.client.SetNode(ctx, &pb.SetNodeRequest{
+		Graph:   c.graph.FilePath,
+		Version: c.version,
 		Config: &pb.NodeConfig{
 			Name:         n.Name,
 			Enabled:      n.Enabled,
@@ -258,45 +283,51 @@ func (c *graphController) CreateNode(ctx context.Context, partType string) (view
 	if err != nil {
 		return nil, err
 	}
+	c.edited(resp)
 	c.graph.Nodes[n.Name] = n
 	n.RefreshConnections()
 	return c.newNodeController(n), nil
 }
 
-func (c *graphController) action(ctx context.Context, a pb.ActionRequest_Action) error {
+// action performs an action, showing the output in the terminal. It
+// returns any compile errors sent by the server.
+func (c *graphController) action(ctx context.Context, a pb.ActionRequest_Action) ([]*pb.CompileError, error) {
 	stream, err := c.client.Action(ctx, &pb.ActionRequest{
 		Graph:  c.graph.FilePath,
 		Action: a,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if a == pb.ActionRequest_SAVE || a == pb.ActionRequest_REVERT {
 		// No need for a terminal
-		return nil
+		return nil, nil
 	}
 	c.ShowHterm()
 	c.htermTerminal.ClearHome()
 	tio := c.htermTerminal.IO().Push()
+	var ces []*pb.CompileError
 	for {
 		resp, err := stream.Recv()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
-			return err
+			return ces, err
 		}
 		tio.Print(resp.Output)
+		ces = append(ces, resp.CompileErrors...)
 	}
-	return nil
+	return ces, nil
 }
 
 func (c *graphController) Save(ctx context.Context) error {
-	return c.action(ctx, pb.ActionRequest_SAVE)
+	_, err := c.action(ctx, pb.ActionRequest_SAVE)
+	return err
 }
 
 func (c *graphController) Revert(ctx context.Context) error {
-	if err := c.action(ctx, pb.ActionRequest_REVERT); err != nil {
+	if _, err := c.action(ctx, pb.ActionRequest_REVERT); err != nil {
 		return err
 	}
 	// TODO: Less janky reloading. (call into view reload)
@@ -304,16 +335,51 @@ func (c *graphController) Revert(ctx context.Context) error {
 	return nil
 }
 
+// Undo asks the server to undo the last edit. Like edits made by other
+// clients, the change reaches the model through WatchGraph.
+func (c *graphController) Undo(ctx context.Context) error {
+	_, err := c.client.Undo(ctx, &pb.UndoRequest{Graph: c.graph.FilePath})
+	return err
+}
+
+// Redo asks the server to redo the last undone edit.
+func (c *graphController) Redo(ctx context.Context) error {
+	_, err := c.client.Redo(ctx, &pb.RedoRequest{Graph: c.graph.FilePath})
+	return err
+}
+
+func (c *graphController) Check(ctx context.Context) error {
+	_, err := c.action(ctx, pb.ActionRequest_CHECK)
+	return err
+}
+
 func (c *graphController) Generate(ctx context.Context) error {
-	return c.action(ctx, pb.ActionRequest_GENERATE)
+	_, err := c.action(ctx, pb.ActionRequest_GENERATE)
+	return err
 }
 
-func (c *graphController) Build(ctx context.Context) error {
-	return c.action(ctx, pb.ActionRequest_BUILD)
+// Build builds the graph, returning the compile errors in the code of nodes.
+func (c *graphController) Build(ctx context.Context) ([]*view.CompileError, error) {
+	pces, err := c.action(ctx, pb.ActionRequest_BUILD)
+	var ces []*view.CompileError
+	for _, pce := range pces {
+		n := c.graph.Nodes[pce.Node]
+		if n == nil {
+			continue
+		}
+		ces = append(ces, &view.CompileError{
+			Node:    displayName(n),
+			Section: pce.Section,
+			Line:    int(pce.SectionLine),
+			Message: pce.Message,
+		})
+	}
+	return ces, err
 }
 
 func (c *graphController) Install(ctx context.Context) error {
-	return c.action(ctx, pb.ActionRequest_INSTALL)
+	_, err := c.action(ctx, pb.ActionRequest_INSTALL)
+	return err
 }
 
 func setupHterm(el dom.Element) dom.Terminal {
@@ -396,19 +462,229 @@ func (c *graphController) Run(ctx context.Context) error {
 	}
 }
 
+func (c *graphController) WatchTelemetry(ctx context.Context, f func(*view.Telemetry)) error {
+	if !c.graph.Telemetry {
+		return nil
+	}
+	stream, err := c.client.WatchTelemetry(ctx, &pb.WatchTelemetryRequest{Graph: c.graph.FilePath})
+	if err != nil {
+		return err
+	}
+	for {
+		t, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		vt := &view.Telemetry{
+			Channels: make(map[string]*view.ChannelTelemetry, len(t.Channels)),
+			Nodes:    make(map[string]*view.NodeTelemetry, len(t.Nodes)),
+		}
+		for _, ch := range t.Channels {
+			vt.Channels[ch.Name] = &view.ChannelTelemetry{
+				Sent:     ch.Sent,
+				Received: ch.Received,
+				Len:      ch.Len,
+				Cap:      ch.Cap,
+				Blocked:  ch.Blocked,
+				Closed:   ch.Closed,
+			}
+		}
+		for _, n := range t.Nodes {
+			mn := c.graph.Nodes[n.Name]
+			if mn == nil {
+				continue
+			}
+			vt.Nodes[displayName(mn)] = &view.NodeTelemetry{
+				Running:  n.Running,
+				Finished: n.Finished,
+				Err:      n.Err,
+			}
+		}
+		f(vt)
+	}
+}
+
+// edited records the version of the graph resulting from an edit made by
+// this client.
+func (c *graphController) edited(resp *pb.EditResponse) {
+	switch v := resp.Version; {
+	case v <= c.version:
+		// The edit changed nothing.
+	case v == c.version+1:
+		c.version = v
+	default:
+		// Changes made by other clients are yet to arrive.
+		c.ownVersions[v] = true
+	}
+}
+
+func (c *graphController) WatchGraph(ctx context.Context, f func(*view.GraphChange)) error {
+	stream, err := c.client.WatchGraph(ctx, &pb.WatchGraphRequest{
+		Graph:   c.graph.FilePath,
+		Version: c.version,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		gc, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch {
+		case gc.Version <= c.version:
+			continue
+		case c.ownVersions[gc.Version]:
+			// Already applied locally.
+			delete(c.ownVersions, gc.Version)
+			c.version = gc.Version
+			continue
+		}
+		vc, err := c.apply(gc)
+		if err != nil {
+			return err
+		}
+		c.version = gc.Version
+		f(vc)
+	}
+}
+
+// apply updates the model with a change made elsewhere, and returns what
+// changed, with nodes named as the view names them.
+func (c *graphController) apply(gc *pb.GraphChange) (*view.GraphChange, error) {
+	vc := &view.GraphChange{
+		Nodes:    make(map[string]bool),
+		Channels: make(map[string]bool),
+	}
+	if p := gc.Properties; p != nil {
+		c.graph.Name = p.Name
+		c.graph.PackagePath = p.PackagePath
+		c.graph.IsCommand = p.IsCommand
+		c.graph.OutputDir = p.OutputDir
+		c.graph.UseContext = p.UseContext
+		c.graph.Telemetry = p.Telemetry
+		c.graph.Generics = p.Generics
+		c.graphNameTextInput.Set("value", p.Name)
+		c.graphPackagePathTextInput.Set("value", p.PackagePath)
+		c.graphIsCommandCheckbox.Set("checked", p.IsCommand)
+		c.graphOutputDirTextInput.Set("value", p.OutputDir)
+		c.graphUseContextCheckbox.Set("checked", p.UseContext)
+		c.graphTelemetryCheckbox.Set("checked", p.Telemetry)
+		c.graphGenericsCheckbox.Set("checked", p.Generics)
+		vc.Properties = true
+	}
+
+	for _, nc := range gc.Nodes {
+		old := c.graph.Nodes[nc.Name]
+		if old != nil {
+			vc.Nodes[displayName(old)] = true
+			delete(c.graph.Nodes, nc.Name)
+		}
+		cfg := nc.Config
+		if cfg == nil {
+			continue
+		}
+		part, err := (&model.PartJSON{
+			Part: cfg.PartCfg,
+			Type: cfg.PartType,
+		}).Unmarshal()
+		if err != nil {
+			return nil, fmt.Errorf("unmarshalling part of node %q: %v", nc.Name, err)
+		}
+		n := &model.Node{
+			Name:           cfg.Name,
+			Comment:        cfg.Comment,
+			Enabled:        cfg.Enabled,
+			Multiplicity:   cfg.Multiplicity,
+			Wait:           cfg.Wait,
+			Recover:        cfg.Recover,
+			Restart:        model.RestartPolicy(cfg.Restart),
+			MaxRestarts:    uint(cfg.MaxRestarts),
+			RestartBackoff: cfg.RestartBackoff,
+			RestartMetrics: cfg.RestartMetrics,
+			Part:           part,
+			X:              cfg.X,
+			Y:              cfg.Y,
+		}
+		if old != nil {
+			n.Connections = old.Connections
+		} else {
+			// Pick up connections from unchanged channels, such as when
+			// the node was renamed.
+			n.Connections = make(map[string]string)
+			for _, ch := range c.graph.Channels {
+				for np := range ch.Pins {
+					if np.Node == n.Name {
+						n.Connections[np.Pin] = ch.Name
+					}
+				}
+			}
+		}
+		n.RefreshConnections()
+		c.graph.Nodes[n.Name] = n
+		vc.Nodes[displayName(n)] = true
+	}
+
+	for _, cc := range gc.Channels {
+		if old := c.graph.Channels[cc.Name]; old != nil {
+			for np := range old.Pins {
+				if n := c.graph.Nodes[np.Node]; n != nil && n.Connections[np.Pin] == cc.Name {
+					n.Connections[np.Pin] = "nil"
+				}
+			}
+			delete(c.graph.Channels, cc.Name)
+		}
+		vc.Channels[cc.Name] = true
+		cfg := cc.Config
+		if cfg == nil {
+			continue
+		}
+		ch := &model.Channel{
+			Name:     cfg.Name,
+			Capacity: int(cfg.Cap),
+			Pins:     make(map[model.NodePin]struct{}, len(cfg.Pins)),
+		}
+		for _, np := range cfg.Pins {
+			ch.AddPin(np.Node, np.Pin)
+			if n := c.graph.Nodes[np.Node]; n != nil {
+				n.Connections[np.Pin] = ch.Name
+			}
+		}
+		c.graph.Channels[ch.Name] = ch
+	}
+	return vc, nil
+}
+
 func (c *graphController) Commit(ctx context.Context) error {
 	req := &pb.SetGraphPropertiesRequest{
 		Graph:       c.graph.FilePath,
+		Version:     c.version,
 		Name:        c.graphNameTextInput.Get("value").String(),
 		PackagePath: c.graphPackagePathTextInput.Get("value").String(),
 		IsCommand:   c.graphIsCommandCheckbox.Get("checked").Bool(),
+		OutputDir:   c.graphOutputDirTextInput.Get("value").String(),
+		UseContext:  c.graphUseContextCheckbox.Get("checked").Bool(),
+		Telemetry:   c.graphTelemetryCheckbox.Get("checked").Bool(),
+		Generics:    c.graphGenericsCheckbox.Get("checked").Bool(),
 	}
-	if _, err := c.client.SetGraphProperties(ctx, req); err != nil {
+	resp, err := c.client.SetGraphProperties(ctx, req)
+	if err != nil {
 		return err
 	}
+	c.edited(resp)
 	c.graph.Name = req.Name
 	c.graph.PackagePath = req.PackagePath
 	c.graph.IsCommand = req.IsCommand
+	c.graph.OutputDir = req.OutputDir
+	c.graph.UseContext = req.UseContext
+	c.graph.Telemetry = req.Telemetry
+	c.graph.Generics = req.Generics
 	return nil
 }
 
@@ -17,6 +17,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/google/shenzhen-go/client/view"
 	"github.com/google/shenzhen-go/dom"
@@ -43,6 +44,11 @@ type nodeSharedOutlets struct {
 	inputEnabled      dom.Element
 	inputMultiplicity dom.Element
 	inputWait         dom.Element
+	inputRecover      dom.Element
+	inputRestart      dom.Element
+	inputMaxRestarts  dom.Element
+	inputBackoff      dom.Element
+	inputMetrics      dom.Element
 	partEditors       map[string]*partEditor
 }
 
@@ -56,11 +62,15 @@ type nodeController struct {
 	subpanel *subpanel // remember most recent subpanel for each node
 }
 
-func (c *nodeController) Name() string {
-	if c.node.Multiplicity == "1" {
-		return c.node.Name
+func (c *nodeController) Name() string { return displayName(c.node) }
+
+// displayName is the name of the node as shown in the diagram, which is
+// also how the view refers to it.
+func displayName(n *model.Node) string {
+	if n.Multiplicity == "1" {
+		return n.Name
 	}
-	return fmt.Sprintf("%s (×%s)", c.node.Name, c.node.Multiplicity)
+	return fmt.Sprintf("%s (×%s)", n.Name, n.Multiplicity)
 }
 
 func (c *nodeController) Position() (x, y float64) { return c.node.X, c.node.Y }
@@ -80,14 +90,16 @@ func (c *nodeController) Pins(f func(view.PinController, string)) {
 }
 
 func (c *nodeController) Delete(ctx context.Context) error {
-	_, err := c.client.SetNode(ctx, &pb.SetNodeRequest{
-		Graph: c.graph.FilePath,
-		Node:  c.node.Name,
+	resp, err := c.client.SetNode(ctx, &pb.SetNodeRequest{
+		Graph:   c.graph.FilePath,
+		Node:    c.node.Name,
+		Version: c.gc.version,
 	})
 	if err != nil {
 		// TODO: contextualise
 		return err
 	}
+	c.gc.edited(resp)
 	c.graph.DeleteNode(c.node, true)
 	c.node = nil
 	return nil
@@ -101,45 +113,68 @@ func (c *nodeController) Commit(ctx context.Context) error {
 	if err != nil {
 		return err // TODO: contextualise
 	}
+	maxRestarts, err := strconv.ParseUint(c.sharedOutlets.inputMaxRestarts.Get("value").String(), 10, 32)
+	if err != nil {
+		return err // TODO: contextualise
+	}
+	restart := model.RestartNever
+	if c.sharedOutlets.inputRestart.Get("checked").Bool() {
+		restart = model.RestartOnPanic
+	}
 	cfg := &pb.NodeConfig{
-		Name:         c.sharedOutlets.inputName.Get("value").String(),
-		Comment:      c.sharedOutlets.textareaComment.Get("value").String(),
-		Enabled:      c.sharedOutlets.inputEnabled.Get("checked").Bool(),
-		Multiplicity: c.sharedOutlets.inputMultiplicity.Get("value").String(),
-		Wait:         c.sharedOutlets.inputWait.Get("checked").Bool(),
-		PartCfg:      pj.Part,
-		PartType:     pj.Type,
-		X:            c.node.X,
-		Y:            c.node.Y,
+		Name:           c.sharedOutlets.inputName.Get("value").String(),
+		Comment:        c.sharedOutlets.textareaComment.Get("value").String(),
+		Enabled:        c.sharedOutlets.inputEnabled.Get("checked").Bool(),
+		Multiplicity:   c.sharedOutlets.inputMultiplicity.Get("value").String(),
+		Wait:           c.sharedOutlets.inputWait.Get("checked").Bool(),
+		Recover:        c.sharedOutlets.inputRecover.Get("checked").Bool(),
+		Restart:        string(restart),
+		MaxRestarts:    uint32(maxRestarts),
+		RestartBackoff: c.sharedOutlets.inputBackoff.Get("value").String(),
+		RestartMetrics: c.sharedOutlets.inputMetrics.Get("checked").Bool(),
+		PartCfg:        pj.Part,
+		PartType:       pj.Type,
+		X:              c.node.X,
+		Y:              c.node.Y,
 	}
 	req := &pb.SetNodeRequest{
-		Graph:  c.graph.FilePath,
-		Node:   c.node.Name,
-		Config: cfg,
+		Graph:   c.graph.FilePath,
+		Node:    c.node.Name,
+		Config:  cfg,
+		Version: c.gc.version,
 	}
-	if _, err := c.client.SetNode(ctx, req); err != nil {
+	resp, err := c.client.SetNode(ctx, req)
+	if err != nil {
 		return err // TODO: contextualise
 	}
+	c.gc.edited(resp)
 	// Update local copy, since these were read at save time.
 	c.graph.RenameNode(c.node, cfg.Name)
 	c.node.Comment = cfg.Comment
 	c.node.Enabled = cfg.Enabled
 	c.node.Multiplicity = cfg.Multiplicity
 	c.node.Wait = cfg.Wait
+	c.node.Recover = cfg.Recover
+	c.node.Restart = restart
+	c.node.MaxRestarts = uint(cfg.MaxRestarts)
+	c.node.RestartBackoff = cfg.RestartBackoff
+	c.node.RestartMetrics = cfg.RestartMetrics
 	c.node.RefreshConnections()
 	return nil
 }
 
 func (c *nodeController) SetPosition(ctx context.Context, x, y float64) error {
-	_, err := c.client.SetPosition(ctx, &pb.SetPositionRequest{
-		Graph: c.graph.FilePath,
-		Node:  c.node.Name,
-		X:     x,
-		Y:     y,
+	resp, err := c.client.SetPosition(ctx, &pb.SetPositionRequest{
+		Graph:   c.graph.FilePath,
+		Node:    c.node.Name,
+		X:       x,
+		Y:       y,
+		Version: c.gc.version,
 	})
 	if err != nil {
 		return err // TODO: contextualise
 	}
+	c.gc.edited(resp)
 	c.node.X, c.node.Y = x, y
 	return nil
 }
@@ -148,6 +183,17 @@ type focusable interface {
 	GainFocus()
 }
 
+// codeHighlighter is implemented by parts whose editors can point out a
+// line of their code.
+type codeHighlighter interface {
+	// CodePanel returns the name of the panel for editing the section,
+	// or "" if there isn't one.
+	CodePanel(model.CodeSection) string
+
+	// HighlightCode shows the message at a line of the section.
+	HighlightCode(s model.CodeSection, line int, message string)
+}
+
 func (c *nodeController) GainFocus() {
 	c.gc.showRHSPanel(c.gc.nodePropertiesPanel)
 
@@ -156,6 +202,11 @@ func (c *nodeController) GainFocus() {
 	c.sharedOutlets.inputEnabled.Set("checked", c.node.Enabled)
 	c.sharedOutlets.inputMultiplicity.Set("value", c.node.Multiplicity)
 	c.sharedOutlets.inputWait.Set("checked", c.node.Wait)
+	c.sharedOutlets.inputRecover.Set("checked", c.node.Recover)
+	c.sharedOutlets.inputRestart.Set("checked", c.node.Restart == model.RestartOnPanic)
+	c.sharedOutlets.inputMaxRestarts.Set("value", c.node.MaxRestarts)
+	c.sharedOutlets.inputBackoff.Set("value", c.node.RestartBackoff)
+	c.sharedOutlets.inputMetrics.Set("checked", c.node.RestartMetrics)
 	// Hide all parteditor links except for this parttype
 	for k, e := range c.sharedOutlets.partEditors {
 		if k == c.node.Part.TypeKey() {
@@ -167,6 +218,21 @@ func (c *nodeController) GainFocus() {
 	c.showSubpanel(c.subpanel)
 }
 
+// ShowCode shows a line of the code of the node, if the part can.
+func (c *nodeController) ShowCode(section string, line int, message string) {
+	h, ok := c.node.Part.(codeHighlighter)
+	if !ok {
+		return
+	}
+	s := model.CodeSection(section)
+	p := h.CodePanel(s)
+	if p == "" {
+		return
+	}
+	c.ShowPartSubpanel(p)
+	h.HighlightCode(s, line, message)
+}
+
 func (c *nodeController) ShowMetadataSubpanel() {
 	c.showSubpanel(c.sharedOutlets.subpanelMetadata)
 }
@@ -45,6 +45,7 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	gc := controller.NewGraphController(doc, g, client)
+	version := uint64(js.Global.Get("graphVersion").Int64())
+	gc := controller.NewGraphController(doc, g, version, client)
 	view.Setup(doc, gc)
 }
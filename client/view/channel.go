@@ -41,6 +41,8 @@ type Channel struct {
 	potentialPin       *Pin        // considering attaching to this pin
 	subsumeInto        *Channel    // considering merging with this channel
 	presubsumption     map[*Pin]struct{}
+
+	telemetryText, telemetryTextNode dom.Element // shows telemetry while running
 }
 
 // MakeElements recreates elements for this channel and adds them to the parent.
@@ -67,7 +69,14 @@ func (c *Channel) MakeElements(doc dom.Document, parent dom.Element) {
 	c.dragCirc.ClassList().Add("draggable")
 	c.hideDrag()
 
-	c.Group.AddChildren(c.steiner, c.dragLine, c.dragCirc)
+	c.telemetryTextNode = doc.MakeTextNode("")
+	c.telemetryText = doc.MakeSVGElement("text").
+		SetAttribute("text-anchor", "middle").
+		AddChildren(c.telemetryTextNode)
+	c.telemetryText.ClassList().Add("telemetry")
+	c.telemetryText.Hide()
+
+	c.Group.AddChildren(c.steiner, c.dragLine, c.dragCirc, c.telemetryText)
 }
 
 // Pt implements Pointer.
@@ -395,6 +404,9 @@ func (c *Channel) layout(additional Pointer) {
 	c.dragLine.
 		SetAttribute("x2", real(c.visual)).
 		SetAttribute("y2", imag(c.visual))
+	c.telemetryText.
+		SetAttribute("x", real(c.visual)).
+		SetAttribute("y", imag(c.visual)-2*pinRadius)
 	for _, r := range c.Pins {
 		r.Reroute()
 	}
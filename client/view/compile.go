@@ -0,0 +1,33 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view
+
+// CompileError is an error from building the program, in the code of a node.
+type CompileError struct {
+	Node    string // name shown in the diagram
+	Section string // "head", "body", or "tail"
+	Line    int    // within the section, counting from 1
+	Message string
+}
+
+// showCompileError selects the node with the error and shows the line.
+func (g *Graph) showCompileError(ce *CompileError) {
+	n := g.Nodes[ce.Node]
+	if n == nil {
+		return
+	}
+	g.view.changeSelection(n)
+	n.nc.ShowCode(ce.Section, ce.Line, ce.Message)
+}
@@ -36,10 +36,15 @@ type GraphController interface {
 	// Action links
 	Save(ctx context.Context) error
 	Revert(ctx context.Context) error
+	Undo(ctx context.Context) error
+	Redo(ctx context.Context) error
+	Check(ctx context.Context) error
 	Generate(ctx context.Context) error
-	Build(ctx context.Context) error
+	Build(ctx context.Context) ([]*CompileError, error)
 	Install(ctx context.Context) error
 	Run(ctx context.Context) error
+	WatchTelemetry(ctx context.Context, f func(*Telemetry)) error
+	WatchGraph(ctx context.Context, f func(*GraphChange)) error
 	PreviewGo()
 	PreviewRawGo()
 	PreviewJSON()
@@ -72,6 +77,7 @@ type NodeController interface {
 	GainFocus()
 	ShowMetadataSubpanel()
 	ShowPartSubpanel(name string)
+	ShowCode(section string, line int, message string)
 
 	Commit(ctx context.Context) error
 	Delete(ctx context.Context) error
@@ -39,15 +39,28 @@ func (c fakeGraphController) CreateNode(ctx context.Context, partType string) (N
 func (c fakeGraphController) Commit(ctx context.Context) error   { return nil }
 func (c fakeGraphController) Save(ctx context.Context) error     { return nil }
 func (c fakeGraphController) Revert(ctx context.Context) error   { return nil }
+func (c fakeGraphController) Undo(ctx context.Context) error     { return nil }
+func (c fakeGraphController) Redo(ctx context.Context) error     { return nil }
+func (c fakeGraphController) Check(ctx context.Context) error    { return nil }
 func (c fakeGraphController) Generate(ctx context.Context) error { return nil }
-func (c fakeGraphController) Build(ctx context.Context) error    { return nil }
-func (c fakeGraphController) Install(ctx context.Context) error  { return nil }
-func (c fakeGraphController) Run(ctx context.Context) error      { return nil }
-func (c fakeGraphController) PreviewGo()                         {}
-func (c fakeGraphController) PreviewRawGo()                      {}
-func (c fakeGraphController) PreviewJSON()                       {}
-func (c fakeGraphController) HelpLicenses()                      {}
-func (c fakeGraphController) HelpAbout()                         {}
+func (c fakeGraphController) Build(ctx context.Context) ([]*CompileError, error) {
+	return nil, nil
+}
+func (c fakeGraphController) Install(ctx context.Context) error { return nil }
+func (c fakeGraphController) Run(ctx context.Context) error     { return nil }
+func (c fakeGraphController) PreviewGo()                        {}
+func (c fakeGraphController) PreviewRawGo()                     {}
+func (c fakeGraphController) PreviewJSON()                      {}
+func (c fakeGraphController) HelpLicenses()                     {}
+func (c fakeGraphController) HelpAbout()                        {}
+
+func (c fakeGraphController) WatchTelemetry(ctx context.Context, f func(*Telemetry)) error {
+	return nil
+}
+
+func (c fakeGraphController) WatchGraph(ctx context.Context, f func(*GraphChange)) error {
+	return nil
+}
 
 type fakeNodeController struct{}
 
@@ -67,6 +80,7 @@ func (f fakeNodeController) Commit(context.Context) error
 func (f fakeNodeController) SetPosition(context.Context, float64, float64) error { return nil }
 func (f fakeNodeController) ShowMetadataSubpanel()                               {}
 func (f fakeNodeController) ShowPartSubpanel(string)                             {}
+func (f fakeNodeController) ShowCode(string, int, string)                        {}
 
 type fakePinController string
 
@@ -34,6 +34,16 @@ type Graph struct {
 
 	Nodes    map[string]*Node
 	Channels map[string]*Channel
+
+	pending *GraphChange // changes that arrived during a drag, for after the drop
+}
+
+// GraphChange describes a change to the graph made by another client, or by
+// undo or redo.
+type GraphChange struct {
+	Properties bool            // whether the graph properties changed
+	Nodes      map[string]bool // nodes changed, by name shown in the diagram
+	Channels   map[string]bool // channels changed, by name
 }
 
 func (g *Graph) nearestPoint(x, y float64) (dist float64, pt Pointer) {
@@ -58,6 +68,9 @@ func (g *Graph) nearestPoint(x, y float64) (dist float64, pt Pointer) {
 // goroutines because cannot block in callback
 func (g *Graph) save(e dom.Object)     { g.view.commitSelected(e); go g.reallySave() }
 func (g *Graph) revert(e dom.Object)   { g.view.commitSelected(e); go g.reallyRevert() }
+func (g *Graph) undo(dom.Object)       { go g.reallyUndo() }
+func (g *Graph) redo(dom.Object)       { go g.reallyRedo() }
+func (g *Graph) check(e dom.Object)    { g.view.commitSelected(e); go g.reallyCheck() }
 func (g *Graph) generate(e dom.Object) { g.view.commitSelected(e); go g.reallyGenerate() }
 func (g *Graph) build(e dom.Object)    { g.view.commitSelected(e); go g.reallyBuild() }
 func (g *Graph) install(e dom.Object)  { g.view.commitSelected(e); go g.reallyInstall() }
@@ -75,6 +88,24 @@ func (g *Graph) reallyRevert() {
 	}
 }
 
+func (g *Graph) reallyUndo() {
+	if err := g.gc.Undo(context.TODO()); err != nil {
+		g.errors.setError("Couldn't undo: " + err.Error())
+	}
+}
+
+func (g *Graph) reallyRedo() {
+	if err := g.gc.Redo(context.TODO()); err != nil {
+		g.errors.setError("Couldn't redo: " + err.Error())
+	}
+}
+
+func (g *Graph) reallyCheck() {
+	if err := g.gc.Check(context.TODO()); err != nil {
+		g.errors.setError("Couldn't check: " + err.Error())
+	}
+}
+
 func (g *Graph) reallyGenerate() {
 	if err := g.gc.Generate(context.TODO()); err != nil {
 		g.errors.setError("Couldn't generate: " + err.Error())
@@ -82,7 +113,11 @@ func (g *Graph) reallyGenerate() {
 }
 
 func (g *Graph) reallyBuild() {
-	if err := g.gc.Build(context.TODO()); err != nil {
+	ces, err := g.gc.Build(context.TODO())
+	if len(ces) > 0 {
+		g.showCompileError(ces[0])
+	}
+	if err != nil {
 		g.errors.setError("Couldn't build: " + err.Error())
 	}
 }
@@ -94,11 +129,94 @@ func (g *Graph) reallyInstall() {
 }
 
 func (g *Graph) reallyRun() {
-	if err := g.gc.Run(context.TODO()); err != nil {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	g.clearTelemetry()
+	go func() {
+		if err := g.gc.WatchTelemetry(ctx, g.showTelemetry); err != nil && ctx.Err() == nil {
+			g.errors.setError("Couldn't watch telemetry: " + err.Error())
+		}
+	}()
+	if err := g.gc.Run(ctx); err != nil {
 		g.errors.setError("Couldn't run: " + err.Error())
 	}
 }
 
+func (g *Graph) watch() {
+	if err := g.gc.WatchGraph(context.Background(), g.changed); err != nil {
+		g.errors.setError("Stopped watching for changes, reload to see others' edits: " + err.Error())
+	}
+}
+
+// changed updates the diagram after the graph was changed elsewhere.
+func (g *Graph) changed(c *GraphChange) {
+	if g.view.dragItem != nil {
+		g.pending = g.pending.merge(c)
+		return
+	}
+	g.refresh(c)
+}
+
+// merge returns a change combining c and d. c may be nil.
+func (c *GraphChange) merge(d *GraphChange) *GraphChange {
+	if c == nil {
+		return d
+	}
+	c.Properties = c.Properties || d.Properties
+	for n := range d.Nodes {
+		c.Nodes[n] = true
+	}
+	for ch := range d.Channels {
+		c.Channels[ch] = true
+	}
+	return c
+}
+
+// refresh rebuilds the diagram from the graph controller, keeping the same
+// item selected if it still exists. If the selected item was changed, it
+// regains focus so that the properties panel shows the change; otherwise the
+// panel is left alone, including any edits in progress.
+func (g *Graph) refresh(c *GraphChange) {
+	g.pending = nil
+
+	// Everything is replaced, so remember the selection by name.
+	var node, channel string
+	switch s := g.view.selectedItem.(type) {
+	case *Node:
+		node = s.nc.Name()
+	case *Channel:
+		channel = s.cc.Name()
+	}
+
+	g.MakeElements(g.doc, g.view.diagram)
+	g.view.hoverTip.BringToFront()
+
+	if n := g.Nodes[node]; n != nil {
+		g.view.selectedItem = n
+		n.Group.Element.ClassList().Add("selected")
+		if c.Nodes[node] {
+			n.nc.GainFocus()
+		}
+		return
+	}
+	if ch := g.Channels[channel]; ch != nil {
+		g.view.selectedItem = ch
+		ch.Group.ClassList().Add("selected")
+		for p := range ch.Pins {
+			p.selected()
+		}
+		if c.Channels[channel] {
+			ch.cc.GainFocus()
+		}
+		return
+	}
+	if g.view.selectedItem != g {
+		// The selected item no longer exists.
+		g.view.selectedItem = g
+		g.gainFocus()
+	}
+}
+
 func (g *Graph) commit(dom.Object) {
 	go g.reallyCommit() // cannot block in callback
 }
@@ -0,0 +1,95 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view
+
+import "fmt"
+
+// Telemetry is a snapshot of a running program, keyed by channel and node name.
+type Telemetry struct {
+	Channels map[string]*ChannelTelemetry
+	Nodes    map[string]*NodeTelemetry
+}
+
+// ChannelTelemetry describes the state of a channel in a running program.
+type ChannelTelemetry struct {
+	Sent, Received  uint64
+	Len, Cap        uint64
+	Blocked, Closed bool
+}
+
+// String summarises the channel state for display next to the channel.
+func (t *ChannelTelemetry) String() string {
+	s := fmt.Sprintf("%d → %d", t.Sent, t.Received)
+	if t.Cap > 0 {
+		s += fmt.Sprintf(" [%d/%d]", t.Len, t.Cap)
+	}
+	if t.Closed {
+		s += " (closed)"
+	}
+	return s
+}
+
+// NodeTelemetry describes the state of a node in a running program.
+type NodeTelemetry struct {
+	Running, Finished bool
+	Err               string
+}
+
+// showTelemetry overlays the telemetry on the diagram.
+func (g *Graph) showTelemetry(t *Telemetry) {
+	for name, c := range g.Channels {
+		c.showTelemetry(t.Channels[name])
+	}
+	for name, n := range g.Nodes {
+		n.showTelemetry(t.Nodes[name])
+	}
+}
+
+// clearTelemetry removes any telemetry overlay from the diagram.
+func (g *Graph) clearTelemetry() {
+	g.showTelemetry(&Telemetry{})
+}
+
+func (c *Channel) showTelemetry(t *ChannelTelemetry) {
+	cl := c.Group.Element.ClassList()
+	if t == nil {
+		c.telemetryText.Hide()
+		cl.Remove("blocked")
+		return
+	}
+	c.telemetryTextNode.Set("nodeValue", t.String())
+	c.telemetryText.Show()
+	if t.Blocked {
+		cl.Add("blocked")
+	} else {
+		cl.Remove("blocked")
+	}
+}
+
+func (n *Node) showTelemetry(t *NodeTelemetry) {
+	cl := n.Group.Element.ClassList()
+	cl.Remove("running", "finished", "failed")
+	if t == nil {
+		return
+	}
+	switch {
+	case t.Err != "":
+		cl.Add("failed")
+	case t.Running:
+		cl.Add("running")
+	case t.Finished:
+		cl.Add("finished")
+	}
+}
@@ -66,10 +66,19 @@ func Setup(doc dom.Document, gc GraphController) {
 		AddEventListener("mousemove", v.diagramMouseMove).
 		AddEventListener("mouseup", v.diagramMouseUp)
 
+	doc.AddEventListener("keydown", v.keyDown)
+	go v.graph.watch()
+
 	doc.ElementByID("graph-save").
 		AddEventListener("click", v.graph.save)
 	doc.ElementByID("graph-revert").
 		AddEventListener("click", v.graph.revert)
+	doc.ElementByID("graph-undo").
+		AddEventListener("click", v.graph.undo)
+	doc.ElementByID("graph-redo").
+		AddEventListener("click", v.graph.redo)
+	doc.ElementByID("graph-check").
+		AddEventListener("click", v.graph.check)
 	doc.ElementByID("graph-generate").
 		AddEventListener("click", v.graph.generate)
 	doc.ElementByID("graph-build").
@@ -95,8 +104,16 @@ func Setup(doc dom.Document, gc GraphController) {
 		AddEventListener("change", v.graph.commit)
 	doc.ElementByID("graph-prop-package-path").
 		AddEventListener("change", v.graph.commit)
+	doc.ElementByID("graph-prop-output-dir").
+		AddEventListener("change", v.graph.commit)
 	doc.ElementByID("graph-prop-is-command").
 		AddEventListener("change", v.graph.commit)
+	doc.ElementByID("graph-prop-use-context").
+		AddEventListener("change", v.graph.commit)
+	doc.ElementByID("graph-prop-telemetry").
+		AddEventListener("change", v.graph.commit)
+	doc.ElementByID("graph-prop-generics").
+		AddEventListener("change", v.graph.commit)
 
 	doc.ElementByID("channel-name").
 		AddEventListener("change", v.commitSelected)
@@ -114,6 +131,16 @@ func Setup(doc dom.Document, gc GraphController) {
 		AddEventListener("change", v.commitSelected)
 	doc.ElementByID("node-wait").
 		AddEventListener("change", v.commitSelected)
+	doc.ElementByID("node-recover").
+		AddEventListener("change", v.commitSelected)
+	doc.ElementByID("node-restart").
+		AddEventListener("change", v.commitSelected)
+	doc.ElementByID("node-max-restarts").
+		AddEventListener("change", v.commitSelected)
+	doc.ElementByID("node-restart-backoff").
+		AddEventListener("change", v.commitSelected)
+	doc.ElementByID("node-restart-metrics").
+		AddEventListener("change", v.commitSelected)
 
 	// TODO(josh): reinstate Clone and Convert-To-Code links
 	doc.ElementByID("node-delete-link").
@@ -143,6 +170,27 @@ func Setup(doc dom.Document, gc GraphController) {
 	}
 }
 
+// keyDown handles keyboard shortcuts that apply to the whole graph.
+func (v *View) keyDown(e dom.Object) {
+	if !e.Get("ctrlKey").Bool() && !e.Get("metaKey").Bool() {
+		return
+	}
+	if e.Get("key").String() != "z" && e.Get("key").String() != "Z" {
+		return
+	}
+	// Leave text fields and code editors to undo their own changes.
+	switch e.Get("target").Get("tagName").String() {
+	case "INPUT", "TEXTAREA", "SELECT":
+		return
+	}
+	e.Call("preventDefault")
+	if e.Get("shiftKey").Bool() {
+		v.graph.redo(e)
+	} else {
+		v.graph.undo(e)
+	}
+}
+
 func (v *View) showHoverTip(event dom.Object, tip string) {
 	v.hoverTip.
 		SetText(tip).
@@ -217,6 +265,9 @@ func (v *View) diagramMouseUp(e dom.Object) {
 	v.dragItem.drag(v.diagramCursorPos(e))
 	v.dragItem.drop()
 	v.dragItem = nil
+	if c := v.graph.pending; c != nil {
+		v.graph.refresh(c)
+	}
 }
 
 // changeSelection switches the selected item, calling loseFocus and gainFocus
@@ -0,0 +1,451 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/shenzhen-go/importer"
+	"github.com/google/shenzhen-go/model"
+	"github.com/google/shenzhen-go/server"
+)
+
+// command describes a subcommand of shenzhen-go.
+type command struct {
+	// Short is a one-line description used in the general usage.
+	Short string
+
+	// Help is the text printed by `shenzhen-go help [command]`.
+	Help string
+
+	// Args describes the arguments in the usage, if not "[files]".
+	Args string
+
+	// Headless, if set, does the work for a single loaded graph without
+	// starting the UI server. Messages should be written to out.
+	Headless func(out io.Writer, g *model.Graph) error
+}
+
+var commands = map[string]*command{
+	"build": {
+		Short: "generate and build Go packages",
+		Help: `Build generates the Go package for each graph, and then runs
+"go build" on the package.`,
+		Headless: server.Build,
+	},
+	"diff": {
+		Short: "print the changes between two graphs",
+		Help: `Diff prints the changes from the first graph to the second by node and
+channel, rather than by lines of JSON: nodes and channels added, removed, or
+renamed, and changes to their properties, part configuration, connections,
+and capacities. Node positions are not compared.
+
+The exit status is 0 if there are no changes, 1 if there are changes, and 2
+if there was trouble.`,
+		Args: "old new",
+	},
+	"dot": {
+		Short: "print graphs in the Graphviz DOT language",
+		Help: `Dot prints each graph in the Graphviz DOT language, for rendering
+with "dot -Tsvg" and similar. Nodes are labelled with their name and part
+type, channels with their name, type, and capacity. Disabled nodes are drawn
+with dashed outlines.`,
+		Headless: func(out io.Writer, g *model.Graph) error { return g.WriteDOTTo(out) },
+	},
+	"edit": {
+		Short: "launch a Shenzhen Go server and open the editor interface",
+		Help: `Edit launches a Shenzhen Go server and opens the editor interface
+for each file. With no files, the editor opens at the current directory.`,
+	},
+	"fmt": {
+		Short: "rewrite graph files in canonical form",
+		Help: `Fmt rewrites each graph file in canonical JSON, so that files only
+change when the graph does. In canonical form, keys are sorted, node positions
+are rounded to whole pixels, and code is saved as lines without trailing
+whitespace.
+
+Flags:
+
+  -d  print diffs to canonical form instead of rewriting files
+  -l  list files that are not in canonical form instead of rewriting them`,
+		Args: "[-d] [-l] [files]",
+	},
+	"generate": {
+		Short: "generate Go packages",
+		Help: `Generate checks each graph for problems, and then writes the
+generated Go source code for the graph into the package directory.`,
+		Headless: func(out io.Writer, g *model.Graph) error {
+			_, err := server.GeneratePackage(out, g)
+			return err
+		},
+	},
+	"import": {
+		Short: "make a graph from a Go function",
+		Help: `Import makes a graph from a hand-written Go function that makes
+channels and starts goroutines, and prints the graph as JSON. Each channel
+made with make(chan T, n) becomes a channel, and each go statement becomes a
+Code node, with pins from the channel parameters of the goroutine.`,
+		Args: "file.go function",
+	},
+	"install": {
+		Short: "generate and install Go packages",
+		Help: `Install generates the Go package for each graph, and then runs
+"go install" on the package.`,
+		Headless: server.Install,
+	},
+	"mermaid": {
+		Short: "print graphs as Mermaid flowcharts",
+		Help: `Mermaid prints each graph as a Mermaid flowchart, for embedding in
+Markdown documents. Nodes are labelled with their name and part type,
+channels with their name, type, and capacity. Disabled nodes are drawn with
+dashed outlines.`,
+		Headless: func(out io.Writer, g *model.Graph) error { return g.WriteMermaidTo(out) },
+	},
+	"merge": {
+		Short: "merge changes to a graph, as a git merge driver",
+		Help: `Merge merges the changes from base to theirs into ours, and writes the
+result to ours. Where both changed the same thing (such as a node property,
+or the channel connected to a pin) differently, our version is kept and the
+conflict is printed.
+
+The exit status is 0 if the merge was clean, 1 if there were conflicts, and
+2 if there was trouble. To use it as a git merge driver, add this to your git
+config:
+
+  [merge "szgo"]
+    name = Shenzhen Go graph merge
+    driver = shenzhen-go merge %O %A %B
+
+and this to .gitattributes:
+
+  *.szgo merge=szgo`,
+		Args: "base ours theirs",
+	},
+	"migrate": {
+		Short: "upgrade graph files to the current format",
+		Help: fmt.Sprintf(`Migrate upgrades each graph file written by an older version of
+Shenzhen Go to the current format (version %d), and rewrites the file in
+place. Files already in the current format are left alone.
+
+Other commands upgrade graphs as they are loaded, but do not save them.`, model.FormatVersion),
+		Headless: migrateGraph,
+	},
+	"run": {
+		Short: "generate Go package and run binaries",
+		Help: `Run generates the Go package for each graph, and then runs it
+with "go run". Graphs that are not commands are run using a temporary
+main package that calls Run, unless they have ports. Standard input and
+output are connected to the program. The graphs are run one after another.`,
+		Headless: runGraph,
+	},
+	"serve": {
+		Short: "launch a Shenzhen Go server",
+		Help: `Serve launches a Shenzhen Go server without opening the editor
+interface.`,
+	},
+	"test": {
+		Short: "generate Go packages and test them",
+		Help: fmt.Sprintf(`Test generates the Go package for each graph, together with a test
+harness in %s, and then runs "go test" on the package. Only library
+graphs with ports can be tested.
+
+The harness declares TestCase, with a field for each port: the values to
+send to an input port, or the values expected from an output port. Each
+input port is closed after its values are sent. RunTestCases runs the graph
+for each TestCase as a subtest, and fails it if the expected values don't
+arrive before the timeout (10 seconds, unless the TestCase has a Timeout).
+
+If %s doesn't exist, it is written with an example TestCase to fill in.
+It is not overwritten after that.`, model.TestHarnessFileName, model.TestScaffoldFileName),
+		Headless: server.Test,
+	},
+}
+
+func runGraph(out io.Writer, g *model.Graph) error {
+	cmd, cleanup, err := server.GenerateRunner(context.Background(), out, g)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return cmd.Run()
+}
+
+func migrateGraph(out io.Writer, g *model.Graph) error {
+	from, ok := g.Migrated()
+	if !ok {
+		fmt.Fprintf(out, "%s: already format version %d\n", g.FilePath, model.FormatVersion)
+		return nil
+	}
+	if err := server.SaveJSONFile(g); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s: migrated from format version %d to %d\n", g.FilePath, from, model.FormatVersion)
+	return nil
+}
+
+// loadGraphFile loads a graph from a file.
+func loadGraphFile(path string) (*model.Graph, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return model.LoadJSON(f, path, path)
+}
+
+// runHeadless runs the named command on each file, and returns an exit code.
+func runHeadless(name string, files []string) int {
+	cmd := commands[name]
+	if len(files) == 0 {
+		fmt.Fprintf(os.Stderr, "%s: no graph files given\n\n", name)
+		commandHelp(name)
+		return 2
+	}
+	failed := 0
+	for _, fp := range files {
+		g, err := loadGraphFile(fp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: loading %s: %v\n", name, fp, err)
+			failed++
+			continue
+		}
+		if from, ok := g.Migrated(); ok && name != "migrate" {
+			fmt.Fprintf(os.Stderr, "%s: warning: %s is in format version %d; use \"%s migrate\" to upgrade it\n", name, fp, from, os.Args[0])
+		}
+		if err := cmd.Headless(os.Stdout, g); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s: %v\n", name, fp, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%s: %d of %d graphs failed\n", name, failed, len(files))
+		return 1
+	}
+	return 0
+}
+
+// runFmt rewrites graph files in canonical form, or reports the files that
+// aren't, and returns an exit code.
+func runFmt(args []string) int {
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	diff := fs.Bool("d", false, "print diffs instead of rewriting files")
+	list := fs.Bool("l", false, "list files instead of rewriting them")
+	fs.Usage = func() { commandHelp("fmt") }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	files := fs.Args()
+	if len(files) == 0 {
+		fmt.Fprintf(os.Stderr, "fmt: no graph files given\n\n")
+		commandHelp("fmt")
+		return 2
+	}
+	failed := 0
+	for _, fp := range files {
+		if err := fmtFile(fp, *list, *diff); err != nil {
+			fmt.Fprintf(os.Stderr, "fmt: %s: %v\n", fp, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// fmtFile rewrites a graph file in canonical form. If list or diff are set,
+// it prints the file name or a diff instead, if the file isn't canonical.
+func fmtFile(fp string, list, diff bool) error {
+	orig, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return err
+	}
+	g, err := model.LoadJSON(bytes.NewReader(orig), fp, fp)
+	if err != nil {
+		return err
+	}
+	buf := new(bytes.Buffer)
+	if err := g.WriteJSONTo(buf); err != nil {
+		return err
+	}
+	if bytes.Equal(orig, buf.Bytes()) {
+		return nil
+	}
+	if list {
+		fmt.Println(fp)
+	}
+	if diff {
+		d, err := diffFiles(fp, orig, buf.Bytes())
+		if err != nil {
+			return err
+		}
+		os.Stdout.Write(d)
+	}
+	if list || diff {
+		return nil
+	}
+	return server.SaveJSONFile(g)
+}
+
+// diffFiles returns a unified diff between two versions of a file, using
+// the system diff.
+func diffFiles(name string, a, b []byte) ([]byte, error) {
+	dir, err := ioutil.TempDir("", "shenzhen-go-fmt")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	fa, fb := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	if err := ioutil.WriteFile(fa, a, 0600); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(fb, b, 0600); err != nil {
+		return nil, err
+	}
+	out, err := exec.Command("diff", "-u", "--label", name+".orig", "--label", name, fa, fb).Output()
+	if len(out) > 0 {
+		// diff exits with status 1 when the files differ.
+		err = nil
+	}
+	return out, err
+}
+
+// runDiff prints the changes between two graph files, and returns an exit
+// code.
+func runDiff(args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintf(os.Stderr, "diff: want two graph files\n\n")
+		commandHelp("diff")
+		return 2
+	}
+	a, err := loadGraphFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "diff: loading %s: %v\n", args[0], err)
+		return 2
+	}
+	b, err := loadGraphFile(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "diff: loading %s: %v\n", args[1], err)
+		return 2
+	}
+	cs, err := model.Diff(a, b)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+		return 2
+	}
+	for _, c := range cs {
+		fmt.Println(c)
+	}
+	if len(cs) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runMerge merges graph files, writing the result over ours, and returns an
+// exit code.
+func runMerge(args []string) int {
+	if len(args) != 3 {
+		fmt.Fprintf(os.Stderr, "merge: want base, ours, and theirs graph files\n\n")
+		commandHelp("merge")
+		return 2
+	}
+	gs := make([]*model.Graph, len(args))
+	for i, fp := range args {
+		g, err := loadGraphFile(fp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "merge: loading %s: %v\n", fp, err)
+			return 2
+		}
+		gs[i] = g
+	}
+	g, cs, err := model.Merge(gs[0], gs[1], gs[2])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "merge: %v\n", err)
+		return 2
+	}
+	g.FilePath = args[1]
+	if err := server.SaveJSONFile(g); err != nil {
+		fmt.Fprintf(os.Stderr, "merge: saving %s: %v\n", args[1], err)
+		return 2
+	}
+	if len(cs) == 0 {
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "merge: %d conflicts; kept ours:\n", len(cs))
+	for _, c := range cs {
+		fmt.Fprintf(os.Stderr, "  %v\n", c)
+	}
+	return 1
+}
+
+// runImport imports a Go function and prints the graph, and returns an exit
+// code.
+func runImport(args []string) int {
+	if len(args) != 2 {
+		fmt.Fprintf(os.Stderr, "import: want a file and a function name\n\n")
+		commandHelp("import")
+		return 2
+	}
+	g, err := importer.Import(args[0], nil, args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import: %s: %v\n", args[0], err)
+		return 1
+	}
+	if err := g.WriteJSONTo(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "import: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// commandHelp prints help for a command, and returns false if the command
+// is unknown.
+func commandHelp(name string) bool {
+	cmd := commands[name]
+	if cmd == nil {
+		return false
+	}
+	args := cmd.Args
+	if args == "" {
+		args = "[files]"
+	}
+	fmt.Fprintf(os.Stderr, "Usage:\n\n  %s %s %s\n\n%s\n", os.Args[0], name, args, cmd.Help)
+	return true
+}
+
+// commandSummaries returns a line for each command, sorted by name.
+func commandSummaries() string {
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	buf := new(bytes.Buffer)
+	for _, n := range names {
+		fmt.Fprintf(buf, "  %-9s %s\n", n, commands[n].Short)
+	}
+	return buf.String()
+}
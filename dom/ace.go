@@ -68,6 +68,12 @@ func (e *AceEditor) SetTheme(theme string) *AceEditor {
 	return e
 }
 
+// GotoLine moves the cursor to a line (counting from 1), scrolling the
+// editor to show it.
+func (e *AceEditor) GotoLine(line int) {
+	e.Call("gotoLine", line)
+}
+
 // AceSession is an Ace editor session.
 type AceSession struct {
 	Object
@@ -107,3 +113,19 @@ func (s *AceSession) SetValue(contents string) {
 func (s *AceSession) Value() string {
 	return s.Call("getValue").String()
 }
+
+// SetErrorAnnotation marks a line (counting from 1) in the gutter as having
+// an error, replacing any other annotations.
+func (s *AceSession) SetErrorAnnotation(line int, text string) {
+	s.Call("setAnnotations", []map[string]interface{}{{
+		"row":    line - 1,
+		"column": 0,
+		"text":   text,
+		"type":   "error",
+	}})
+}
+
+// ClearAnnotations removes all annotations.
+func (s *AceSession) ClearAnnotations() {
+	s.Call("clearAnnotations")
+}
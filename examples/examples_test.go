@@ -21,15 +21,11 @@ import (
 
 	"github.com/google/shenzhen-go/model"
 	_ "github.com/google/shenzhen-go/parts"
-	"github.com/google/shenzhen-go/source"
 )
 
 func TestLoadAndGoExamples(t *testing.T) {
-	gp, err := source.GoPath()
-	if err != nil {
-		t.Fatalf("GoPath() = error %v", err)
-	}
-	glob := filepath.Join(gp, "src", "github.com/google/shenzhen-go/examples/*.szgo")
+	// Tests run in the package directory, whether in GOPATH or module mode.
+	glob := "*.szgo"
 	exs, err := filepath.Glob(glob)
 	if err != nil {
 		t.Fatalf("Glob(%s) = error %v", glob, err)
@@ -51,6 +47,9 @@ func TestLoadAndGoExamples(t *testing.T) {
 			if _, err := g.Go(); err != nil {
 				t.Fatalf("Go() = error %v", err)
 			}
+			if _, err := g.GoFiles(); err != nil {
+				t.Fatalf("GoFiles() = error %v", err)
+			}
 		})
 	}
 }
@@ -0,0 +1,604 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package importer makes Shenzhen Go graphs out of hand-written Go code.
+package importer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/shenzhen-go/model"
+	"github.com/google/shenzhen-go/model/pin"
+	"github.com/google/shenzhen-go/parts"
+)
+
+// Layout of imported nodes, in rows by the flow of data.
+const (
+	layoutLeft    = 100
+	layoutTop     = 100
+	layoutColumn  = 250
+	layoutRow     = 150
+	maxLayoutRows = 100
+)
+
+// importer holds the state of a single import.
+type importer struct {
+	fset    *token.FileSet
+	src     []byte
+	file    *ast.File
+	fn      *ast.FuncDecl // the function being imported
+	funcs   map[string]*ast.FuncDecl
+	elems   map[string]string // channel name -> element type
+	g       *model.Graph
+	order   []string // node names, in order of go statements
+	counter map[string]int
+}
+
+// Import parses a Go source file, and makes a graph out of the function
+// named funcName. Each channel made in the function with make(chan T, n)
+// becomes a channel, and each go statement becomes a Code node. A go
+// statement may call a function declared in the same file, or a function
+// literal. Nodes for function literals are named "goroutine".
+//
+// Pins come from the channel parameters of the function: receive-only
+// parameters are inputs, and send-only parameters are outputs. The
+// direction of bidirectional channel parameters, and of channels used
+// directly by function literals, is worked out from how they are used.
+// Other arguments are assigned to their parameters in the Head of the node,
+// so may not use variables of the function. Go statements in loops are not
+// supported, since one node can't stand for several goroutines.
+//
+// As for parser.ParseFile, src may be nil, in which case the file named by
+// filename is read.
+func Import(filename string, src interface{}, funcName string) (*model.Graph, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	text, err := readSource(filename, src)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &importer{
+		fset:    fset,
+		src:     text,
+		file:    f,
+		funcs:   make(map[string]*ast.FuncDecl),
+		elems:   make(map[string]string),
+		g:       model.NewGraph("", "", f.Name.Name),
+		counter: make(map[string]int),
+	}
+	imp.g.Name = funcName
+	for _, d := range f.Decls {
+		if fd, ok := d.(*ast.FuncDecl); ok && fd.Recv == nil {
+			imp.funcs[fd.Name.Name] = fd
+		}
+	}
+	fd := imp.funcs[funcName]
+	if fd == nil {
+		return nil, fmt.Errorf("no function %q in %s", funcName, filename)
+	}
+	if err := imp.importFunc(fd); err != nil {
+		return nil, fmt.Errorf("%s: %v", funcName, err)
+	}
+	imp.layout()
+	return imp.g, nil
+}
+
+// readSource returns the source text that was parsed.
+func readSource(filename string, src interface{}) ([]byte, error) {
+	switch s := src.(type) {
+	case nil:
+		return ioutil.ReadFile(filename)
+	case string:
+		return []byte(s), nil
+	case []byte:
+		return s, nil
+	}
+	return nil, fmt.Errorf("unsupported source type %T", src)
+}
+
+// importFunc adds the channels and goroutines started by the function.
+func (imp *importer) importFunc(fd *ast.FuncDecl) error {
+	imp.fn = fd
+	// Find the channels first, so go statements can refer to them in any
+	// order.
+	var err error
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.GoStmt, *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			for i, rhs := range n.Rhs {
+				if i < len(n.Lhs) {
+					if id, ok := n.Lhs[i].(*ast.Ident); ok {
+						err = imp.addChannel(id.Name, rhs)
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, v := range n.Values {
+				if i < len(n.Names) {
+					err = imp.addChannel(n.Names[i].Name, v)
+				}
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt, *ast.RangeStmt:
+			// One node can't stand for the goroutines started by
+			// a loop.
+			if goStmt := findGoStmt(n); goStmt != nil {
+				err = fmt.Errorf("%s: go statement in a loop; import one and set the multiplicity of the node instead", imp.pos(goStmt))
+			}
+			return false
+		case *ast.GoStmt:
+			err = imp.addNode(n.Call)
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if len(imp.order) == 0 {
+		return fmt.Errorf("no go statements")
+	}
+	return nil
+}
+
+// addChannel adds a channel to the graph if the expression makes a channel.
+func (imp *importer) addChannel(name string, x ast.Expr) error {
+	call, ok := x.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil
+	}
+	if id, ok := call.Fun.(*ast.Ident); !ok || id.Name != "make" {
+		return nil
+	}
+	ct, ok := call.Args[0].(*ast.ChanType)
+	if !ok {
+		return nil
+	}
+	if name == "_" || name == "nil" {
+		return fmt.Errorf("%s: channels may not be named %q", imp.pos(call), name)
+	}
+	if _, exists := imp.g.Channels[name]; exists {
+		return fmt.Errorf("%s: channel %q is made more than once", imp.pos(call), name)
+	}
+	cap := 0
+	if len(call.Args) > 1 {
+		lit, ok := call.Args[1].(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return fmt.Errorf("%s: capacity of channel %q is not an integer literal", imp.pos(call), name)
+		}
+		c, err := strconv.ParseInt(lit.Value, 0, 0)
+		if err != nil {
+			return fmt.Errorf("%s: capacity of channel %q: %v", imp.pos(call), name, err)
+		}
+		cap = int(c)
+	}
+	imp.g.Channels[name] = &model.Channel{
+		Name:     name,
+		Capacity: cap,
+		Pins:     make(map[model.NodePin]struct{}),
+	}
+	imp.elems[name] = types.ExprString(ct.Value)
+	return nil
+}
+
+// nodePin is a pin on a node being imported.
+type nodePin struct {
+	name    string
+	def     *pin.Definition
+	channel string
+}
+
+// addNode adds a Code node for the function called by a go statement.
+func (imp *importer) addNode(call *ast.CallExpr) error {
+	var (
+		name  string
+		ftype *ast.FuncType
+		body  *ast.BlockStmt
+	)
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		fd := imp.funcs[fun.Name]
+		if fd == nil || fd.Body == nil {
+			return fmt.Errorf("%s: go statement calls %s, which is not a function in this file", imp.pos(call), fun.Name)
+		}
+		name, ftype, body = fun.Name, fd.Type, fd.Body
+	case *ast.FuncLit:
+		// "func" would make a node that can't be generated.
+		name, ftype, body = "goroutine", fun.Type, fun.Body
+	default:
+		return fmt.Errorf("%s: go statement calls %s; only functions in this file and function literals can be imported", imp.pos(call), types.ExprString(call.Fun))
+	}
+	if ftype.Results != nil && len(ftype.Results.List) > 0 {
+		return fmt.Errorf("%s: %s returns results", imp.pos(call), name)
+	}
+	if call.Ellipsis.IsValid() {
+		return fmt.Errorf("%s: variadic calls to %s are not supported", imp.pos(call), name)
+	}
+
+	// Match parameters with arguments.
+	var params []*ast.Ident
+	var ptypes []ast.Expr
+	for _, field := range ftype.Params.List {
+		if len(field.Names) == 0 {
+			return fmt.Errorf("%s: %s has unnamed parameters", imp.pos(call), name)
+		}
+		for _, id := range field.Names {
+			params = append(params, id)
+			ptypes = append(ptypes, field.Type)
+		}
+	}
+	if len(params) != len(call.Args) {
+		return fmt.Errorf("%s: %s takes %d arguments, but is called with %d", imp.pos(call), name, len(params), len(call.Args))
+	}
+
+	var pins []nodePin
+	var head []string
+	imports := make(map[string]bool)
+	for i, p := range params {
+		arg := call.Args[i]
+		ct, ok := ptypes[i].(*ast.ChanType)
+		if !ok {
+			if _, ok := ptypes[i].(*ast.Ellipsis); ok {
+				return fmt.Errorf("%s: variadic function %s is not supported", imp.pos(call), name)
+			}
+			if !uses(body, p.Name) {
+				continue
+			}
+			// The argument is copied into the node, which doesn't
+			// run inside the function.
+			if vs := imp.capturedVars(arg); len(vs) > 0 {
+				return fmt.Errorf("%s: argument %s to %s uses %s from %s; only literals and package-level values can be copied into the node", imp.pos(arg), types.ExprString(arg), name, strings.Join(vs, ", "), imp.g.Name)
+			}
+			head = append(head, fmt.Sprintf("var %s %s = %s", p.Name, types.ExprString(ptypes[i]), imp.text(arg)))
+			imp.addImports(imports, ptypes[i], arg)
+			continue
+		}
+		id, ok := arg.(*ast.Ident)
+		if !ok || imp.g.Channels[id.Name] == nil {
+			return fmt.Errorf("%s: argument %s to %s is not a channel made in %s", imp.pos(arg), types.ExprString(arg), name, imp.g.Name)
+		}
+		dir, err := imp.direction(ct.Dir, body, p.Name)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %v", imp.pos(call), name, err)
+		}
+		pins = append(pins, nodePin{
+			name:    p.Name,
+			def:     &pin.Definition{Name: p.Name, Type: types.ExprString(ct.Value), Direction: dir},
+			channel: id.Name,
+		})
+		imp.addImports(imports, ct.Value)
+	}
+
+	// Function literals may use channels from the enclosing function
+	// directly. Those become pins too. Other variables can't be used,
+	// because the node doesn't run inside the function.
+	if lit, ok := call.Fun.(*ast.FuncLit); ok {
+		if vs := imp.capturedVars(lit); len(vs) > 0 {
+			return fmt.Errorf("%s: function literal uses %s from %s; pass them as arguments instead", imp.pos(call), strings.Join(vs, ", "), imp.g.Name)
+		}
+		for _, cn := range imp.capturedChannels(body, params) {
+			dir, err := imp.direction(ast.SEND|ast.RECV, body, cn)
+			if err != nil {
+				return fmt.Errorf("%s: function literal: %v", imp.pos(call), err)
+			}
+			pins = append(pins, nodePin{
+				name:    cn,
+				def:     &pin.Definition{Name: cn, Type: imp.elems[cn], Direction: dir},
+				channel: cn,
+			})
+		}
+	}
+	imp.addImports(imports, body)
+
+	pm := make(pin.Map, len(pins))
+	for _, p := range pins {
+		pm[p.name] = p.def
+	}
+	var imps []string
+	for _, is := range imp.file.Imports {
+		if imports[importName(is)] {
+			imps = append(imps, imp.text(is))
+		}
+	}
+	sort.Strings(imps)
+
+	n := &model.Node{
+		Name:         imp.nodeName(name),
+		Enabled:      true,
+		Multiplicity: "1",
+		Wait:         true,
+		Part:         parts.NewCode(imps, strings.Join(head, "\n"), imp.blockText(body), "", pm),
+		Connections:  make(map[string]string, len(pins)),
+	}
+	for _, p := range pins {
+		n.Connections[p.name] = p.channel
+		imp.g.Channels[p.channel].Pins[model.NodePin{Node: n.Name, Pin: p.name}] = struct{}{}
+	}
+	n.RefreshConnections()
+	imp.g.Nodes[n.Name] = n
+	imp.order = append(imp.order, n.Name)
+	return nil
+}
+
+// direction decides the direction of a pin from the channel direction of
+// its parameter, or failing that, how it is used in the body.
+func (imp *importer) direction(dir ast.ChanDir, body ast.Node, name string) (pin.Direction, error) {
+	switch dir {
+	case ast.RECV:
+		return pin.Input, nil
+	case ast.SEND:
+		return pin.Output, nil
+	}
+	send, recv := channelUse(body, name)
+	switch {
+	case send && recv:
+		return "", fmt.Errorf("channel %s is both sent to and received from", name)
+	case send:
+		return pin.Output, nil
+	case recv:
+		return pin.Input, nil
+	}
+	return "", fmt.Errorf("can't tell whether channel %s is sent to or received from", name)
+}
+
+// channelUse reports whether the body sends to (or closes) the channel, and
+// whether it receives from the channel.
+func channelUse(body ast.Node, name string) (send, recv bool) {
+	is := func(x ast.Expr) bool {
+		id, ok := x.(*ast.Ident)
+		return ok && id.Name == name
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SendStmt:
+			send = send || is(n.Chan)
+		case *ast.UnaryExpr:
+			recv = recv || (n.Op == token.ARROW && is(n.X))
+		case *ast.RangeStmt:
+			recv = recv || is(n.X)
+		case *ast.CallExpr:
+			if fun, ok := n.Fun.(*ast.Ident); ok && fun.Name == "close" && len(n.Args) == 1 {
+				send = send || is(n.Args[0])
+			}
+		}
+		return true
+	})
+	return send, recv
+}
+
+// capturedChannels returns the names of channels of the graph used by a
+// function literal body, other than its parameters, in sorted order.
+func (imp *importer) capturedChannels(body ast.Node, params []*ast.Ident) []string {
+	shadowed := make(map[string]bool, len(params))
+	for _, p := range params {
+		shadowed[p.Name] = true
+	}
+	found := make(map[string]bool)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && !shadowed[id.Name] && imp.g.Channels[id.Name] != nil {
+			found[id.Name] = true
+		}
+		return true
+	})
+	names := make([]string, 0, len(found))
+	for cn := range found {
+		names = append(names, cn)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// capturedVars returns the names of variables and constants declared in
+// the function being imported, outside the node (a function literal or an
+// argument), that the node uses, other than channels of the graph, in
+// sorted order.
+func (imp *importer) capturedVars(node ast.Node) []string {
+	found := make(map[string]bool)
+	ast.Inspect(node, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || id.Obj == nil || imp.g.Channels[id.Name] != nil {
+			return true
+		}
+		if id.Obj.Kind != ast.Var && id.Obj.Kind != ast.Con {
+			return true
+		}
+		p := id.Obj.Pos()
+		inFunc := p >= imp.fn.Pos() && p < imp.fn.End()
+		inNode := p >= node.Pos() && p < node.End()
+		if inFunc && !inNode {
+			found[id.Name] = true
+		}
+		return true
+	})
+	names := make([]string, 0, len(found))
+	for n := range found {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// findGoStmt returns the first go statement in n, if any, other than
+// those in function literals.
+func findGoStmt(n ast.Node) *ast.GoStmt {
+	var found *ast.GoStmt
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.GoStmt:
+			if found == nil {
+				found = n
+			}
+		}
+		return found == nil
+	})
+	return found
+}
+
+// addImports notes the package names used in the nodes.
+func (imp *importer) addImports(used map[string]bool, nodes ...ast.Node) {
+	for _, n := range nodes {
+		ast.Inspect(n, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok {
+					used[id.Name] = true
+				}
+			}
+			return true
+		})
+	}
+}
+
+// importName returns the name an import is referred to by.
+func importName(is *ast.ImportSpec) string {
+	if is.Name != nil {
+		return is.Name.Name
+	}
+	path, err := strconv.Unquote(is.Path.Value)
+	if err != nil {
+		return ""
+	}
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// uses reports whether the identifier appears in the node.
+func uses(n ast.Node, name string) bool {
+	found := false
+	ast.Inspect(n, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// nodeName returns an unused node name based on base.
+func (imp *importer) nodeName(base string) string {
+	for {
+		imp.counter[base]++
+		name := base
+		if c := imp.counter[base]; c > 1 {
+			name = fmt.Sprintf("%s %d", base, c)
+		}
+		if _, exists := imp.g.Nodes[name]; !exists {
+			return name
+		}
+	}
+}
+
+// layout positions the nodes in rows, so that nodes reading from a channel
+// are below the nodes writing to it, as far as cycles allow.
+func (imp *importer) layout() {
+	row := make(map[string]int, len(imp.order))
+	for i := 0; i < maxLayoutRows; i++ {
+		changed := false
+		for _, c := range imp.g.Channels {
+			var from, to []string
+			for np := range c.Pins {
+				if imp.g.Nodes[np.Node].Part.Pins()[np.Pin].Direction == pin.Output {
+					from = append(from, np.Node)
+				} else {
+					to = append(to, np.Node)
+				}
+			}
+			for _, f := range from {
+				for _, t := range to {
+					if f != t && row[t] < row[f]+1 && row[f]+1 < len(imp.order) {
+						row[t] = row[f] + 1
+						changed = true
+					}
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	cols := make(map[int]int)
+	for _, nn := range imp.order {
+		n, r := imp.g.Nodes[nn], row[nn]
+		n.X = float64(layoutLeft + layoutColumn*cols[r])
+		n.Y = float64(layoutTop + layoutRow*r)
+		cols[r]++
+	}
+}
+
+// pos describes the position of a node in the source.
+func (imp *importer) pos(n ast.Node) string {
+	p := imp.fset.Position(n.Pos())
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// text returns the source text of a node.
+func (imp *importer) text(n ast.Node) string {
+	tf := imp.fset.File(n.Pos())
+	return string(imp.src[tf.Offset(n.Pos()):tf.Offset(n.End())])
+}
+
+// blockText returns the source text inside a block, without the braces,
+// and with the common indentation removed.
+func (imp *importer) blockText(b *ast.BlockStmt) string {
+	tf := imp.fset.File(b.Pos())
+	lines := strings.Split(strings.Trim(string(imp.src[tf.Offset(b.Lbrace)+1:tf.Offset(b.Rbrace)]), "\n"), "\n")
+	indent, first := "", true
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		li := l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+		if first {
+			indent, first = li, false
+			continue
+		}
+		for !strings.HasPrefix(li, indent) {
+			indent = indent[:len(indent)-1]
+		}
+	}
+	for i, l := range lines {
+		lines[i] = strings.TrimPrefix(l, indent)
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), " \t\n")
+}
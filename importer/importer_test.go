@@ -0,0 +1,250 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"go/ast"
+	goimporter "go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/google/shenzhen-go/model"
+	"github.com/google/shenzhen-go/model/pin"
+	"github.com/google/shenzhen-go/parts"
+)
+
+const pipelineSrc = `package pipeline
+
+import (
+	"fmt"
+	"strings"
+)
+
+func generate(n int, out chan<- int) {
+	for i := 0; i < n; i++ {
+		out <- i
+	}
+	close(out)
+}
+
+func square(in <-chan int, out chan int) {
+	for x := range in {
+		out <- x * x
+	}
+	close(out)
+}
+
+func Pipeline() {
+	nums := make(chan int)
+	squares := make(chan int, 10)
+	done := make(chan struct{})
+	go generate(5, nums)
+	go square(nums, squares)
+	go func() {
+		for s := range squares {
+			fmt.Println(strings.Repeat("*", s))
+		}
+		close(done)
+	}()
+	<-done
+}
+`
+
+func TestImport(t *testing.T) {
+	g, err := Import("pipeline.go", pipelineSrc, "Pipeline")
+	if err != nil {
+		t.Fatalf("Import() = %v, want nil", err)
+	}
+	if got, want := g.PackagePath, "pipeline"; got != want {
+		t.Errorf("PackagePath = %q, want %q", got, want)
+	}
+
+	for name, cap := range map[string]int{"nums": 0, "squares": 10, "done": 0} {
+		c := g.Channels[name]
+		if c == nil {
+			t.Errorf("channel %q missing", name)
+			continue
+		}
+		if c.Capacity != cap {
+			t.Errorf("channel %q capacity = %d, want %d", name, c.Capacity, cap)
+		}
+	}
+
+	tests := []struct {
+		node    string
+		pins    map[string]pin.Definition
+		conns   map[string]string
+		head    string
+		body    string
+		imports []string
+		y       float64
+	}{
+		{
+			node:  "generate",
+			pins:  map[string]pin.Definition{"out": {Type: "int", Direction: pin.Output}},
+			conns: map[string]string{"out": "nums"},
+			head:  "var n int = 5",
+			body:  "for i := 0; i < n; i++ {\n\tout <- i\n}\nclose(out)",
+			y:     100,
+		},
+		{
+			node:  "square",
+			pins:  map[string]pin.Definition{"in": {Type: "int", Direction: pin.Input}, "out": {Type: "int", Direction: pin.Output}},
+			conns: map[string]string{"in": "nums", "out": "squares"},
+			body:  "for x := range in {\n\tout <- x * x\n}\nclose(out)",
+			y:     250,
+		},
+		{
+			node:    "goroutine",
+			pins:    map[string]pin.Definition{"squares": {Type: "int", Direction: pin.Input}, "done": {Type: "struct{}", Direction: pin.Output}},
+			conns:   map[string]string{"squares": "squares", "done": "done"},
+			body:    "for s := range squares {\n\tfmt.Println(strings.Repeat(\"*\", s))\n}\nclose(done)",
+			imports: []string{`"fmt"`, `"strings"`},
+			y:       400,
+		},
+	}
+	for _, test := range tests {
+		n := g.Nodes[test.node]
+		if n == nil {
+			t.Errorf("node %q missing", test.node)
+			continue
+		}
+		code := n.Part.(*parts.Code)
+		for pn, want := range test.pins {
+			p := code.PinMap[pn]
+			if p == nil {
+				t.Errorf("node %q pin %q missing", test.node, pn)
+				continue
+			}
+			if p.Direction != want.Direction || p.Type != want.Type {
+				t.Errorf("node %q pin %q = %v %s, want %v %s", test.node, pn, p.Direction, p.Type, want.Direction, want.Type)
+			}
+			if got, want := n.Connections[pn], test.conns[pn]; got != want {
+				t.Errorf("node %q pin %q connected to %q, want %q", test.node, pn, got, want)
+			}
+			if _, ok := g.Channels[test.conns[pn]].Pins[model.NodePin{Node: test.node, Pin: pn}]; !ok {
+				t.Errorf("channel %q doesn't have pin %s.%s", test.conns[pn], test.node, pn)
+			}
+		}
+		if got := strings.Join(code.Head, "\n"); got != test.head {
+			t.Errorf("node %q head = %q, want %q", test.node, got, test.head)
+		}
+		if got := strings.Join(code.Body, "\n"); got != test.body {
+			t.Errorf("node %q body = %q, want %q", test.node, got, test.body)
+		}
+		if got, want := strings.Join(code.Imports, ","), strings.Join(test.imports, ","); got != want {
+			t.Errorf("node %q imports = %q, want %q", test.node, got, want)
+		}
+		if n.Y != test.y {
+			t.Errorf("node %q y = %v, want %v", test.node, n.Y, test.y)
+		}
+	}
+
+	if err := g.InferTypes(); err != nil {
+		t.Errorf("InferTypes() = %v, want nil", err)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	tests := []struct {
+		desc, src, want string
+	}{
+		{
+			desc: "missing function",
+			src:  "package p\n",
+			want: `no function "F"`,
+		},
+		{
+			desc: "no goroutines",
+			src:  "package p\nfunc F() {}\n",
+			want: "no go statements",
+		},
+		{
+			desc: "unknown function",
+			src:  "package p\nimport \"fmt\"\nfunc F() { go fmt.Println() }\n",
+			want: "only functions in this file",
+		},
+		{
+			desc: "channel not made here",
+			src:  "package p\nfunc g(c chan<- int) {}\nfunc F(c chan int) { go g(c) }\n",
+			want: "not a channel made in F",
+		},
+		{
+			desc: "unclear direction",
+			src:  "package p\nfunc g(c chan int) { h(c) }\nfunc h(chan int) {}\nfunc F() { c := make(chan int); go g(c) }\n",
+			want: "can't tell",
+		},
+		{
+			desc: "captured variable",
+			src:  "package p\nfunc F(n int) { c := make(chan int); go func() { for i := 0; i < n; i++ { c <- i } }(); <-c }\n",
+			want: "uses n from F",
+		},
+		{
+			desc: "argument uses a local",
+			src:  "package p\nfunc g(id int, c chan<- int) { c <- id }\nfunc F() { c := make(chan int); id := 1; go g(id+1, c); <-c }\n",
+			want: "uses id from F",
+		},
+		{
+			desc: "go statement in a loop",
+			src:  "package p\nfunc g(id int, c chan<- int) { c <- id }\nfunc F() { c := make(chan int); for i := 0; i < 4; i++ { go g(i, c) }; <-c }\n",
+			want: "go statement in a loop",
+		},
+		{
+			desc: "capacity expression",
+			src:  "package p\nfunc F(n int) { c := make(chan int, n); _ = c }\n",
+			want: "not an integer literal",
+		},
+	}
+	for _, test := range tests {
+		_, err := Import("p.go", test.src, "F")
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: Import() = %v, want error containing %q", test.desc, err, test.want)
+		}
+	}
+}
+
+func TestImportGenerates(t *testing.T) {
+	g, err := Import("pipeline.go", pipelineSrc, "Pipeline")
+	if err != nil {
+		t.Fatalf("Import() = %v, want nil", err)
+	}
+	for _, d := range g.Check() {
+		if d.Severity == model.SeverityError {
+			t.Errorf("g.Check() has error %v", d)
+		}
+	}
+	files, err := g.GoFiles()
+	if err != nil {
+		t.Fatalf("g.GoFiles() = error %v", err)
+	}
+
+	// The generated package should type-check.
+	fset := token.NewFileSet()
+	var fs []*ast.File
+	for name, src := range files {
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatalf("parsing %s: %v\n%s", name, err, src)
+		}
+		fs = append(fs, f)
+	}
+	conf := types.Config{Importer: goimporter.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check(g.PackagePath, fset, fs, nil); err != nil {
+		t.Errorf("type-checking the generated package: %v", err)
+	}
+}
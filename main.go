@@ -166,19 +166,14 @@ Usage:
   
 The (optional) commands are:
   
-  build     generate and build Go packages
-  edit      launch a Shenzhen Go server and open the editor interface
-  generate  generate Go packages
-  install   generate and install Go packages
-  run       generate Go package and run binaries
-  serve     launch a Shenzhen Go server
-  
+%s  
 "edit" is the default command.
 
+Use "%s help [command]" for more information about a command.
+
 Flags:
 
-`, os.Args[0])
-	// TODO: Add per-command help. `shenzhen-go help [command]`
+`, os.Args[0], commandSummaries(), os.Args[0])
 
 	flag.PrintDefaults()
 }
@@ -191,19 +186,29 @@ func main() {
 	args := flag.Args()
 	if len(args) > 0 {
 		switch args[0] {
-		case "build":
-			log.Fatalf("TODO: build is not yet implemented")
+		case "build", "dot", "generate", "install", "mermaid", "migrate", "run", "test":
+			os.Exit(runHeadless(args[0], args[1:]))
+		case "diff":
+			os.Exit(runDiff(args[1:]))
+		case "merge":
+			os.Exit(runMerge(args[1:]))
+		case "fmt":
+			os.Exit(runFmt(args[1:]))
+		case "import":
+			os.Exit(runImport(args[1:]))
 		case "edit":
 			args = args[1:]
-		case "generate":
-			log.Fatalf("TODO: generate is not yet implemented")
 		case "help":
+			if len(args) > 1 {
+				if !commandHelp(args[1]) {
+					fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", args[1])
+					usage()
+					os.Exit(2)
+				}
+				return
+			}
 			usage()
 			return
-		case "install":
-			log.Fatalf("TODO: install is not yet implemented")
-		case "run":
-			log.Fatalf("TODO: run is not yet implemented")
 		case "serve":
 			if len(args) > 1 {
 				log.Print(`Note: extra arguments to "serve" command are ignored`)
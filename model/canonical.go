@@ -0,0 +1,110 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"go/scanner"
+	"go/token"
+	"strings"
+)
+
+// CodeLines returns code as it should be saved: one line per element,
+// without carriage returns or trailing whitespace, and without blank lines
+// at the start or end. Code with no lines is nil. Trailing whitespace inside
+// raw string literals is part of the string, so is kept.
+func CodeLines(code []string) []string {
+	src := strings.Join(code, "\n")
+	raw := rawStringLines(src)
+	lines := strings.Split(src, "\n")
+	for i, l := range lines {
+		cutset := " \t\r"
+		if raw[i] {
+			// Raw strings don't contain carriage returns anyway.
+			cutset = "\r"
+		}
+		lines[i] = strings.TrimRight(l, cutset)
+	}
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return lines
+}
+
+// rawStringLines returns the lines of src (counting from 0) that end inside
+// a raw string literal.
+func rawStringLines(src string) map[int]bool {
+	fset := token.NewFileSet()
+	f := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(f, []byte(src), nil, 0)
+	lines := make(map[int]bool)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return lines
+		}
+		if tok != token.STRING || !strings.HasPrefix(lit, "`") {
+			continue
+		}
+		start := f.Line(pos) - 1
+		for i := 0; i < strings.Count(lit, "\n"); i++ {
+			lines[start+i] = true
+		}
+	}
+}
+
+// htmlEscapes are the escapes encoding/json uses for HTML-sensitive
+// characters, which are noisy in code such as "x <- y".
+var htmlEscapes = map[string]byte{
+	`\u003c`: '<',
+	`\u003e`: '>',
+	`\u0026`: '&',
+}
+
+// unescapeHTML undoes the HTML-safe escaping in encoded JSON.
+func unescapeHTML(j []byte) []byte {
+	if !bytes.Contains(j, []byte(`\u00`)) {
+		return j
+	}
+	out := make([]byte, 0, len(j))
+	for i := 0; i < len(j); i++ {
+		if j[i] != '\\' {
+			out = append(out, j[i])
+			continue
+		}
+		if i+6 <= len(j) {
+			if c, ok := htmlEscapes[string(j[i:i+6])]; ok {
+				out = append(out, c)
+				i += 5
+				continue
+			}
+		}
+		// Some other escape: copy both characters, so an escaped
+		// backslash isn't mistaken for the start of an escape.
+		out = append(out, j[i])
+		if i+1 < len(j) {
+			i++
+			out = append(out, j[i])
+		}
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCodeLines(t *testing.T) {
+	tests := []struct {
+		in, want []string
+	}{
+		{in: nil, want: nil},
+		{in: []string{""}, want: nil},
+		{in: []string{"", " \t", ""}, want: nil},
+		{in: []string{"a := 1\r", "b := 2  "}, want: []string{"a := 1", "b := 2"}},
+		{in: []string{"", "if x {\n\ty()\n}", "", ""}, want: []string{"if x {", "\ty()", "}"}},
+		{in: []string{"a", "", "b"}, want: []string{"a", "", "b"}},
+		{in: []string{"s := `a  ", "b\t\r", "c`  ", "d  "}, want: []string{"s := `a  ", "b\t", "c`", "d"}},
+		{in: []string{"x := \"`\"  ", "y := '`'  "}, want: []string{"x := \"`\"", "y := '`'"}},
+	}
+	for _, test := range tests {
+		if got := CodeLines(test.in); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("CodeLines(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestUnescapeHTML(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{in: `"x <- y"`, want: `"x <- y"`},
+		{in: `"a && b > c"`, want: `"a && b > c"`},
+		{in: `"\\u003c"`, want: `"\\u003c"`},
+		{in: `"\\<"`, want: `"\\<"`},
+		{in: `"é"`, want: `"é"`},
+	}
+	for _, test := range tests {
+		if got := string(unescapeHTML([]byte(test.in))); got != test.want {
+			t.Errorf("unescapeHTML(%s) = %s, want %s", test.in, got, test.want)
+		}
+	}
+}
+
+func TestWriteJSONToCanonical(t *testing.T) {
+	g := &Graph{
+		Name: "a < b",
+		Nodes: map[string]*Node{
+			"foo": {
+				Name:         "foo",
+				Multiplicity: "1",
+				Part:         &FakePart{},
+				X:            10.4,
+				Y:            20.6,
+				Connections:  map[string]string{},
+			},
+		},
+		Channels: map[string]*Channel{},
+	}
+	got, err := g.JSON()
+	if err != nil {
+		t.Fatalf("JSON() = error %v", err)
+	}
+	for _, want := range []string{
+		`"name": "a < b"`,
+		`"x": 10,`,
+		`"y": 21,`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("JSON() = %s, want it to contain %s", got, want)
+		}
+	}
+
+	// Canonical JSON should be unchanged by loading and writing it again.
+	g2, err := LoadJSON(strings.NewReader(got), "", "")
+	if err != nil {
+		t.Fatalf("LoadJSON() = error %v", err)
+	}
+	again, err := g2.JSON()
+	if err != nil {
+		t.Fatalf("JSON() = error %v", err)
+	}
+	if again != got {
+		t.Errorf("JSON() after reload = %s, want %s", again, got)
+	}
+}
@@ -0,0 +1,412 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+// Severity describes how bad a Diagnostic is.
+type Severity int
+
+// The various severities.
+const (
+	// SeverityWarning is for things that are probably mistakes, but
+	// which won't stop the generated code from compiling.
+	SeverityWarning Severity = iota
+
+	// SeverityError is for things that will definitely cause generation
+	// or compilation of the generated code to fail.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "Severity(" + strconv.Itoa(int(s)) + ")"
+}
+
+// Diagnostic describes a problem found with a graph by Check.
+// Node, Channel, and Pin are empty when not relevant to the problem.
+type Diagnostic struct {
+	Severity Severity
+	Node     string // name of the node involved
+	Channel  string // name of the channel involved
+	Pin      string // name of the pin on Node involved
+	Message  string
+}
+
+func (d *Diagnostic) String() string {
+	buf := bytes.NewBufferString(d.Severity.String())
+	if d.Node != "" {
+		fmt.Fprintf(buf, ": node %q", d.Node)
+		if d.Pin != "" {
+			fmt.Fprintf(buf, " pin %q", d.Pin)
+		}
+	}
+	if d.Channel != "" {
+		fmt.Fprintf(buf, ": channel %q", d.Channel)
+	}
+	buf.WriteString(": ")
+	buf.WriteString(d.Message)
+	return buf.String()
+}
+
+// Diagnostics is a list of diagnostics.
+type Diagnostics []*Diagnostic
+
+// addFunc is how checks report problems. node, channel, and pn are empty
+// when not relevant to the problem.
+type addFunc func(sev Severity, node, channel, pn, format string, args ...interface{})
+
+// add appends a diagnostic. It is an addFunc.
+func (ds *Diagnostics) add(sev Severity, node, channel, pn, format string, args ...interface{}) {
+	*ds = append(*ds, &Diagnostic{
+		Severity: sev,
+		Node:     node,
+		Channel:  channel,
+		Pin:      pn,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// HasErrors is true if any of the diagnostics are errors.
+func (ds Diagnostics) HasErrors() bool {
+	for _, d := range ds {
+		if d.Severity >= SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns an error summarising all the error diagnostics, or nil
+// if there are none.
+func (ds Diagnostics) Err() error {
+	var msgs []string
+	for _, d := range ds {
+		if d.Severity >= SeverityError {
+			msgs = append(msgs, d.String())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("graph has %d error(s):\n%s", len(msgs), strings.Join(msgs, "\n"))
+}
+
+func (ds Diagnostics) sort() {
+	sort.SliceStable(ds, func(i, j int) bool {
+		a, b := ds[i], ds[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if a.Node != b.Node {
+			return a.Node < b.Node
+		}
+		if a.Channel != b.Channel {
+			return a.Channel < b.Channel
+		}
+		if a.Pin != b.Pin {
+			return a.Pin < b.Pin
+		}
+		return a.Message < b.Message
+	})
+}
+
+// contextReserved contains identifiers used by the generated code when
+// UseContext is set, which cannot be used as pin or channel names.
+var contextReserved = map[string]bool{
+	"ctx":    true,
+	"err":    true,
+	"cancel": true,
+	"errc":   true,
+	"fail":   true,
+}
+
+// Check checks over the graph for any problems, returning diagnostics
+// sorted with errors first. Check calls InferTypes.
+func (g *Graph) Check() Diagnostics {
+	var ds Diagnostics
+	add := ds.add
+
+	g.checkSubgraphs(add)
+
+	// Node names must mangle to distinct, non-empty identifiers.
+	idents := make(map[string][]string)
+	for _, n := range g.Nodes {
+		idents[n.Identifier()] = append(idents[n.Identifier()], n.Name)
+	}
+	for id, names := range idents {
+		if id == "" {
+			for _, nn := range names {
+				add(SeverityError, nn, "", "", "name does not contain any characters usable in an identifier")
+			}
+			continue
+		}
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		for _, nn := range names {
+			add(SeverityError, nn, "", "", "name mangles to identifier %q, which is shared with nodes %s", id, quoteOthers(names, nn))
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if err := n.checkMultiplicity(); err != nil {
+			add(SeverityError, n.Name, "", "", "invalid multiplicity %q: %v", n.Multiplicity, err)
+		}
+		if g.UseContext {
+			for pn := range n.Part.Pins() {
+				if contextReserved[pn] {
+					add(SeverityError, n.Name, "", pn, "pin name %q is reserved when generating a context-aware Run", pn)
+				}
+			}
+		}
+		if !n.Enabled {
+			continue
+		}
+		pins := n.Part.Pins()
+		for pn, cn := range n.Connections {
+			if cn != "" && cn != "nil" {
+				continue
+			}
+			if def := pins[pn]; def != nil && def.Optional {
+				continue
+			}
+			add(SeverityWarning, n.Name, "", pn, "pin is not connected")
+		}
+	}
+
+	for _, c := range g.Channels {
+		if g.UseContext && contextReserved[c.Name] {
+			add(SeverityError, "", c.Name, "", "channel name %q is reserved when generating a context-aware Run", c.Name)
+		}
+		if g.Telemetry && telemetryReserved[c.Name] {
+			add(SeverityError, "", c.Name, "", "channel name %q is reserved when generating telemetry", c.Name)
+		}
+		var readers, writers, disabledWriters, enabledReaders int
+		for np := range c.Pins {
+			n := g.Nodes[np.Node]
+			if n == nil {
+				add(SeverityError, np.Node, c.Name, np.Pin, "channel is attached to a nonexistent node")
+				continue
+			}
+			p := n.Part.Pins()[np.Pin]
+			if p == nil {
+				add(SeverityError, np.Node, c.Name, np.Pin, "channel is attached to a nonexistent pin")
+				continue
+			}
+			switch p.Direction {
+			case pin.Input:
+				readers++
+				if n.Enabled {
+					enabledReaders++
+				}
+			case pin.Output:
+				writers++
+				if !n.Enabled {
+					disabledWriters++
+				}
+			}
+		}
+		switch {
+		case readers == 0 && writers > 0:
+			add(SeverityWarning, "", c.Name, "", "channel has writers but no readers")
+		case writers == 0 && readers > 0:
+			add(SeverityWarning, "", c.Name, "", "channel has readers but no writers")
+		}
+		if disabledWriters == 0 || enabledReaders == 0 {
+			continue
+		}
+		for np := range c.Pins {
+			n := g.Nodes[np.Node]
+			if n == nil || n.Enabled {
+				continue
+			}
+			if p := n.Part.Pins()[np.Pin]; p == nil || p.Direction != pin.Output {
+				continue
+			}
+			add(SeverityWarning, np.Node, c.Name, np.Pin, "disabled node writes to a channel read by enabled nodes")
+		}
+	}
+
+	g.checkPorts(add)
+	g.checkSupervision(add)
+	g.checkParameters(add)
+
+	if err := g.InferTypes(); err != nil {
+		d := &Diagnostic{
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("type inference failed: %v", err),
+		}
+		if te, ok := err.(*TypeIncompatibilityError); ok {
+			d.Node, d.Pin = te.Pin.Node, te.Pin.Pin
+			if te.Channel != nil {
+				d.Channel = te.Channel.Name
+			}
+		}
+		ds = append(ds, d)
+	} else {
+		// The Impl of some parts includes inferred types, so they can
+		// only be analysed once inference has succeeded.
+		g.checkDeadlocks(add)
+		g.checkNodeParameters(add)
+	}
+
+	ds.sort()
+	return ds
+}
+
+// checkSubgraphs links any subgraphs, and checks they can be used by g.
+func (g *Graph) checkSubgraphs(add addFunc) {
+	linked := false
+	for _, n := range g.Nodes {
+		r, ok := n.Part.(GraphRef)
+		if !ok {
+			continue
+		}
+		linked = true
+		if err := g.LinkNode(n); err != nil {
+			add(SeverityError, n.Name, "", "", "cannot link subgraph: %v", err)
+			continue
+		}
+		sub := r.Subgraph()
+		if sub.IsCommand {
+			add(SeverityError, n.Name, "", "", "subgraph %q is a command", sub.Name)
+		}
+		if sub.UseContext != g.UseContext {
+			add(SeverityError, n.Name, "", "", "subgraph %q and this graph must both use a context-aware Run, or both not", sub.Name)
+		}
+	}
+	if linked {
+		g.RefreshChannelsPins()
+	}
+}
+
+// checkPorts checks that ports can become parameters of Run.
+func (g *Graph) checkPorts(add addFunc) {
+	portOf := make(map[string][]string) // channel name -> port node names
+	for _, nn := range sortedNodeNames(g.Nodes) {
+		n := g.Nodes[nn]
+		pt, ok := n.Part.(Port)
+		if !ok {
+			continue
+		}
+		if g.IsCommand {
+			add(SeverityWarning, n.Name, "", "", "ports are ignored when the graph is a command")
+			continue
+		}
+		if g.Channels[n.Identifier()] != nil {
+			add(SeverityError, n.Name, n.Identifier(), "", "port name clashes with a channel name")
+		}
+		if g.UseContext && contextReserved[n.Identifier()] {
+			add(SeverityError, n.Name, "", "", "port name %q is reserved when generating a context-aware Run", n.Identifier())
+		}
+		if g.Telemetry && telemetryReserved[n.Identifier()] {
+			add(SeverityError, n.Name, "", "", "port name %q is reserved when generating telemetry", n.Identifier())
+		}
+		pn := pt.PortPin()
+		c := g.Channels[n.Connections[pn]]
+		if c == nil {
+			continue
+		}
+		portOf[c.Name] = append(portOf[c.Name], n.Name)
+		dir := n.Part.Pins()[pn].Direction
+		for np := range c.Pins {
+			m := g.Nodes[np.Node]
+			if m == nil {
+				continue
+			}
+			if p := m.Part.Pins()[np.Pin]; p != nil && p.Direction == dir && np.Node != n.Name {
+				add(SeverityError, np.Node, c.Name, np.Pin, "pin has the same direction as port %q on the same channel", n.Name)
+			}
+		}
+	}
+	for cn, nns := range portOf {
+		if len(nns) < 2 {
+			continue
+		}
+		for _, nn := range nns {
+			add(SeverityError, nn, cn, "", "channel is also connected to ports %s", quoteOthers(nns, nn))
+		}
+	}
+}
+
+// checkMultiplicity checks that the multiplicity is an expression, and if
+// it is a literal, that it is a positive integer.
+func (n *Node) checkMultiplicity() error {
+	if strings.TrimSpace(n.Multiplicity) == "" {
+		return errors.New("empty expression")
+	}
+	x, err := parser.ParseExpr(n.ExpandedMult())
+	if err != nil {
+		return err
+	}
+	lit, ok := x.(*ast.BasicLit)
+	if !ok {
+		// Can't easily say more without type-checking.
+		return nil
+	}
+	if lit.Kind != token.INT {
+		return errors.New("literal is not an integer")
+	}
+	v, err := strconv.ParseInt(lit.Value, 0, 64)
+	if err != nil {
+		return err
+	}
+	if v < 1 {
+		return errors.New("must be at least 1")
+	}
+	return nil
+}
+
+// firstError runs a check that adds diagnostics, and returns the first
+// error diagnostic as an error, or nil if there are none.
+func firstError(check func(add addFunc)) error {
+	var ds Diagnostics
+	check(ds.add)
+	for _, d := range ds {
+		if d.Severity >= SeverityError {
+			return errors.New(strings.TrimPrefix(d.String(), d.Severity.String()+": "))
+		}
+	}
+	return nil
+}
+
+func quoteOthers(names []string, except string) string {
+	q := make([]string, 0, len(names)-1)
+	for _, n := range names {
+		if n != except {
+			q = append(q, strconv.Quote(n))
+		}
+	}
+	return strings.Join(q, ", ")
+}
@@ -0,0 +1,237 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"gopkg.in/d4l3k/messagediff.v1"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+func fakeNode(name string, enabled bool, mult string, conns map[string]string, pins ...*pin.Definition) *Node {
+	return &Node{
+		Part:         &FakePart{nil, "", "", "", pin.NewMap(pins...)},
+		Name:         name,
+		Enabled:      enabled,
+		Multiplicity: mult,
+		Connections:  conns,
+	}
+}
+
+// testGraph returns a library graph with the package path
+// example.com/name, containing the nodes and channels.
+func testGraph(name string, nodes []*Node, channels ...*Channel) *Graph {
+	g := &Graph{
+		Name:        name,
+		PackagePath: "example.com/" + name,
+		Nodes:       make(map[string]*Node, len(nodes)),
+		Channels:    make(map[string]*Channel, len(channels)),
+	}
+	for _, n := range nodes {
+		g.Nodes[n.Name] = n
+	}
+	for _, c := range channels {
+		g.Channels[c.Name] = c
+	}
+	g.RefreshChannelsPins()
+	return g
+}
+
+func TestCheck(t *testing.T) {
+	out := func(t string) *pin.Definition { return &pin.Definition{Name: "output", Type: t, Direction: pin.Output} }
+	in := func(t string) *pin.Definition { return &pin.Definition{Name: "input", Type: t, Direction: pin.Input} }
+
+	tests := []struct {
+		name  string
+		nodes []*Node
+		chans []string
+		want  Diagnostics
+	}{
+		{
+			name: "ok",
+			nodes: []*Node{
+				fakeNode("a", true, "1", map[string]string{"output": "c"}, out("int")),
+				fakeNode("b", true, "N", map[string]string{"input": "c"}, in("$T")),
+			},
+			chans: []string{"c"},
+		},
+		{
+			name: "unconnected pin",
+			nodes: []*Node{
+				fakeNode("a", true, "1", map[string]string{"output": "nil"}, out("int")),
+				fakeNode("b", false, "1", map[string]string{"input": "nil"}, in("int")),
+			},
+			want: Diagnostics{
+				{Severity: SeverityWarning, Node: "a", Pin: "output", Message: "pin is not connected"},
+			},
+		},
+		{
+			name: "unconnected optional pin",
+			nodes: []*Node{
+				fakeNode("a", true, "1", map[string]string{"output": "nil"},
+					&pin.Definition{Name: "output", Type: "int", Direction: pin.Output, Optional: true}),
+			},
+		},
+		{
+			name: "writers only",
+			nodes: []*Node{
+				fakeNode("a", true, "1", map[string]string{"output": "c"}, out("int")),
+				fakeNode("b", true, "1", map[string]string{"output": "c"}, out("int")),
+			},
+			chans: []string{"c"},
+			want: Diagnostics{
+				{Severity: SeverityWarning, Channel: "c", Message: "channel has writers but no readers"},
+			},
+		},
+		{
+			name: "readers only",
+			nodes: []*Node{
+				fakeNode("a", true, "1", map[string]string{"input": "c"}, in("int")),
+				fakeNode("b", true, "1", map[string]string{"input": "c"}, in("int")),
+			},
+			chans: []string{"c"},
+			want: Diagnostics{
+				{Severity: SeverityWarning, Channel: "c", Message: "channel has readers but no writers"},
+			},
+		},
+		{
+			name: "disabled feeding enabled",
+			nodes: []*Node{
+				fakeNode("a", false, "1", map[string]string{"output": "c"}, out("int")),
+				fakeNode("b", true, "1", map[string]string{"input": "c"}, in("int")),
+			},
+			chans: []string{"c"},
+			want: Diagnostics{
+				{Severity: SeverityWarning, Node: "a", Channel: "c", Pin: "output", Message: "disabled node writes to a channel read by enabled nodes"},
+			},
+		},
+		{
+			name: "bad multiplicity",
+			nodes: []*Node{
+				fakeNode("a", false, "0", nil),
+				fakeNode("b", false, "2.5", nil),
+				fakeNode("c", false, "", nil),
+				fakeNode("d", false, "2*N", nil),
+			},
+			want: Diagnostics{
+				{Severity: SeverityError, Node: "a", Message: `invalid multiplicity "0": must be at least 1`},
+				{Severity: SeverityError, Node: "b", Message: `invalid multiplicity "2.5": literal is not an integer`},
+				{Severity: SeverityError, Node: "c", Message: `invalid multiplicity "": empty expression`},
+			},
+		},
+		{
+			name: "mangled names collide",
+			nodes: []*Node{
+				fakeNode("foo bar", false, "1", nil),
+				fakeNode("foo_bar", false, "1", nil),
+				fakeNode("!!!", false, "1", nil),
+			},
+			want: Diagnostics{
+				{Severity: SeverityError, Node: "!!!", Message: "name does not contain any characters usable in an identifier"},
+				{Severity: SeverityError, Node: "foo bar", Message: `name mangles to identifier "foo_bar", which is shared with nodes "foo_bar"`},
+				{Severity: SeverityError, Node: "foo_bar", Message: `name mangles to identifier "foo_bar", which is shared with nodes "foo bar"`},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := &Graph{
+				Nodes:    make(map[string]*Node),
+				Channels: make(map[string]*Channel),
+			}
+			for _, n := range test.nodes {
+				g.Nodes[n.Name] = n
+			}
+			for _, c := range test.chans {
+				g.Channels[c] = &Channel{Name: c}
+			}
+			g.RefreshChannelsPins()
+
+			got := g.Check()
+			if diff, equal := messagediff.PrettyDiff(got, test.want); !equal {
+				t.Errorf("g.Check() diff (got -> want)\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestCheckUseContextReserved(t *testing.T) {
+	g := &Graph{
+		UseContext: true,
+		Nodes: map[string]*Node{
+			"a": fakeNode("a", false, "1", nil, &pin.Definition{Name: "ctx", Type: "int", Direction: pin.Input}),
+		},
+		Channels: map[string]*Channel{
+			"fail": {Name: "fail"},
+		},
+	}
+	want := Diagnostics{
+		{Severity: SeverityError, Channel: "fail", Message: `channel name "fail" is reserved when generating a context-aware Run`},
+		{Severity: SeverityError, Node: "a", Pin: "ctx", Message: `pin name "ctx" is reserved when generating a context-aware Run`},
+	}
+	if diff, equal := messagediff.PrettyDiff(g.Check(), want); !equal {
+		t.Errorf("g.Check() diff (got -> want)\n%v", diff)
+	}
+}
+
+func TestCheckTypeInferenceFailure(t *testing.T) {
+	g := &Graph{
+		Nodes: map[string]*Node{
+			"a": fakeNode("a", true, "1", map[string]string{"output": "c"}, &pin.Definition{Name: "output", Type: "int", Direction: pin.Output}),
+			"b": fakeNode("b", true, "1", map[string]string{"input": "c"}, &pin.Definition{Name: "input", Type: "string", Direction: pin.Input}),
+		},
+		Channels: map[string]*Channel{
+			"c": {Name: "c"},
+		},
+	}
+	g.RefreshChannelsPins()
+
+	// The exact message and pin depend on map iteration order.
+	got := g.Check()
+	if len(got) != 1 || got[0].Severity != SeverityError || got[0].Channel != "c" {
+		t.Errorf("g.Check() = %v, want one error about channel c", got)
+	}
+}
+
+func TestCheckMultiplicityParseError(t *testing.T) {
+	g := &Graph{
+		Nodes: map[string]*Node{
+			"a": fakeNode("a", false, "N+", nil),
+		},
+	}
+	got := g.Check()
+	if len(got) != 1 || got[0].Severity != SeverityError || got[0].Node != "a" {
+		t.Errorf("g.Check() = %v, want one error about node a", got)
+	}
+}
+
+func TestDiagnosticsErr(t *testing.T) {
+	ds := Diagnostics{
+		{Severity: SeverityWarning, Node: "a", Pin: "p", Message: "meh"},
+	}
+	if err := ds.Err(); err != nil {
+		t.Errorf("ds.Err() = %v, want nil", err)
+	}
+	ds = append(ds, &Diagnostic{Severity: SeverityError, Channel: "c", Message: "bad"})
+	err := ds.Err()
+	if err == nil {
+		t.Fatal("ds.Err() = nil, want error")
+	}
+	if got, want := err.Error(), "graph has 1 error(s):\nerror: channel \"c\": bad"; got != want {
+		t.Errorf("ds.Err() = %q, want %q", got, want)
+	}
+}
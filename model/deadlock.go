@@ -0,0 +1,352 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+// pinUsage summarises how the code of a node uses its pins.
+type pinUsage struct {
+	closes map[string]bool // output pins closed by the node (usually in the Tail)
+	drains map[string]bool // input pins read until closed
+}
+
+// usage works out how the node uses its pins, from its Impl. Nodes that
+// can't be analysed (subgraphs, and code that doesn't parse) are assumed to
+// read every input until closed, and close every output.
+func (n *Node) usage() *pinUsage {
+	u := &pinUsage{
+		closes: make(map[string]bool),
+		drains: make(map[string]bool),
+	}
+	opaque := func() *pinUsage {
+		for pn, p := range n.Part.Pins() {
+			switch p.Direction {
+			case pin.Input:
+				u.drains[pn] = true
+			case pin.Output:
+				u.closes[pn] = true
+			}
+		}
+		return u
+	}
+	if n.IsPort() {
+		// The caller of Run closes the channels of input ports.
+		for pn, p := range n.Part.Pins() {
+			if p.Direction == pin.Output {
+				u.closes[pn] = true
+			}
+		}
+		return u
+	}
+	if _, ok := n.Part.(GraphRef); ok {
+		return opaque()
+	}
+	_, f, _, err := n.parseImpl()
+	if err != nil {
+		return opaque()
+	}
+
+	// Map the parameter objects for pins back to pin names, so that
+	// shadowing declarations aren't mistaken for pins.
+	pins := n.Part.Pins()
+	params := make(map[*ast.Object]string)
+	for _, fld := range f.Decls[0].(*ast.FuncDecl).Type.Params.List {
+		for _, id := range fld.Names {
+			if pins[id.Name] != nil {
+				params[id.Obj] = id.Name
+			}
+		}
+	}
+	pinName := func(x ast.Expr, dir pin.Direction) string {
+		id, ok := x.(*ast.Ident)
+		if !ok || id.Obj == nil {
+			return ""
+		}
+		pn := params[id.Obj]
+		if pn == "" || pins[pn].Direction != dir {
+			return ""
+		}
+		return pn
+	}
+	// receive returns the pin received from by x, if x is a receive.
+	receive := func(x ast.Expr) string {
+		ux, ok := x.(*ast.UnaryExpr)
+		if !ok || ux.Op != token.ARROW {
+			return ""
+		}
+		return pinName(ux.X, pin.Input)
+	}
+
+	ast.Inspect(f, func(x ast.Node) bool {
+		switch x := x.(type) {
+		case *ast.CallExpr:
+			// close(output)
+			if id, ok := x.Fun.(*ast.Ident); ok && id.Name == "close" && id.Obj == nil && len(x.Args) == 1 {
+				if pn := pinName(x.Args[0], pin.Output); pn != "" {
+					u.closes[pn] = true
+				}
+			}
+		case *ast.RangeStmt:
+			// for x := range input
+			if pn := pinName(x.X, pin.Input); pn != "" {
+				u.drains[pn] = true
+			}
+		case *ast.AssignStmt:
+			// x, ok := <-input
+			if len(x.Lhs) == 2 && len(x.Rhs) == 1 {
+				if pn := receive(x.Rhs[0]); pn != "" {
+					u.drains[pn] = true
+				}
+			}
+		case *ast.ValueSpec:
+			// var x, ok = <-input
+			if len(x.Names) == 2 && len(x.Values) == 1 {
+				if pn := receive(x.Values[0]); pn != "" {
+					u.drains[pn] = true
+				}
+			}
+		}
+		return true
+	})
+	return u
+}
+
+// checkDeadlocks looks for likely deadlocks: cycles of unbuffered channels,
+// channels read until closed that are never closed, and nodes that Run
+// waits for which may never finish. It is a heuristic: it assumes that a
+// node finishes once every channel it reads until closed has been closed,
+// and that a channel is closed once a node closing it has finished.
+func (g *Graph) checkDeadlocks(add addFunc) {
+	names := sortedNodeNames(g.Nodes)
+	usage := make(map[string]*pinUsage)
+	for _, nn := range names {
+		n := g.Nodes[nn]
+		if !n.Enabled {
+			continue
+		}
+		g.refreshImpl(n)
+		usage[nn] = n.usage()
+	}
+
+	// Work out which nodes finish, and which channels are closed, by
+	// iterating to a fixed point. Nodes in a cycle waiting on each other's
+	// channels never finish.
+	finishes := make(map[string]bool)
+	closed := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, nn := range names {
+			u := usage[nn]
+			if u == nil || finishes[nn] {
+				continue
+			}
+			if g.blockingInput(nn, u, closed) != "" {
+				continue
+			}
+			finishes[nn] = true
+			changed = true
+			for pn := range u.closes {
+				if cn := g.Nodes[nn].Connections[pn]; g.Channels[cn] != nil {
+					closed[cn] = true
+				}
+			}
+		}
+	}
+
+	for _, nn := range names {
+		u := usage[nn]
+		if u == nil {
+			continue
+		}
+		n := g.Nodes[nn]
+		for _, pn := range sortedPinNames(u.drains) {
+			cn := n.Connections[pn]
+			if g.Channels[cn] == nil || closed[cn] {
+				continue
+			}
+			// Channels without writers are reported elsewhere.
+			if writers, closers := g.writers(cn, usage); writers == 0 || closers > 0 {
+				continue
+			}
+			add(SeverityWarning, nn, cn, pn, "input is read until the channel is closed, but no enabled node closes the channel")
+		}
+		if !n.Wait || finishes[nn] {
+			continue
+		}
+		pn := g.blockingInput(nn, u, closed)
+		add(SeverityWarning, nn, n.Connections[pn], pn, "Run waits for this node, but it may never finish, because the channel it reads until closed may never be closed")
+	}
+
+	g.checkUnbufferedCycles(add)
+}
+
+// blockingInput returns the first input pin of the node that is read until
+// closed, but whose channel is not (yet) known to be closed.
+func (g *Graph) blockingInput(nn string, u *pinUsage, closed map[string]bool) string {
+	n := g.Nodes[nn]
+	for _, pn := range sortedPinNames(u.drains) {
+		cn := n.Connections[pn]
+		if g.Channels[cn] == nil {
+			// Unconnected pins are reported elsewhere.
+			continue
+		}
+		if !closed[cn] {
+			return pn
+		}
+	}
+	return ""
+}
+
+// writers counts the enabled nodes that write to the channel, and the
+// enabled nodes that close it.
+func (g *Graph) writers(cn string, usage map[string]*pinUsage) (writers, closers int) {
+	for nn, u := range usage {
+		n := g.Nodes[nn]
+		writes, closes := false, false
+		for pn, p := range n.Part.Pins() {
+			if p.Direction != pin.Output || n.Connections[pn] != cn {
+				continue
+			}
+			writes = true
+			closes = closes || u.closes[pn]
+		}
+		if writes {
+			writers++
+		}
+		if closes {
+			closers++
+		}
+	}
+	return writers, closers
+}
+
+// checkUnbufferedCycles reports channels that are part of a cycle of
+// unbuffered channels between enabled nodes. Each node in such a cycle may
+// block sending to the next, while the next is blocked sending to the one
+// after, and so on around the cycle.
+func (g *Graph) checkUnbufferedCycles(add addFunc) {
+	// Edges from writers to readers of unbuffered channels.
+	type edge struct{ to, channel string }
+	edges := make(map[string][]edge)
+	for _, cn := range sortedChannelNames(g.Channels) {
+		c := g.Channels[cn]
+		if c.Capacity != 0 {
+			continue
+		}
+		var writers, readers []string
+		for np := range c.Pins {
+			n := g.Nodes[np.Node]
+			if n == nil || !n.Enabled || n.IsPort() {
+				continue
+			}
+			p := n.Part.Pins()[np.Pin]
+			if p == nil {
+				continue
+			}
+			switch p.Direction {
+			case pin.Output:
+				writers = append(writers, np.Node)
+			case pin.Input:
+				readers = append(readers, np.Node)
+			}
+		}
+		for _, w := range writers {
+			for _, r := range readers {
+				edges[w] = append(edges[w], edge{r, cn})
+			}
+		}
+	}
+
+	// Tarjan's strongly connected components algorithm.
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	comp := make(map[string]int) // node name -> component number
+	ncomp := 0
+	var connect func(v string)
+	connect = func(v string) {
+		index[v] = len(index)
+		low[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, e := range edges[v] {
+			if _, seen := index[e.to]; !seen {
+				connect(e.to)
+				if low[e.to] < low[v] {
+					low[v] = low[e.to]
+				}
+			} else if onStack[e.to] && index[e.to] < low[v] {
+				low[v] = index[e.to]
+			}
+		}
+		if low[v] != index[v] {
+			return
+		}
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			comp[w] = ncomp
+			if w == v {
+				break
+			}
+		}
+		ncomp++
+	}
+	for _, nn := range sortedNodeNames(g.Nodes) {
+		if _, seen := index[nn]; !seen {
+			connect(nn)
+		}
+	}
+
+	members := make(map[int][]string)
+	for _, nn := range sortedNodeNames(g.Nodes) {
+		if c, ok := comp[nn]; ok {
+			members[c] = append(members[c], nn)
+		}
+	}
+	reported := make(map[string]bool)
+	for _, v := range sortedNodeNames(g.Nodes) {
+		for _, e := range edges[v] {
+			if comp[v] != comp[e.to] || reported[e.channel] {
+				continue
+			}
+			reported[e.channel] = true
+			nodes := make([]string, 0, len(members[comp[v]]))
+			for _, nn := range members[comp[v]] {
+				nodes = append(nodes, strconv.Quote(nn))
+			}
+			add(SeverityWarning, "", e.channel, "", "unbuffered channel is part of a cycle through nodes %s, which may deadlock", strings.Join(nodes, ", "))
+		}
+	}
+}
+
+func sortedPinNames(m map[string]bool) []string {
+	names := make([]string, 0, len(m))
+	for pn := range m {
+		names = append(names, pn)
+	}
+	sort.Strings(names)
+	return names
+}
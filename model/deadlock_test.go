@@ -0,0 +1,215 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/d4l3k/messagediff.v1"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+func TestNodeUsage(t *testing.T) {
+	n := fakeNode("n", true, "1", nil,
+		&pin.Definition{Name: "a", Type: "int", Direction: pin.Input},
+		&pin.Definition{Name: "b", Type: "int", Direction: pin.Input},
+		&pin.Definition{Name: "c", Type: "int", Direction: pin.Input},
+		&pin.Definition{Name: "x", Type: "int", Direction: pin.Output},
+		&pin.Definition{Name: "y", Type: "int", Direction: pin.Output},
+		&pin.Definition{Name: "z", Type: "int", Direction: pin.Output},
+	)
+	fp := n.Part.(*FakePart)
+	fp.Body = `for v := range a {
+	x <- v
+}
+for {
+	select {
+	case v, ok := <-b:
+		if !ok {
+			return
+		}
+		y <- v
+	case <-c:
+	}
+}`
+	fp.Tail = `close(x)
+if y != nil {
+	close(y)
+}
+{
+	z := make(chan int)
+	close(z)
+}`
+	n.RefreshImpl()
+	got := n.usage()
+	want := &pinUsage{
+		closes: map[string]bool{"x": true, "y": true},
+		drains: map[string]bool{"a": true, "b": true},
+	}
+	if diff, equal := messagediff.PrettyDiff(got, want); !equal {
+		t.Errorf("usage() diff (got -> want)\n%v", diff)
+	}
+}
+
+func TestCheckDeadlocks(t *testing.T) {
+	// node makes an enabled node with pins "in" and "out", running body and tail.
+	node := func(name, body, tail string, wait bool, conns map[string]string) *Node {
+		n := fakeNode(name, true, "1", conns,
+			&pin.Definition{Name: "in", Type: "int", Direction: pin.Input},
+			&pin.Definition{Name: "out", Type: "int", Direction: pin.Output},
+		)
+		n.Wait = wait
+		n.Part.(*FakePart).Body = body
+		n.Part.(*FakePart).Tail = tail
+		return n
+	}
+	const (
+		relay   = "for v := range in { out <- v }"
+		closeIt = "close(out)"
+	)
+
+	tests := []struct {
+		name  string
+		nodes []*Node
+		chans map[string]int // name -> capacity
+		want  Diagnostics
+	}{
+		{
+			name: "pipeline ok",
+			nodes: []*Node{
+				node("a", "out <- 1", closeIt, false, map[string]string{"in": "nil", "out": "c1"}),
+				node("b", relay, closeIt, false, map[string]string{"in": "c1", "out": "c2"}),
+				node("c", relay, "", true, map[string]string{"in": "c2", "out": "nil"}),
+			},
+			chans: map[string]int{"c1": 0, "c2": 0},
+			want: Diagnostics{
+				{Severity: SeverityWarning, Node: "a", Pin: "in", Message: "pin is not connected"},
+				{Severity: SeverityWarning, Node: "c", Pin: "out", Message: "pin is not connected"},
+			},
+		},
+		{
+			name: "never closed",
+			nodes: []*Node{
+				node("a", "out <- 1", "", false, map[string]string{"in": "nil", "out": "c1"}),
+				node("b", relay, closeIt, false, map[string]string{"in": "c1", "out": "c2"}),
+				node("c", relay, "", true, map[string]string{"in": "c2", "out": "nil"}),
+			},
+			chans: map[string]int{"c1": 0, "c2": 0},
+			want: Diagnostics{
+				{Severity: SeverityWarning, Node: "a", Pin: "in", Message: "pin is not connected"},
+				{Severity: SeverityWarning, Node: "b", Channel: "c1", Pin: "in", Message: "input is read until the channel is closed, but no enabled node closes the channel"},
+				{Severity: SeverityWarning, Node: "c", Pin: "out", Message: "pin is not connected"},
+				{Severity: SeverityWarning, Node: "c", Channel: "c2", Pin: "in", Message: "Run waits for this node, but it may never finish, because the channel it reads until closed may never be closed"},
+			},
+		},
+		{
+			name: "unbuffered cycle",
+			nodes: []*Node{
+				node("a", relay, closeIt, false, map[string]string{"in": "c1", "out": "c2"}),
+				node("b", relay, closeIt, false, map[string]string{"in": "c2", "out": "c1"}),
+			},
+			chans: map[string]int{"c1": 0, "c2": 0},
+			want: Diagnostics{
+				{Severity: SeverityWarning, Channel: "c1", Message: `unbuffered channel is part of a cycle through nodes "a", "b", which may deadlock`},
+				{Severity: SeverityWarning, Channel: "c2", Message: `unbuffered channel is part of a cycle through nodes "a", "b", which may deadlock`},
+			},
+		},
+		{
+			name: "buffered cycle with waited node",
+			nodes: []*Node{
+				node("a", relay, closeIt, true, map[string]string{"in": "c1", "out": "c2"}),
+				node("b", relay, closeIt, false, map[string]string{"in": "c2", "out": "c1"}),
+			},
+			chans: map[string]int{"c1": 1, "c2": 0},
+			want: Diagnostics{
+				{Severity: SeverityWarning, Node: "a", Channel: "c1", Pin: "in", Message: "Run waits for this node, but it may never finish, because the channel it reads until closed may never be closed"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := &Graph{
+				Nodes:    make(map[string]*Node),
+				Channels: make(map[string]*Channel),
+			}
+			for _, n := range test.nodes {
+				g.Nodes[n.Name] = n
+			}
+			for c, cap := range test.chans {
+				g.Channels[c] = &Channel{Name: c, Capacity: cap}
+			}
+			g.RefreshChannelsPins()
+
+			got := g.Check()
+			if diff, equal := messagediff.PrettyDiff(got, test.want); !equal {
+				t.Errorf("g.Check() diff (got -> want)\n%v", diff)
+			}
+		})
+	}
+}
+
+// typedRelay is a part whose Impl only parses once the type of its pins has
+// been inferred, like parts that declare variables of the inferred type.
+type typedRelay struct {
+	FakePart
+}
+
+func (p *typedRelay) Impl(n *Node) PartImpl {
+	typ := ""
+	if t := n.TypeParams["$T"]; t != nil {
+		typ = t.String()
+	}
+	return PartImpl{Body: "for v := range in { var _ map[string]" + typ + "; out <- v }"}
+}
+
+func TestCheckDeadlocksInferredTypes(t *testing.T) {
+	a := &Node{
+		Part: &typedRelay{FakePart{Pns: pin.NewMap(
+			&pin.Definition{Name: "in", Type: "$T", Direction: pin.Input},
+			&pin.Definition{Name: "out", Type: "$T", Direction: pin.Output},
+		)}},
+		Name:         "a",
+		Enabled:      true,
+		Multiplicity: "1",
+		Connections:  map[string]string{"in": "c0", "out": "c1"},
+	}
+	src := fakeNode("src", true, "1", map[string]string{"out": "c0"},
+		&pin.Definition{Name: "out", Type: "int", Direction: pin.Output},
+	)
+	src.Part.(*FakePart).Body = "out <- 1"
+	src.Part.(*FakePart).Tail = "close(out)"
+	dst := fakeNode("dst", true, "1", map[string]string{"in": "c1"},
+		&pin.Definition{Name: "in", Type: "int", Direction: pin.Input},
+	)
+	dst.Part.(*FakePart).Body = "for range in {}"
+	g := testGraph("deadlock", []*Node{a, src, dst}, &Channel{Name: "c0"}, &Channel{Name: "c1"})
+
+	// a never closes its output, which can only be seen once a's Impl parses.
+	want := "input is read until the channel is closed, but no enabled node closes the channel"
+	found := false
+	for _, d := range g.Check() {
+		if d.Node == "dst" && d.Message == want {
+			found = true
+		}
+		if strings.Contains(d.Message, "type inference") {
+			t.Errorf("g.Check() has diagnostic %v", d)
+		}
+	}
+	if !found {
+		t.Errorf("g.Check() has no diagnostic for dst %q", want)
+	}
+}
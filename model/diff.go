@@ -0,0 +1,363 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of things in a graph, for diffs and merges.
+const (
+	kindGraph     = "graph"
+	kindNode      = "node"
+	kindChannel   = "channel"
+	kindParameter = "parameter"
+)
+
+// kindRank orders the kinds in diffs and merges.
+var kindRank = map[string]int{
+	kindGraph:     0,
+	kindParameter: 1,
+	kindNode:      2,
+	kindChannel:   3,
+}
+
+// pinPrefix starts the fields of a node holding the connections of its pins.
+const pinPrefix = "pin "
+
+// fieldKey identifies a field of a graph, a node, or a channel. The field
+// of the key for a node or channel itself is empty.
+type fieldKey struct {
+	kind, name, field string
+}
+
+func (k fieldKey) String() string {
+	s := k.kind
+	if k.name != "" {
+		s += " " + k.name
+	}
+	if k.field != "" {
+		s += ": " + k.field
+	}
+	return s
+}
+
+// less orders keys by kind (in kindRank order), name, and then field.
+func (k fieldKey) less(l fieldKey) bool {
+	if k.kind != l.kind {
+		return kindRank[k.kind] < kindRank[l.kind]
+	}
+	if k.name != l.name {
+		return k.name < l.name
+	}
+	return k.field < l.field
+}
+
+// flatGraph is a graph as a map of fields to values. Fields with zero
+// values are left out, so a missing field is the same as an empty one.
+type flatGraph map[fieldKey]string
+
+// flatten turns a graph into fields.
+func flatten(g *Graph) (flatGraph, error) {
+	f := make(flatGraph)
+	set := func(kind, name, field, value string) {
+		if value != "" && value != "false" && value != "0" {
+			f[fieldKey{kind, name, field}] = value
+		}
+	}
+	set(kindGraph, "", "name", g.Name)
+	set(kindGraph, "", "package_path", g.PackagePath)
+	set(kindGraph, "", "is_command", strconv.FormatBool(g.IsCommand))
+	set(kindGraph, "", "use_context", strconv.FormatBool(g.UseContext))
+	set(kindGraph, "", "telemetry", strconv.FormatBool(g.Telemetry))
+	set(kindGraph, "", "output_dir", g.OutputDir)
+	set(kindGraph, "", "generics", strconv.FormatBool(g.Generics))
+	for nn, n := range g.Nodes {
+		pj, err := MarshalPart(n.Part)
+		if err != nil {
+			return nil, fmt.Errorf("node %q: %v", nn, err)
+		}
+		set(kindNode, nn, "", kindNode)
+		set(kindNode, nn, "part_type", pj.Type)
+		set(kindNode, nn, "part", string(pj.Part))
+		set(kindNode, nn, "comment", n.Comment)
+		set(kindNode, nn, "enabled", strconv.FormatBool(n.Enabled))
+		set(kindNode, nn, "wait", strconv.FormatBool(n.Wait))
+		set(kindNode, nn, "multiplicity", n.Multiplicity)
+		set(kindNode, nn, "recover", strconv.FormatBool(n.Recover))
+		set(kindNode, nn, "restart", string(n.Restart))
+		set(kindNode, nn, "max_restarts", strconv.FormatUint(uint64(n.MaxRestarts), 10))
+		set(kindNode, nn, "restart_backoff", n.RestartBackoff)
+		set(kindNode, nn, "restart_metrics", strconv.FormatBool(n.RestartMetrics))
+		set(kindNode, nn, "x", strconv.FormatFloat(n.X, 'g', -1, 64))
+		set(kindNode, nn, "y", strconv.FormatFloat(n.Y, 'g', -1, 64))
+		for pn, cn := range n.Connections {
+			if cn != "nil" {
+				set(kindNode, nn, pinPrefix+pn, cn)
+			}
+		}
+	}
+	for cn, c := range g.Channels {
+		set(kindChannel, cn, "", kindChannel)
+		set(kindChannel, cn, "cap", strconv.Itoa(c.Capacity))
+	}
+	for pn, p := range g.Parameters {
+		set(kindParameter, pn, "", kindParameter)
+		set(kindParameter, pn, "type", p.Type)
+		set(kindParameter, pn, "default", p.Default)
+		set(kindParameter, pn, "help", p.Help)
+	}
+	return f, nil
+}
+
+// names returns the sorted names of the nodes or channels.
+func (f flatGraph) names(kind string) []string {
+	var names []string
+	for k := range f {
+		if k.kind == kind && k.field == "" {
+			names = append(names, k.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// channelPins returns the pins connected to each channel, as a sorted,
+// comma-separated list.
+func (f flatGraph) channelPins() map[string]string {
+	pins := make(map[string][]string)
+	for k, v := range f {
+		if k.kind == kindNode && strings.HasPrefix(k.field, pinPrefix) {
+			pins[v] = append(pins[v], k.name+"."+strings.TrimPrefix(k.field, pinPrefix))
+		}
+	}
+	m := make(map[string]string, len(pins))
+	for cn, ps := range pins {
+		sort.Strings(ps)
+		m[cn] = strings.Join(ps, ",")
+	}
+	return m
+}
+
+// renamed returns a copy of the fields with nodes and channels renamed.
+func (f flatGraph) renamed(nodes, channels map[string]string) flatGraph {
+	r := make(flatGraph, len(f))
+	for k, v := range f {
+		switch k.kind {
+		case kindNode:
+			if nn, ok := nodes[k.name]; ok {
+				k.name = nn
+			}
+			if strings.HasPrefix(k.field, pinPrefix) {
+				if cn, ok := channels[v]; ok {
+					v = cn
+				}
+			}
+		case kindChannel:
+			if cn, ok := channels[k.name]; ok {
+				k.name = cn
+			}
+		}
+		r[k] = v
+	}
+	return r
+}
+
+// renames finds nodes and channels that were renamed from one graph to
+// another. A node is renamed if it was removed, and a node with the same
+// part was added. Then, a channel is renamed if it was removed, and a
+// channel connecting the same pins was added.
+func renames(from, to flatGraph) (nodes, channels map[string]string) {
+	nodes = make(map[string]string)
+	// Pairing in order of names makes the renames predictable when there
+	// are several candidates.
+	added := make(map[string]bool)
+	for _, nn := range to.names(kindNode) {
+		if to[fieldKey{kindNode, nn, ""}] != "" && from[fieldKey{kindNode, nn, ""}] == "" {
+			added[nn] = true
+		}
+	}
+	for _, on := range from.names(kindNode) {
+		if to[fieldKey{kindNode, on, ""}] != "" {
+			continue
+		}
+		for _, nn := range to.names(kindNode) {
+			if !added[nn] {
+				continue
+			}
+			if from[fieldKey{kindNode, on, "part_type"}] == to[fieldKey{kindNode, nn, "part_type"}] &&
+				from[fieldKey{kindNode, on, "part"}] == to[fieldKey{kindNode, nn, "part"}] {
+				nodes[on] = nn
+				delete(added, nn)
+				break
+			}
+		}
+	}
+
+	channels = make(map[string]string)
+	fromPins, toPins := from.renamed(nodes, nil).channelPins(), to.channelPins()
+	for _, oc := range from.names(kindChannel) {
+		if to[fieldKey{kindChannel, oc, ""}] != "" || fromPins[oc] == "" {
+			continue
+		}
+		for _, nc := range to.names(kindChannel) {
+			if from[fieldKey{kindChannel, nc, ""}] != "" {
+				continue
+			}
+			if fromPins[oc] == toPins[nc] {
+				channels[oc] = nc
+				break
+			}
+		}
+	}
+	return nodes, channels
+}
+
+// ChangeKind is the kind of a Change.
+type ChangeKind int
+
+// Kinds of change.
+const (
+	Added ChangeKind = iota
+	Removed
+	Renamed
+	Modified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Renamed:
+		return "renamed"
+	case Modified:
+		return "modified"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is a difference between two graphs.
+type Change struct {
+	Kind ChangeKind
+
+	// What is "graph", "node", "channel", or "parameter".
+	What string
+
+	// Name is the name of the node, channel, or parameter in the old graph,
+	// or in the new graph if it was added. It is empty for changes to the
+	// graph.
+	Name string
+
+	// NewName is the new name of a renamed node or channel.
+	NewName string
+
+	// Field is the field that was modified: a graph property, a node
+	// property such as "part" or "multiplicity", "pin " followed by a pin
+	// name for the channel connected to a pin, "cap" for channels, or
+	// "type", "default", or "help" for parameters.
+	Field string
+
+	// Old and New are the values of a modified field. Empty values mean the
+	// field was unset.
+	Old, New string
+}
+
+func (c Change) String() string {
+	k := fieldKey{c.What, c.Name, c.Field}
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("+ %v", k)
+	case Removed:
+		return fmt.Sprintf("- %v", k)
+	case Renamed:
+		return fmt.Sprintf("~ %v renamed to %s", k, c.NewName)
+	}
+	if c.Field == "part" {
+		// Part configuration is lengthy JSON.
+		return fmt.Sprintf("~ %v changed", k)
+	}
+	return fmt.Sprintf("~ %v: %q -> %q", k, c.Old, c.New)
+}
+
+// Diff returns the changes from graph a to graph b, in a stable order:
+// graph properties, then parameters, then nodes, then channels, by name.
+// Node positions are not compared. Nodes are renamed if a node with the
+// same part configuration replaces one, and channels are renamed if a
+// channel connecting the same pins replaces one.
+func Diff(a, b *Graph) ([]Change, error) {
+	fa, err := flatten(a)
+	if err != nil {
+		return nil, err
+	}
+	fb, err := flatten(b)
+	if err != nil {
+		return nil, err
+	}
+	nodeRen, chanRen := renames(fa, fb)
+	fr := fa.renamed(nodeRen, chanRen)
+
+	keys := make(map[fieldKey]bool)
+	for k := range fr {
+		keys[k] = true
+	}
+	for k := range fb {
+		keys[k] = true
+	}
+	sorted := make([]fieldKey, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].less(sorted[j]) })
+
+	// Report renames under the old name, in the same order as other changes.
+	var cs []Change
+	oldName := func(kind, name string) string {
+		ren := nodeRen
+		if kind == kindChannel {
+			ren = chanRen
+		}
+		for on, nn := range ren {
+			if nn == name {
+				return on
+			}
+		}
+		return name
+	}
+	for _, k := range sorted {
+		if k.field == "x" || k.field == "y" {
+			continue
+		}
+		ov, nv := fr[k], fb[k]
+		exists := fieldKey{k.kind, k.name, ""}
+		switch {
+		case k.field == "" && ov == "":
+			cs = append(cs, Change{Kind: Added, What: k.kind, Name: k.name})
+		case k.field == "" && nv == "":
+			cs = append(cs, Change{Kind: Removed, What: k.kind, Name: k.name})
+		case k.field == "":
+			if on := oldName(k.kind, k.name); on != k.name {
+				cs = append(cs, Change{Kind: Renamed, What: k.kind, Name: on, NewName: k.name})
+			}
+		case ov != nv && (k.kind == kindGraph || fr[exists] != "" && fb[exists] != ""):
+			cs = append(cs, Change{Kind: Modified, What: k.kind, Name: oldName(k.kind, k.name), Field: k.field, Old: ov, New: nv})
+		}
+	}
+	return cs, nil
+}
@@ -0,0 +1,246 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// diffGraph loads a small graph for diffing and merging, with nodes
+// "src" and "dst" connected by channel "c".
+func diffGraph(t *testing.T, edit func(j string) string) *Graph {
+	t.Helper()
+	j := `{
+	"name": "g",
+	"nodes": {
+		"src": {
+			"part_type": "Fake",
+			"part": {"head": "src", "pins": {"out": {"type": "int", "dir": "out"}}},
+			"enabled": true,
+			"x": 10,
+			"connections": {"out": "c"}
+		},
+		"dst": {
+			"part_type": "Fake",
+			"part": {"head": "dst", "pins": {"in": {"type": "int", "dir": "in"}}},
+			"enabled": true,
+			"x": 20,
+			"connections": {"in": "c"}
+		}
+	},
+	"channels": {
+		"c": {"cap": 0}
+	}
+}`
+	if edit != nil {
+		j = edit(j)
+	}
+	g, err := LoadJSON(strings.NewReader(j), "", "")
+	if err != nil {
+		t.Fatalf("LoadJSON() = error %v\n%s", err, j)
+	}
+	return g
+}
+
+// replacer returns an edit function making replacements in the JSON.
+func replacer(oldnew ...string) func(string) string {
+	return strings.NewReplacer(oldnew...).Replace
+}
+
+func changeStrings(cs []Change) string {
+	s := make([]string, len(cs))
+	for i, c := range cs {
+		s[i] = c.String()
+	}
+	return strings.Join(s, "\n")
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		desc string
+		edit func(string) string
+		want []string
+	}{
+		{
+			desc: "no change",
+		},
+		{
+			desc: "moved",
+			edit: replacer(`"x": 10`, `"x": 100`),
+		},
+		{
+			desc: "properties",
+			edit: replacer(`"name": "g"`, `"name": "h"`, `"cap": 0`, `"cap": 5`, `"enabled": true,
+			"x": 10`, `"enabled": false, "comment": "hi",
+			"x": 10`),
+			want: []string{
+				`~ graph: name: "g" -> "h"`,
+				`~ node src: comment: "" -> "hi"`,
+				`~ node src: enabled: "true" -> ""`,
+				`~ channel c: cap: "" -> "5"`,
+			},
+		},
+		{
+			desc: "added node",
+			edit: replacer(`"nodes": {`, `"nodes": {
+		"new": {"part_type": "Fake", "part": {"head": "new"}},`),
+			want: []string{`+ node new`},
+		},
+		{
+			desc: "part config",
+			edit: replacer(`"head": "dst"`, `"head": "dest"`),
+			want: []string{`~ node dst: part changed`},
+		},
+		{
+			desc: "renamed node and channel",
+			edit: replacer(`"src": {`, `"source": {`, `"c"`, `"d"`),
+			want: []string{
+				`~ node src renamed to source`,
+				`~ channel c renamed to d`,
+			},
+		},
+		{
+			desc: "removed channel",
+			edit: replacer(`"connections": {"in": "c"}`, `"connections": {}`, `"c": {"cap": 0}`, ``, `"connections": {"out": "c"}`, `"connections": {}`),
+			want: []string{
+				`~ node dst: pin in: "c" -> ""`,
+				`~ node src: pin out: "c" -> ""`,
+				`- channel c`,
+			},
+		},
+		{
+			desc: "parameters",
+			edit: replacer(`"channels": {`, `"parameters": {
+		"size": {"type": "int", "default": "8"}
+	},
+	"channels": {`),
+			want: []string{
+				`+ parameter size`,
+			},
+		},
+	}
+	for _, test := range tests {
+		a, b := diffGraph(t, nil), diffGraph(t, test.edit)
+		cs, err := Diff(a, b)
+		if err != nil {
+			t.Errorf("%s: Diff() = error %v", test.desc, err)
+			continue
+		}
+		if got, want := changeStrings(cs), strings.Join(test.want, "\n"); got != want {
+			t.Errorf("%s: Diff() =\n%s\nwant\n%s", test.desc, got, want)
+		}
+	}
+}
+
+func conflictStrings(cs []Conflict) string {
+	s := make([]string, len(cs))
+	for i, c := range cs {
+		s[i] = c.String()
+	}
+	return strings.Join(s, "\n")
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		desc          string
+		ours, theirs  func(string) string
+		wantConflicts []string
+		check         func(*Graph) string
+	}{
+		{
+			desc:   "independent changes",
+			ours:   replacer(`"name": "g"`, `"name": "h"`),
+			theirs: replacer(`"cap": 0`, `"cap": 3`),
+			check: func(g *Graph) string {
+				if g.Name != "h" || g.Channels["c"].Capacity != 3 {
+					return "want name h and cap 3"
+				}
+				return ""
+			},
+		},
+		{
+			desc: "rename and change",
+			ours: replacer(`"src": {`, `"source": {`),
+			theirs: replacer(`"enabled": true,
+			"x": 10`, `"enabled": true, "comment": "hi",
+			"x": 10`),
+			check: func(g *Graph) string {
+				n := g.Nodes["source"]
+				if n == nil || n.Comment != "hi" || g.Nodes["src"] != nil {
+					return "want src renamed to source, with comment hi"
+				}
+				if !g.Channels["c"].HasPin("source", "out") {
+					return "want source.out connected to c"
+				}
+				return ""
+			},
+		},
+		{
+			desc:          "same field",
+			ours:          replacer(`"cap": 0`, `"cap": 1`),
+			theirs:        replacer(`"cap": 0`, `"cap": 2`),
+			wantConflicts: []string{`channel c: cap: base "", ours "1", theirs "2"`},
+			check: func(g *Graph) string {
+				if g.Channels["c"].Capacity != 1 {
+					return "want ours (cap 1)"
+				}
+				return ""
+			},
+		},
+		{
+			desc:   "parameters",
+			ours:   replacer(`"channels": {`, `"parameters": {"size": {"type": "int", "default": "8"}}, "channels": {`),
+			theirs: replacer(`"channels": {`, `"parameters": {"size": {"type": "int", "help": "cache size"}}, "channels": {`),
+			check: func(g *Graph) string {
+				p := g.Parameters["size"]
+				if p == nil || p.Name != "size" || p.Type != "int" || p.Default != "8" || p.Help != "cache size" {
+					return fmt.Sprintf("parameter size = %+v, want int, default 8, help \"cache size\"", p)
+				}
+				return ""
+			},
+		},
+		{
+			desc:   "both moved",
+			ours:   replacer(`"x": 10`, `"x": 11`),
+			theirs: replacer(`"x": 10`, `"x": 12`),
+		},
+		{
+			desc:   "connected to removed channel",
+			ours:   replacer(`"connections": {"in": "c"}`, `"connections": {}`, `"connections": {"out": "c"}`, `"connections": {}`, `"c": {"cap": 0}`, ``),
+			theirs: replacer(`"pins": {"in"`, `"pins": {"in2": {"type": "int", "dir": "in"}, "in"`, `"connections": {"in": "c"}`, `"connections": {"in": "c", "in2": "c"}`),
+			wantConflicts: []string{
+				`node dst: pin in2: connected to channel "c", which was removed`,
+			},
+		},
+	}
+	for _, test := range tests {
+		base, ours, theirs := diffGraph(t, nil), diffGraph(t, test.ours), diffGraph(t, test.theirs)
+		g, cs, err := Merge(base, ours, theirs)
+		if err != nil {
+			t.Errorf("%s: Merge() = error %v", test.desc, err)
+			continue
+		}
+		if got, want := conflictStrings(cs), strings.Join(test.wantConflicts, "\n"); got != want {
+			t.Errorf("%s: Merge() conflicts =\n%s\nwant\n%s", test.desc, got, want)
+		}
+		if test.check != nil {
+			if msg := test.check(g); msg != "" {
+				t.Errorf("%s: Merge() graph: %s", test.desc, msg)
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+// edge is a connection from an output pin to an input pin by a channel,
+// for drawing the graph.
+type edge struct {
+	from, to string
+	channel  *Channel
+}
+
+// edges returns the edges of the graph, sorted by channel then node names.
+// A channel with several writers or readers yields an edge from each writer
+// to each reader. Requires InferTypes to have been called.
+func (g *Graph) edges() []edge {
+	var es []edge
+	for _, cn := range sortedChannelNames(g.Channels) {
+		c := g.Channels[cn]
+		var from, to []string
+		for np := range c.Pins {
+			n := g.Nodes[np.Node]
+			if n == nil {
+				continue
+			}
+			switch n.Part.Pins()[np.Pin].Direction {
+			case pin.Output:
+				from = append(from, np.Node)
+			case pin.Input:
+				to = append(to, np.Node)
+			}
+		}
+		sort.Strings(from)
+		sort.Strings(to)
+		for _, f := range from {
+			for _, t := range to {
+				es = append(es, edge{from: f, to: t, channel: c})
+			}
+		}
+	}
+	return es
+}
+
+// label returns the lines describing the channel: its name, and then its
+// type and capacity.
+func (e edge) label() []string {
+	typ := "?"
+	if e.channel.Type != nil {
+		typ = e.channel.Type.String()
+	}
+	return []string{e.channel.Name, fmt.Sprintf("%s, cap %d", typ, e.channel.Capacity)}
+}
+
+// WriteDOTTo writes the graph in the Graphviz DOT language to the io.Writer.
+// Nodes are labelled by name and part type, and disabled nodes are dashed.
+// Edges are labelled by channel name, type, and capacity.
+func (g *Graph) WriteDOTTo(w io.Writer) error {
+	if err := g.InferTypes(); err != nil {
+		return err
+	}
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "digraph %s {\n", strconv.Quote(g.Name))
+	buf.WriteString("\trankdir=LR;\n\tnode [shape=box];\n")
+	for _, nn := range sortedNodeNames(g.Nodes) {
+		n := g.Nodes[nn]
+		fmt.Fprintf(buf, "\t%s [label=%s", strconv.Quote(nn), dotLabel(nn, n.Part.TypeKey()))
+		if !n.Enabled {
+			buf.WriteString(", style=dashed, fontcolor=gray")
+		}
+		buf.WriteString("];\n")
+	}
+	for _, e := range g.edges() {
+		fmt.Fprintf(buf, "\t%s -> %s [label=%s];\n", strconv.Quote(e.from), strconv.Quote(e.to), dotLabel(e.label()...))
+	}
+	buf.WriteString("}\n")
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// DOT returns the graph in the Graphviz DOT language.
+func (g *Graph) DOT() (string, error) {
+	buf := &bytes.Buffer{}
+	if err := g.WriteDOTTo(buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// dotLabel quotes lines as a DOT string, with centred line breaks.
+func dotLabel(lines ...string) string {
+	q := make([]string, len(lines))
+	for i, l := range lines {
+		ql := strconv.Quote(l)
+		q[i] = ql[1 : len(ql)-1]
+	}
+	return `"` + strings.Join(q, `\n`) + `"`
+}
+
+// WriteMermaidTo writes the graph as a Mermaid flowchart to the io.Writer.
+// Nodes are labelled by name and part type, and disabled nodes are dashed.
+// Edges are labelled by channel name, type, and capacity.
+func (g *Graph) WriteMermaidTo(w io.Writer) error {
+	if err := g.InferTypes(); err != nil {
+		return err
+	}
+	// Mermaid IDs are restrictive, so number the nodes.
+	names := sortedNodeNames(g.Nodes)
+	ids := make(map[string]string, len(names))
+	buf := &bytes.Buffer{}
+	buf.WriteString("flowchart LR\n")
+	for i, nn := range names {
+		id := "n" + strconv.Itoa(i)
+		ids[nn] = id
+		fmt.Fprintf(buf, "\t%s[%s]\n", id, mermaidLabel(nn, g.Nodes[nn].Part.TypeKey()))
+	}
+	for _, e := range g.edges() {
+		fmt.Fprintf(buf, "\t%s -->|%s| %s\n", ids[e.from], mermaidLabel(e.label()...), ids[e.to])
+	}
+	var disabled []string
+	for _, nn := range names {
+		if !g.Nodes[nn].Enabled {
+			disabled = append(disabled, ids[nn])
+		}
+	}
+	if len(disabled) > 0 {
+		buf.WriteString("\tclassDef disabled stroke-dasharray: 5 5, color: gray\n")
+		fmt.Fprintf(buf, "\tclass %s disabled\n", strings.Join(disabled, ","))
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// Mermaid returns the graph as a Mermaid flowchart.
+func (g *Graph) Mermaid() (string, error) {
+	buf := &bytes.Buffer{}
+	if err := g.WriteMermaidTo(buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// mermaidEscaper escapes text for a quoted Mermaid label, using Mermaid's
+// entity codes.
+var mermaidEscaper = strings.NewReplacer(
+	"#", "#35;",
+	`"`, "#quot;",
+	"&", "#amp;",
+	"<", "#lt;",
+	">", "#gt;",
+)
+
+// mermaidLabel quotes lines as a Mermaid label.
+func mermaidLabel(lines ...string) string {
+	q := make([]string, len(lines))
+	for i, l := range lines {
+		q[i] = mermaidEscaper.Replace(l)
+	}
+	return `"` + strings.Join(q, "<br/>") + `"`
+}
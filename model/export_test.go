@@ -0,0 +1,68 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"testing"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+func exportTestGraph() *Graph {
+	out := &pin.Definition{Name: "out", Type: "int", Direction: pin.Output}
+	in := &pin.Definition{Name: "in", Type: "$T", Direction: pin.Input}
+	return testGraph("pipe", []*Node{
+		fakeNode("gen", true, "1", map[string]string{"out": "nums"}, out),
+		fakeNode("sink", true, "1", map[string]string{"in": "nums"}, in),
+		fakeNode(`"off"`, false, "1", map[string]string{"in": "nil"}, in),
+	}, &Channel{Name: "nums", Capacity: 2})
+}
+
+func TestDOT(t *testing.T) {
+	got, err := exportTestGraph().DOT()
+	if err != nil {
+		t.Fatalf("DOT() error = %v", err)
+	}
+	want := `digraph "pipe" {
+	rankdir=LR;
+	node [shape=box];
+	"\"off\"" [label="\"off\"\nFake", style=dashed, fontcolor=gray];
+	"gen" [label="gen\nFake"];
+	"sink" [label="sink\nFake"];
+	"gen" -> "sink" [label="nums\nint, cap 2"];
+}
+`
+	if got != want {
+		t.Errorf("DOT() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	got, err := exportTestGraph().Mermaid()
+	if err != nil {
+		t.Fatalf("Mermaid() error = %v", err)
+	}
+	want := `flowchart LR
+	n0["#quot;off#quot;<br/>Fake"]
+	n1["gen<br/>Fake"]
+	n2["sink<br/>Fake"]
+	n1 -->|"nums<br/>int, cap 2"| n2
+	classDef disabled stroke-dasharray: 5 5, color: gray
+	class n0 disabled
+`
+	if got != want {
+		t.Errorf("Mermaid() =\n%s\nwant\n%s", got, want)
+	}
+}
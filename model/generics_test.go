@@ -0,0 +1,99 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+// fakeGenericPart is a FakePart that also has a GenericImpl.
+type fakeGenericPart struct {
+	FakePart
+}
+
+func (f *fakeGenericPart) Clone() Part { f2 := *f; return &f2 }
+
+func (f *fakeGenericPart) GenericImpl(*Node) PartImpl {
+	return PartImpl{
+		Imports: []string{`"` + GenericPackagePath + `"`},
+		Body:    "generic.Unbatch(input, output)",
+	}
+}
+
+func genericsGraph(generics bool) *Graph {
+	g := testGraph("generics", []*Node{{
+		Part: &fakeGenericPart{FakePart{nil, "", "fake.Unbatch(input, output)", "", pin.NewMap(
+			&pin.Definition{Name: "input", Type: "[]int", Direction: pin.Input},
+			&pin.Definition{Name: "output", Type: "int", Direction: pin.Output},
+		)}},
+		Name:         "unbatch",
+		Enabled:      true,
+		Multiplicity: "1",
+		Connections:  map[string]string{"input": "nil", "output": "nil"},
+	}})
+	g.Generics = generics
+	return g
+}
+
+func TestGoGenerics(t *testing.T) {
+	for _, generics := range []bool{false, true} {
+		g := genericsGraph(generics)
+		src, err := g.Go()
+		if err != nil {
+			t.Fatalf("Go() with Generics = %t = error %v", generics, err)
+		}
+		files, err := g.GoFiles()
+		if err != nil {
+			t.Fatalf("GoFiles() with Generics = %t = error %v", generics, err)
+		}
+		srcs := []string{src}
+		for _, f := range files {
+			srcs = append(srcs, string(f))
+		}
+		for _, s := range srcs {
+			if got := strings.HasPrefix(s, "//go:build go1.18\n"); got != generics {
+				t.Errorf("source with Generics = %t has build constraint = %t, want %t; got:\n%s", generics, got, generics, s)
+			}
+		}
+		want, notWant := "fake.Unbatch(input, output)", "generic.Unbatch(input, output)"
+		if generics {
+			want, notWant = notWant, want
+		}
+		if !strings.Contains(src, want) {
+			t.Errorf("Go() with Generics = %t does not contain %q; got:\n%s", generics, want, src)
+		}
+		if strings.Contains(src, notWant) {
+			t.Errorf("Go() with Generics = %t contains %q", generics, notWant)
+		}
+	}
+}
+
+func TestCheckGenerics(t *testing.T) {
+	// Check looks for deadlocks in the code that would be generated.
+	for _, generics := range []bool{false, true} {
+		g := genericsGraph(generics)
+		g.Check()
+		want := "fake.Unbatch(input, output)"
+		if generics {
+			want = "generic.Unbatch(input, output)"
+		}
+		if got := g.Nodes["unbatch"].Impl.Body; got != want {
+			t.Errorf("after Check() with Generics = %t, Impl.Body = %q, want %q", generics, got, want)
+		}
+	}
+}
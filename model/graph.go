@@ -16,11 +16,15 @@ package model
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/google/shenzhen-go/model/pin"
 	"github.com/google/shenzhen-go/source"
 )
 
@@ -28,15 +32,23 @@ var typeEmptyInterface = source.MustNewType("", "interface{}")
 
 // Graph represents a package / program / collection of nodes and channels.
 type Graph struct {
-	FilePath    string              `json:"-"` // path to the JSON source
-	URLPath     string              `json:"-"` // path in the URL
-	Name        string              `json:"name"`
-	PackagePath string              `json:"package_path"`
-	IsCommand   bool                `json:"is_command"`
-	Nodes       map[string]*Node    `json:"nodes"`    // name -> node
-	Channels    map[string]*Channel `json:"channels"` // name -> channel
+	FilePath    string                `json:"-"` // path to the JSON source
+	URLPath     string                `json:"-"` // path in the URL
+	Name        string                `json:"name"`
+	PackagePath string                `json:"package_path"`
+	IsCommand   bool                  `json:"is_command"`
+	UseContext  bool                  `json:"use_context,omitempty"` // generate Run(ctx context.Context) error
+	Telemetry   bool                  `json:"telemetry,omitempty"`   // generate code that reports channel and node activity
+	OutputDir   string                `json:"output_dir,omitempty"`  // relative to the directory of FilePath
+	Generics    bool                  `json:"generics,omitempty"`    // implement standard parts with generic functions (Go 1.18+)
+	Nodes       map[string]*Node      `json:"nodes"`                 // name -> node
+	Channels    map[string]*Channel   `json:"channels"`              // name -> channel
+	Parameters  map[string]*Parameter `json:"parameters,omitempty"`  // name -> parameter
 
 	types source.TypeInferenceMap
+
+	migrated     bool // upgraded from an older format version by LoadJSON
+	migratedFrom int
 }
 
 // NewGraph returns a new empty graph associated with a file path.
@@ -50,14 +62,30 @@ func NewGraph(filePath, urlPath, pkgPath string) *Graph {
 	}
 }
 
-// LoadJSON loads a JSON-encoded Graph from an io.Reader.
+// LoadJSON loads a JSON-encoded Graph from an io.Reader. Graphs in older
+// format versions are upgraded to the current version (see Migrated).
 func LoadJSON(r io.Reader, filePath, urlPath string) (*Graph, error) {
 	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var raw map[string]interface{}
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	from, err := migrate(raw)
+	if err != nil {
+		return nil, err
+	}
+	j, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
 	g := &Graph{
-		FilePath: filePath,
-		URLPath:  urlPath,
+		FilePath:     filePath,
+		URLPath:      urlPath,
+		migrated:     from < FormatVersion,
+		migratedFrom: from,
 	}
-	if err := dec.Decode(g); err != nil {
+	if err := json.Unmarshal(j, g); err != nil {
 		return nil, err
 	}
 	// Each node and channel should cache it's own name.
@@ -67,6 +95,9 @@ func LoadJSON(r io.Reader, filePath, urlPath string) (*Graph, error) {
 	for k, n := range g.Nodes {
 		n.Name = k
 	}
+	for k, p := range g.Parameters {
+		p.Name = k
+	}
 	// Set up channel pin caches.
 	g.RefreshChannelsPins()
 	// As a safety mechanism, cull any connections to channels that don't exist.
@@ -89,23 +120,183 @@ func (g *Graph) PackageName() string {
 	return g.PackagePath[i+1:]
 }
 
-// AllImports combines all desired imports into one slice.
-// It doesn't fix conflicting names, but dedupes any whole lines,
-// trims whitespace and removes blank lines. go/format will put
-// them in sorted order later.
-// TODO: Put nodes in separate files to solve all import issues.
+// AllImports combines all desired imports into one slice, for writing
+// the whole package as one file.
+// It dedupes any whole lines, trims whitespace and removes blank lines.
+// go/format will put them in sorted order later. Conflicting names are
+// fixed beforehand by WriteRawGoTo (see resolveImportConflicts), and
+// GoFiles avoids conflicts by putting each node in a separate file.
 func (g *Graph) AllImports() []string {
-	m := source.NewStringSet(`"runtime"`, `"sync"`)
+	m := g.mainImports()
+	m.Add(`"runtime"`)
 	for _, n := range g.Nodes {
-		for _, i := range n.Impl.Imports {
-			j := strings.TrimSpace(i)
-			if j == "" {
+		addImports(m, n.Impl.Imports)
+	}
+	return m.Slice()
+}
+
+// MainImports returns the imports needed by the file containing main or Run.
+func (g *Graph) MainImports() []string {
+	return g.mainImports().Slice()
+}
+
+func (g *Graph) mainImports() source.StringSet {
+	m := g.fixedImports()
+	// Channel types and port types are used in Run.
+	for _, c := range g.Channels {
+		g.addQualifierImports(m, c.Type)
+	}
+	for _, p := range g.Ports() {
+		g.addQualifierImports(m, p.Node.PinTypes[p.Node.Part.(Port).PortPin()])
+	}
+	return m
+}
+
+// fixedImports returns the imports used by the generated main or Run, and
+// the supervisor, rather than by the code of any one node.
+func (g *Graph) fixedImports() source.StringSet {
+	m := source.NewStringSet(`"sync"`)
+	if g.Telemetry {
+		m.Add(strconv.Quote(TelemetryPackagePath))
+	}
+	if len(g.Parameters) > 0 && g.IsCommand {
+		m.Add(`"flag"`)
+	}
+	for _, p := range g.Parameters {
+		if p.Type == "time.Duration" {
+			m.Add(`"time"`)
+			break
+		}
+	}
+	if g.Supervised() {
+		m.Add(`"log"`)
+		m.Add(`"time"`)
+		if g.UseContext {
+			m.Add(`"fmt"`)
+		}
+	}
+	if g.RestartMetrics() {
+		m.Add(`"github.com/prometheus/client_golang/prometheus"`)
+		m.Add(`"strconv"`)
+	}
+	if g.UseContext {
+		m.Add(`"context"`)
+		if g.IsCommand {
+			for _, i := range []string{`"fmt"`, `"os"`, `"os/signal"`, `"syscall"`} {
+				m.Add(i)
+			}
+		}
+	}
+	return m
+}
+
+var majorVersionRE = regexp.MustCompile(`^v[0-9]+$|\.v[0-9]+$`)
+
+// importName returns the name an import line binds in a file: the alias,
+// if any, or otherwise a guess based on the last element of the path.
+func importName(imp string) string {
+	f := strings.Fields(imp)
+	switch len(f) {
+	case 1:
+		// Guess below.
+	case 2:
+		return f[0]
+	default:
+		return ""
+	}
+	p, err := strconv.Unquote(f[0])
+	if err != nil {
+		return ""
+	}
+	name := path.Base(p)
+	if majorVersionRE.MatchString(name) {
+		// e.g. "example.com/foo/v2", or "gopkg.in/foo.v1".
+		if name[0] == 'v' && path.Dir(p) != "." {
+			name = path.Base(path.Dir(p))
+		}
+		name = majorVersionRE.ReplaceAllString(name, "")
+	}
+	return strings.TrimPrefix(name, "go-")
+}
+
+// addQualifierImports adds, for each qualified identifier in the types,
+// the import line from the originating node that binds the qualifier.
+func (g *Graph) addQualifierImports(m source.StringSet, types ...*source.Type) {
+	for _, t := range types {
+		if t == nil {
+			continue
+		}
+		for sq := range t.ScopedQualifiers() {
+			n := g.Nodes[sq.Scope]
+			if n == nil {
 				continue
 			}
-			m.Add(j)
+			for _, i := range n.Impl.Imports {
+				if j := strings.TrimSpace(i); importName(j) == sq.Qual {
+					m.Add(j)
+					break
+				}
+			}
 		}
 	}
-	return m.Slice()
+}
+
+// addImports adds import lines to m, trimming whitespace and skipping
+// blank lines.
+func addImports(m source.StringSet, imps []string) {
+	for _, i := range imps {
+		j := strings.TrimSpace(i)
+		if j == "" {
+			continue
+		}
+		m.Add(j)
+	}
+}
+
+// RunArgs returns the arguments for calling the function for the node
+// from Run: the config, if the graph has parameters, and then the channels
+// connected to each pin, in order of pin name. With Telemetry, output pins
+// are given the relay for the channel.
+func (g *Graph) RunArgs(n *Node) string {
+	pins := n.Part.Pins()
+	names := make([]string, 0, len(pins))
+	for pn := range pins {
+		names = append(names, pn)
+	}
+	sort.Strings(names)
+	args := make([]string, 0, len(pins)+1)
+	if len(g.Parameters) > 0 {
+		args = append(args, "config")
+	}
+	portChans := g.PortChannels()
+	for _, pn := range names {
+		cn := n.Connections[pn]
+		if g.Telemetry && pins[pn].Direction == pin.Output && g.Channels[cn] != nil && portChans[cn] == "" {
+			cn = "send." + cn
+		}
+		args = append(args, cn)
+	}
+	return strings.Join(args, ", ")
+}
+
+// sortedNodeNames returns the names of the nodes in sorted order.
+func sortedNodeNames(nodes map[string]*Node) []string {
+	names := make([]string, 0, len(nodes))
+	for nn := range nodes {
+		names = append(names, nn)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedChannelNames returns the names of the channels in sorted order.
+func sortedChannelNames(channels map[string]*Channel) []string {
+	names := make([]string, 0, len(channels))
+	for cn := range channels {
+		names = append(names, cn)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // Inits returns a map of part type keys to init sections for those parts that need it.
@@ -186,12 +377,6 @@ func (g *Graph) RenameNode(n *Node, newName string) {
 	n.Name = newName
 }
 
-// Check checks over the graph for any errors.
-func (g *Graph) Check() error {
-	// TODO: implement
-	return errors.New("not implemented")
-}
-
 // RefreshChannelsPins refreshes the Pins cache of all channels.
 // Use this when node names or pin definitions might have changed.
 func (g *Graph) RefreshChannelsPins() {
@@ -236,6 +421,7 @@ func (g *Graph) InferTypes() error {
 	// begin as their basic definition, params scoped to the node.
 	// The types map should start with all type parameters set to nil.
 	g.types = make(source.TypeInferenceMap)
+	var cons []pinConstraint
 	for _, n := range g.Nodes {
 		pins := n.Part.Pins()
 		n.PinTypes = make(map[string]*source.Type, len(pins))
@@ -246,6 +432,11 @@ func (g *Graph) InferTypes() error {
 			}
 			n.PinTypes[pn] = pt
 			g.types.Note(pt)
+			for tp, cs := range pt.Constraints() {
+				for _, c := range cs {
+					cons = append(cons, pinConstraint{NodePin{n.Name, pn}, tp, c})
+				}
+			}
 		}
 	}
 
@@ -270,7 +461,20 @@ func (g *Graph) InferTypes() error {
 		}
 	}
 
+	// Parameters that couldn't be inferred need a default satisfying
+	// their constraints, if interface{} won't do.
+	for _, pc := range cons {
+		if g.types[pc.param] != nil {
+			continue
+		}
+		if d := pc.constraint.Default(); d != nil {
+			g.types[pc.param] = d
+		}
+	}
 	g.types.ApplyDefault(typeEmptyInterface)
+	if err := g.checkConstraints(cons); err != nil {
+		return err
+	}
 
 	// Refine all types one final time.
 	for _, c := range g.Channels {
@@ -290,6 +494,48 @@ func (g *Graph) InferTypes() error {
 	return nil
 }
 
+// pinConstraint is a constraint on a type parameter, written in the type
+// of a pin.
+type pinConstraint struct {
+	pin        NodePin
+	param      source.TypeParam
+	constraint *source.Constraint
+}
+
+// checkConstraints checks the inferred types satisfy the constraints.
+// The first unsatisfied constraint, in order of node and pin, is reported.
+func (g *Graph) checkConstraints(cons []pinConstraint) error {
+	sort.Slice(cons, func(i, j int) bool {
+		a, b := cons[i].pin, cons[j].pin
+		if a.Node != b.Node {
+			return a.Node < b.Node
+		}
+		return a.Pin < b.Pin
+	})
+	for _, pc := range cons {
+		// The inferred type may itself use other inferred parameters.
+		t := g.types[pc.param]
+		_, err := t.Refine(g.types)
+		if err == nil {
+			err = pc.constraint.SatisfiedBy(t)
+		}
+		if err == nil {
+			continue
+		}
+		te := &TypeIncompatibilityError{
+			Summary: fmt.Sprintf("%s at %q has an unsuitable type", pc.param.Ident, pc.pin),
+			Source:  err,
+			Pin:     pc.pin,
+		}
+		if c := g.Channels[g.Nodes[pc.pin.Node].Connections[pc.pin.Pin]]; c != nil {
+			te.Summary = fmt.Sprintf("channel %q gives %s at %q an unsuitable type", c.Name, pc.param.Ident, pc.pin)
+			te.Channel = c
+		}
+		return te
+	}
+	return nil
+}
+
 // next contains any channels that might be inferrable
 // as a result of making improvement on this channel's type.
 func (g *Graph) inferAndRefineChan(c *Channel) (map[*Channel]struct{}, error) {
@@ -435,3 +435,51 @@ func TestInferTypes10Chain(t *testing.T) {
 		}
 	}
 }
+
+func TestInferTypesConstraints(t *testing.T) {
+	graph := func(srcType string) *Graph {
+		g := &Graph{
+			Name: "constraints",
+			Nodes: map[string]*Node{
+				"src": fakeNode("src", true, "1", map[string]string{"output": "c"},
+					&pin.Definition{Name: "output", Type: srcType, Direction: pin.Output},
+				),
+				"counter": fakeNode("counter", true, "1", map[string]string{"input": "c"},
+					&pin.Definition{Name: "input", Type: "$Key:comparable", Direction: pin.Input},
+					&pin.Definition{Name: "result", Type: "map[$Key]uint", Direction: pin.Output},
+					&pin.Definition{Name: "count", Type: "$N:integer", Direction: pin.Output},
+				),
+			},
+			Channels: map[string]*Channel{
+				"c": {Name: "c"},
+			},
+		}
+		g.RefreshChannelsPins()
+		return g
+	}
+
+	g := graph("struct{ A string; B [2]int }")
+	if err := g.InferTypes(); err != nil {
+		t.Fatalf("InferTypes() = error %v", err)
+	}
+	if got, want := g.Nodes["counter"].PinTypes["result"].String(), "map[struct{A string; B [2]int}]uint"; got != want {
+		t.Errorf("Nodes[counter].PinTypes[result] = %q, want %q", got, want)
+	}
+	// $N couldn't be inferred, so it gets a default satisfying integer.
+	if got, want := g.Nodes["counter"].PinTypes["count"].String(), "int"; got != want {
+		t.Errorf("Nodes[counter].PinTypes[count] = %s, want %s", got, want)
+	}
+
+	g = graph("[]string")
+	err := g.InferTypes()
+	te, ok := err.(*TypeIncompatibilityError)
+	if !ok {
+		t.Fatalf("InferTypes() = error %v, want *TypeIncompatibilityError", err)
+	}
+	if got, want := te.Error(), `channel "c" gives $Key at "counter.input" an unsuitable type: []string does not satisfy comparable`; got != want {
+		t.Errorf("InferTypes() error = %q, want %q", got, want)
+	}
+	if te.Channel != g.Channels["c"] || te.Pin != (NodePin{"counter", "input"}) {
+		t.Errorf("InferTypes() error has channel %v, pin %v; want c, counter.input", te.Channel, te.Pin)
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// importPath returns the unquoted path of an import line, or "" if the
+// line can't be understood.
+func importPath(imp string) string {
+	f := strings.Fields(imp)
+	if len(f) == 0 || len(f) > 2 {
+		return ""
+	}
+	p, err := strconv.Unquote(f[len(f)-1])
+	if err != nil {
+		return ""
+	}
+	return p
+}
+
+// resolveImportConflicts finds imports of different packages that bind the
+// same name, and gives all but the first an alias. The import lines, code,
+// and types of each affected node are rewritten to use the alias.
+// This is needed when the whole package is written as one file.
+// Requires InferTypes to have been called, and refreshes each Impl.
+func (g *Graph) resolveImportConflicts() error {
+	for _, n := range g.Nodes {
+		g.refreshImpl(n)
+	}
+
+	bound := make(map[string]string) // name -> path
+	for _, i := range append(g.fixedImports().Slice(), `"runtime"`) {
+		bound[importName(i)] = importPath(i)
+	}
+	// Other identifiers at package scope must not be used as aliases.
+	used := map[string]bool{"main": true, "Run": true, "Config": true, "DefaultConfig": true}
+	for _, n := range g.Nodes {
+		used[n.Identifier()] = true
+	}
+	for cn := range g.Channels {
+		used[cn] = true
+	}
+
+	// Nodes needing an init section claim their names first, because the
+	// init section is shared between nodes of the part type and can't be
+	// rewritten per node. After that, nodes claim names in sorted order.
+	names := sortedNodeNames(g.Nodes)
+	sort.SliceStable(names, func(i, j int) bool {
+		return g.Nodes[names[i]].Impl.NeedsInit && !g.Nodes[names[j]].Impl.NeedsInit
+	})
+	type rename struct {
+		line     int // index into Impl.Imports
+		old, new string
+	}
+	renames := make(map[*Node][]rename)
+	for _, nn := range names {
+		n := g.Nodes[nn]
+		if n.IsPort() {
+			continue
+		}
+		for k, imp := range n.Impl.Imports {
+			name, p := importName(imp), importPath(imp)
+			if name == "" || name == "_" || name == "." || p == "" {
+				continue
+			}
+			if bp, ok := bound[name]; !ok || bp == p {
+				bound[name] = p
+				continue
+			}
+			alias := name
+			for i := 2; bound[alias] != "" || used[alias]; i++ {
+				alias = name + strconv.Itoa(i)
+			}
+			bound[alias] = p
+			renames[n] = append(renames[n], rename{k, name, alias})
+			g.renameTypeQualifier(nn, name, alias)
+		}
+	}
+	if len(renames) == 0 {
+		return nil
+	}
+
+	// Types from renamed imports may have been expanded into the code of
+	// any node, so refresh them all before renaming within the code.
+	for _, n := range g.Nodes {
+		g.refreshImpl(n)
+	}
+	for n, rs := range renames {
+		// Impl.Imports may be shared with the part, so copy it before
+		// rewriting any lines.
+		n.Impl.Imports = append([]string(nil), n.Impl.Imports...)
+		for _, r := range rs {
+			if err := n.renameQualifier(r.old, r.new); err != nil {
+				return fmt.Errorf("node %q: cannot rename import %s to %s: %v", n.Name, n.Impl.Imports[r.line], r.new, err)
+			}
+			n.Impl.Imports[r.line] = r.new + " " + strconv.Quote(importPath(n.Impl.Imports[r.line]))
+		}
+	}
+	return nil
+}
+
+// implSections returns the Head, Tail, and Body of the node's Impl, in the
+// order they are arranged by parseImpl.
+func (n *Node) implSections() []*string {
+	return []*string{&n.Impl.Head, &n.Impl.Tail, &n.Impl.Body}
+}
+
+// parseImpl parses the Head, Tail, and Body of the node's Impl, arranged the
+// way the generated function would arrange them, so that declarations in
+// Head are in scope in Tail and Body. It returns the offset at which each
+// of the implSections starts.
+func (n *Node) parseImpl() (*token.FileSet, *ast.File, []int, error) {
+	sections := n.implSections()
+	params := []string{"ctx", "multiplicity", "instanceNumber"}
+	for pn := range n.Part.Pins() {
+		params = append(params, pn)
+	}
+	buf := bytes.NewBufferString("package p\n\nfunc _(" + strings.Join(params, ", ") + " int) (err error) {\n")
+	starts := make([]int, len(sections))
+	for i, sec := range sections {
+		if i == 1 {
+			buf.WriteString("func() {\n")
+		}
+		starts[i] = buf.Len()
+		buf.WriteString(*sec)
+		buf.WriteString("\n")
+		if i == 1 {
+			buf.WriteString("}()\n")
+		}
+	}
+	buf.WriteString("}\n")
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", buf.Bytes(), 0)
+	return fset, f, starts, err
+}
+
+// renameTypeQualifier renames the qualifier oldq to newq in all types in
+// the graph that originate in the given node.
+func (g *Graph) renameTypeQualifier(scope, oldq, newq string) {
+	for _, c := range g.Channels {
+		if c.Type != nil {
+			c.Type.RenameQualifier(scope, oldq, newq)
+		}
+	}
+	for _, n := range g.Nodes {
+		for _, t := range n.PinTypes {
+			t.RenameQualifier(scope, oldq, newq)
+		}
+		for _, t := range n.TypeParams {
+			t.RenameQualifier(scope, oldq, newq)
+		}
+	}
+}
+
+// renameQualifier rewrites qualified identifiers in the node's Head, Body,
+// and Tail that use the package name oldq, to use newq instead. Identifiers
+// that refer to something declared in the node (including pins) are not
+// package names, and are left alone.
+func (n *Node) renameQualifier(oldq, newq string) error {
+	sections := n.implSections()
+	fset, f, starts, err := n.parseImpl()
+	if err != nil {
+		return err
+	}
+	// Offsets of each identifier to rename, per section.
+	offsets := make([][]int, len(sections))
+	ast.Inspect(f, func(x ast.Node) bool {
+		sel, ok := x.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		id, ok := sel.X.(*ast.Ident)
+		// Package names are unresolved by the parser.
+		if !ok || id.Name != oldq || id.Obj != nil {
+			return true
+		}
+		off := fset.Position(id.Pos()).Offset
+		for i := len(sections) - 1; i >= 0; i-- {
+			if off >= starts[i] {
+				offsets[i] = append(offsets[i], off-starts[i])
+				break
+			}
+		}
+		return true
+	})
+	for i, sec := range sections {
+		s := *sec
+		// Splice from the end, so earlier offsets stay valid.
+		sort.Ints(offsets[i])
+		for j := len(offsets[i]) - 1; j >= 0; j-- {
+			o := offsets[i][j]
+			s = s[:o] + newq + s[o+len(oldq):]
+		}
+		*sec = s
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+func TestImportPath(t *testing.T) {
+	tests := map[string]string{
+		`"fmt"`:                 "fmt",
+		` "html/template" `:     "html/template",
+		`tt "text/template"`:    "text/template",
+		`_ "net/http/pprof"`:    "net/http/pprof",
+		`not quoted`:            "",
+		`a b "too/many/fields"`: "",
+		``:                      "",
+	}
+	for imp, want := range tests {
+		if got := importPath(imp); got != want {
+			t.Errorf("importPath(%q) = %q, want %q", imp, got, want)
+		}
+	}
+}
+
+func TestGoResolvesImportConflicts(t *testing.T) {
+	a := fakeNode("a", true, "1", map[string]string{"output": "c"},
+		&pin.Definition{Name: "output", Type: "template.HTML", Direction: pin.Output},
+	)
+	a.Part.(*FakePart).Impts = []string{`"html/template"`}
+	a.Part.(*FakePart).Body = `output <- template.HTML("<b>hi</b>")`
+	b := fakeNode("b", true, "1", map[string]string{"input": "c"},
+		&pin.Definition{Name: "input", Type: "$T", Direction: pin.Input},
+	)
+	b.Part.(*FakePart).Impts = []string{`"text/template"`, `"sync"`}
+	b.Part.(*FakePart).Head = "var s struct{ template, sync int }"
+	b.Part.(*FakePart).Body = `t := template.Must(template.New("b").Parse(string(<-input)))
+_, _, _ = t, s.template, s.sync`
+	c := fakeNode("c", true, "1", nil)
+	c.Part.(*FakePart).Impts = []string{`"example.com/sync"`}
+	c.Part.(*FakePart).Body = "sync.Do()"
+	g := testGraph("conflicts", []*Node{a, b, c}, &Channel{Name: "c"})
+
+	src, err := g.Go()
+	if err != nil {
+		t.Fatalf("Go() = error %v", err)
+	}
+	for _, want := range []string{
+		`"html/template"`,
+		`template2 "text/template"`,
+		`sync2 "example.com/sync"`,
+		`output <- template.HTML("<b>hi</b>")`,
+		`func b(input <-chan template.HTML)`,
+		`template2.Must(template2.New("b")`,
+		`s.template, s.sync`,
+		`sync2.Do()`,
+		`make(chan template.HTML, 0)`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Go() does not contain %q; got:\n%s", want, src)
+		}
+	}
+	// The parts must not have been changed.
+	if got, want := b.Part.(*FakePart).Impts[0], `"text/template"`; got != want {
+		t.Errorf("b.Part.Impts[0] = %q, want %q", got, want)
+	}
+}
+
+func TestGoResolvesImportConflictsInTypes(t *testing.T) {
+	// Node a's "template" is renamed, and its type flows into node b.
+	a := fakeNode("a", true, "1", map[string]string{"output": "c"},
+		&pin.Definition{Name: "output", Type: "template.Template", Direction: pin.Output},
+	)
+	a.Part.(*FakePart).Impts = []string{`"text/template"`}
+	b := fakeNode("b", true, "1", map[string]string{"input": "c"},
+		&pin.Definition{Name: "input", Type: "$T", Direction: pin.Input},
+	)
+	z := fakeNode("Z", true, "1", nil)
+	z.Part.(*FakePart).Impts = []string{`"html/template"`}
+	g := testGraph("conflicts", []*Node{a, b, z}, &Channel{Name: "c"})
+
+	src, err := g.Go()
+	if err != nil {
+		t.Fatalf("Go() = error %v", err)
+	}
+	for _, want := range []string{
+		`"html/template"`,
+		`template2 "text/template"`,
+		`func a(output chan<- template2.Template)`,
+		`func b(input <-chan template2.Template)`,
+		`make(chan template2.Template, 0)`,
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Go() does not contain %q; got:\n%s", want, src)
+		}
+	}
+}
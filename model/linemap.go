@@ -0,0 +1,242 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CodeSection is one of the sections of code in a PartImpl.
+type CodeSection string
+
+// The sections of a PartImpl.
+const (
+	HeadSection CodeSection = "head"
+	BodySection CodeSection = "body"
+	TailSection CodeSection = "tail"
+)
+
+// Comments around each section of code in the raw generated Go, which
+// are removed after formatting to build a LineMap.
+const (
+	codeBeginMarker = "//shenzhen-go:code "
+	codeEndMarker   = "//shenzhen-go:end"
+)
+
+// markerLine returns a trimmed line of formatted Go with the spelling of a
+// code marker restored. gofmt adds a space after the slashes when markers
+// are next to each other, and so read as a doc comment.
+func markerLine(t string) string {
+	if strings.HasPrefix(t, "// shenzhen-go:") {
+		return "//" + strings.TrimPrefix(t, "// ")
+	}
+	return t
+}
+
+// CodePosition is a line in the code of a node.
+type CodePosition struct {
+	Node string
+
+	// Section and Line (counting from 1) locate the line within the
+	// PartImpl. Section is empty if the line is elsewhere in the file of
+	// the node, such as in the function signature.
+	Section CodeSection
+	Line    int
+}
+
+func (p CodePosition) String() string {
+	if p.Section == "" {
+		return fmt.Sprintf("node %q", p.Node)
+	}
+	return fmt.Sprintf("node %q %s line %d", p.Node, p.Section, p.Line)
+}
+
+// LineMap maps lines in generated Go files back to the code of nodes.
+type LineMap struct {
+	files     map[string][]*codeRange // by file name, sorted by start
+	nodeFiles map[string]string       // file name -> node name
+}
+
+// codeRange is where a section of code ended up in a generated file.
+type codeRange struct {
+	node    string
+	section CodeSection
+	start   int   // line in the generated file of the first line
+	lines   []int // lines in the section, for each line from start
+}
+
+// NewLineMap returns an empty LineMap.
+func NewLineMap() *LineMap {
+	return &LineMap{
+		files:     make(map[string][]*codeRange),
+		nodeFiles: make(map[string]string),
+	}
+}
+
+// Lookup returns the position in the code of a node of a line in a
+// generated file, if the line came from a node.
+func (m *LineMap) Lookup(file string, line int) (CodePosition, bool) {
+	rs := m.files[file]
+	i := sort.Search(len(rs), func(i int) bool { return rs[i].start+len(rs[i].lines) > line })
+	if i < len(rs) && rs[i].start <= line {
+		r := rs[i]
+		return CodePosition{Node: r.node, Section: r.section, Line: r.lines[line-r.start]}, true
+	}
+	if nn, ok := m.nodeFiles[file]; ok {
+		return CodePosition{Node: nn}, true
+	}
+	return CodePosition{}, false
+}
+
+// MarkedCode returns a section of the code of the node, between comments
+// that record where it came from. The comments are removed when the
+// generated code is formatted.
+func (n *Node) MarkedCode(s CodeSection) string {
+	code := n.code(s)
+	if strings.TrimSpace(code) == "" {
+		return code
+	}
+	return fmt.Sprintf("%s%s %q\n%s\n%s", codeBeginMarker, s, n.Name, code, codeEndMarker)
+}
+
+// code returns a section of the code of the node.
+func (n *Node) code(s CodeSection) string {
+	switch s {
+	case HeadSection:
+		return n.Impl.Head
+	case BodySection:
+		return n.Impl.Body
+	case TailSection:
+		return n.Impl.Tail
+	}
+	return ""
+}
+
+// formatGo gofmts raw generated Go, then removes the code markers, adding
+// the positions of code to m.
+func (g *Graph) formatGo(file string, src []byte, m *LineMap) ([]byte, error) {
+	src, err := format.Source(src)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		out       bytes.Buffer
+		outLines  int
+		prevBlank bool
+		cur       *codeRange
+		formatted []string
+	)
+	for _, l := range strings.SplitAfter(string(src), "\n") {
+		t := markerLine(strings.TrimSpace(l))
+		switch {
+		case strings.HasPrefix(t, codeBeginMarker):
+			f := strings.SplitN(strings.TrimPrefix(t, codeBeginMarker), " ", 2)
+			if len(f) != 2 {
+				return nil, fmt.Errorf("malformed code marker %q", t)
+			}
+			nn, err := strconv.Unquote(f[1])
+			if err != nil {
+				return nil, fmt.Errorf("malformed code marker %q: %v", t, err)
+			}
+			cur = &codeRange{node: nn, section: CodeSection(f[0]), start: outLines + 1}
+			continue
+
+		case t == codeEndMarker && cur != nil:
+			var orig []string
+			if n := g.Nodes[cur.node]; n != nil {
+				orig = strings.Split(n.code(cur.section), "\n")
+			}
+			cur.lines = alignLines(orig, formatted)
+			m.files[file] = append(m.files[file], cur)
+			cur, formatted = nil, nil
+			continue
+
+		case t == "" && prevBlank:
+			// Removing a marker can leave two blank lines together,
+			// which gofmt wouldn't.
+			continue
+		}
+		if l == "" {
+			break // after the final newline
+		}
+		out.WriteString(l)
+		outLines++
+		prevBlank = t == ""
+		if cur != nil {
+			formatted = append(formatted, l)
+		}
+	}
+	if cur != nil {
+		return nil, fmt.Errorf("unterminated %s of node %q", cur.section, cur.node)
+	}
+	return out.Bytes(), nil
+}
+
+// alignLines returns, for each of the formatted lines of a section of
+// code, the line of the original code it came from (counting from 1).
+// gofmt changes whitespace within lines, removes some blank lines, and
+// can split a line (such as "if x { y }") across several, so the lines are
+// compared ignoring whitespace.
+func alignLines(orig, formatted []string) []int {
+	lines := make([]int, len(formatted))
+	if len(orig) == 0 {
+		return lines
+	}
+	o, pending := 0, ""
+	for i, f := range formatted {
+		nf := stripSpace(f)
+		if nf != "" && pending == "" {
+			// Skip blank lines that gofmt removed.
+			for o < len(orig)-1 && stripSpace(orig[o]) == "" {
+				o++
+			}
+		}
+		if o >= len(orig) {
+			o = len(orig) - 1
+		}
+		lines[i] = o + 1
+		no := stripSpace(orig[o])
+		if nf == "" {
+			if no == "" && pending == "" {
+				o++
+			}
+			continue
+		}
+		pending += nf
+		if pending == no || !strings.HasPrefix(no, pending) {
+			// Either the whole line is done, or they don't match
+			// at all, in which case best to keep going.
+			o++
+			pending = ""
+		}
+	}
+	return lines
+}
+
+// stripSpace removes all the whitespace in s.
+func stripSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,134 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/shenzhen-go/model/pin"
+	"gopkg.in/d4l3k/messagediff.v1"
+)
+
+func lineMapGraph() *Graph {
+	code := &FakePart{
+		Head: "x := 1",
+		Body: strings.Join([]string{
+			"for i:=0;i<x;i++ {", // 1
+			"",                   // 2
+			"",                   // 3
+			"  if i>0 { x++ }",   // 4
+			"}",                  // 5
+			"output <- x",        // 6
+		}, "\n"),
+		Tail: "close(output)",
+		Pns:  pin.NewMap(&pin.Definition{Name: "output", Type: "int", Direction: pin.Output}),
+	}
+	return testGraph("linemap", []*Node{{
+		Part:         code,
+		Name:         "Code",
+		Enabled:      true,
+		Multiplicity: "1",
+		Connections:  map[string]string{"output": "nil"},
+	}})
+}
+
+func TestGoFilesWithLineMap(t *testing.T) {
+	files, m, err := lineMapGraph().GoFilesWithLineMap()
+	if err != nil {
+		t.Fatalf("GoFilesWithLineMap() = error %v", err)
+	}
+	file := "generated.Code.node.go"
+	src := string(files[file])
+	if strings.Contains(src, "shenzhen-go:") {
+		t.Errorf("%s contains code markers:\n%s", file, src)
+	}
+	lines := strings.Split(src, "\n")
+	find := func(s string) int {
+		for i, l := range lines {
+			if strings.TrimSpace(l) == s {
+				return i + 1
+			}
+		}
+		t.Fatalf("%s has no line %q:\n%s", file, s, src)
+		return 0
+	}
+	tests := []struct {
+		line string
+		want CodePosition
+	}{
+		{"x := 1", CodePosition{Node: "Code", Section: HeadSection, Line: 1}},
+		{"close(output)", CodePosition{Node: "Code", Section: TailSection, Line: 1}},
+		{"for i := 0; i < x; i++ {", CodePosition{Node: "Code", Section: BodySection, Line: 1}},
+		{"if i > 0 {", CodePosition{Node: "Code", Section: BodySection, Line: 4}},
+		{"x++", CodePosition{Node: "Code", Section: BodySection, Line: 4}},
+		{"output <- x", CodePosition{Node: "Code", Section: BodySection, Line: 6}},
+		{"// Code", CodePosition{Node: "Code"}},
+	}
+	for _, test := range tests {
+		l := find(test.line)
+		got, ok := m.Lookup(file, l)
+		if !ok {
+			t.Errorf("Lookup(%s, %d) = _, false, want true", file, l)
+			continue
+		}
+		if diff, equal := messagediff.PrettyDiff(got, test.want); !equal {
+			t.Errorf("Lookup(%s, %d) [%q] diff (got -> want):\n%s", file, l, test.line, diff)
+		}
+	}
+	if _, ok := m.Lookup(MainFileName, 1); ok {
+		t.Errorf("Lookup(%s, 1) = _, true, want false", MainFileName)
+	}
+}
+
+func TestGoHasNoCodeMarkers(t *testing.T) {
+	src, err := lineMapGraph().Go()
+	if err != nil {
+		t.Fatalf("Go() = error %v", err)
+	}
+	if strings.Contains(src, "shenzhen-go:") {
+		t.Errorf("Go() contains code markers:\n%s", src)
+	}
+}
+
+func TestGoFilesWithLineMapHeadWithoutTail(t *testing.T) {
+	// With no tail, the end of the head and the start of the body are
+	// adjacent comments, which gofmt reformats when the body starts with
+	// a keyword.
+	for _, head := range []string{"var x = 1", "x := 1", "const x = 1"} {
+		g := lineMapGraph()
+		p := g.Nodes["Code"].Part.(*FakePart)
+		p.Head, p.Tail = head, ""
+		files, m, err := g.GoFilesWithLineMap()
+		if err != nil {
+			t.Errorf("head %q: GoFilesWithLineMap() = error %v", head, err)
+			continue
+		}
+		file := "generated.Code.node.go"
+		src := string(files[file])
+		if strings.Contains(src, "shenzhen-go:") {
+			t.Errorf("head %q: %s contains code markers:\n%s", head, file, src)
+		}
+		for i, l := range strings.Split(src, "\n") {
+			if strings.TrimSpace(l) != "for i := 0; i < x; i++ {" {
+				continue
+			}
+			want := CodePosition{Node: "Code", Section: BodySection, Line: 1}
+			if got, ok := m.Lookup(file, i+1); !ok || got != want {
+				t.Errorf("head %q: Lookup(%s, %d) = %v, %t, want %v, true", head, file, i+1, got, ok, want)
+			}
+		}
+	}
+}
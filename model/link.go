@@ -0,0 +1,141 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GraphRef is implemented by parts that refer to another graph file, such
+// as subgraphs.
+type GraphRef interface {
+	Part
+
+	// Link loads the referenced graph with load, and updates the part (for
+	// example, its pins) to match. dir is the directory containing the
+	// file of the graph the node belongs to. Relative paths should be
+	// resolved against dir.
+	Link(dir string, load func(path string) (*Graph, error)) error
+
+	// Subgraph returns the graph loaded by the last successful Link, or nil.
+	Subgraph() *Graph
+}
+
+// CycleError is returned when graph files refer to each other in a cycle.
+type CycleError struct {
+	Files []string // The cycle, starting and ending with the same file
+}
+
+func (e *CycleError) Error() string {
+	return "graph files refer to each other in a cycle: " + strings.Join(e.Files, " -> ")
+}
+
+// Link links every node whose part refers to another graph file, loading
+// those graphs from disk (and linking them, in turn).
+func (g *Graph) Link() error {
+	return newLinker().linkGraph(g)
+}
+
+// LinkNode links a single node of the graph, if its part refers to another
+// graph file.
+func (g *Graph) LinkNode(n *Node) error {
+	l := newLinker()
+	abs, err := filepath.Abs(g.FilePath)
+	if err != nil {
+		return err
+	}
+	l.chain = append(l.chain, abs)
+	return l.linkNode(g, n)
+}
+
+type linker struct {
+	chain  []string          // absolute paths of graphs being linked
+	loaded map[string]*Graph // absolute path -> linked graph
+}
+
+func newLinker() *linker {
+	return &linker{loaded: make(map[string]*Graph)}
+}
+
+func (l *linker) linkGraph(g *Graph) error {
+	abs, err := filepath.Abs(g.FilePath)
+	if err != nil {
+		return err
+	}
+	l.chain = append(l.chain, abs)
+	defer func() { l.chain = l.chain[:len(l.chain)-1] }()
+
+	linked := false
+	for _, nn := range sortedNodeNames(g.Nodes) {
+		n := g.Nodes[nn]
+		if _, ok := n.Part.(GraphRef); !ok {
+			continue
+		}
+		if err := l.linkNode(g, n); err != nil {
+			return fmt.Errorf("node %q: %v", nn, err)
+		}
+		linked = true
+	}
+	if linked {
+		// Linking might have changed available pins.
+		g.RefreshChannelsPins()
+	}
+	return nil
+}
+
+func (l *linker) linkNode(g *Graph, n *Node) error {
+	r, ok := n.Part.(GraphRef)
+	if !ok {
+		return nil
+	}
+	if err := r.Link(filepath.Dir(g.FilePath), l.load); err != nil {
+		return err
+	}
+	n.RefreshConnections()
+	return nil
+}
+
+func (l *linker) load(path string) (*Graph, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range l.chain {
+		if p == abs {
+			files := append(append([]string(nil), l.chain[i:]...), abs)
+			return nil, &CycleError{Files: files}
+		}
+	}
+	if sg := l.loaded[abs]; sg != nil {
+		return sg, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sg, err := LoadJSON(f, path, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.linkGraph(sg); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	l.loaded[abs] = sg
+	return sg, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+// fakeRef is a GraphRef that loads a file and has no pins.
+type fakeRef struct {
+	File string `json:"file"`
+
+	graph *Graph
+}
+
+func (r *fakeRef) Clone() Part         { return &fakeRef{File: r.File} }
+func (r *fakeRef) Impl(*Node) PartImpl { return PartImpl{} }
+func (r *fakeRef) Pins() pin.Map       { return nil }
+func (r *fakeRef) TypeKey() string     { return "FakeRef" }
+func (r *fakeRef) Subgraph() *Graph    { return r.graph }
+func (r *fakeRef) Link(dir string, load func(string) (*Graph, error)) error {
+	g, err := load(filepath.Join(dir, r.File))
+	if err != nil {
+		return err
+	}
+	r.graph = g
+	return nil
+}
+
+func init() {
+	RegisterPartType("FakeRef", "Test", &PartType{
+		New: func() Part { return &fakeRef{} },
+	})
+}
+
+func writeRefGraph(t *testing.T, dir, name string, refs ...string) string {
+	t.Helper()
+	var nodes []string
+	for i, r := range refs {
+		nodes = append(nodes, `"n`+strconv.Itoa(i)+`": {"part_type": "FakeRef", "part": {"file": "`+r+`"}}`)
+	}
+	j := `{"name": "` + name + `", "nodes": {` + strings.Join(nodes, ",") + `}}`
+	fp := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(fp, []byte(j), 0644); err != nil {
+		t.Fatalf("WriteFile(%q) = error %v", fp, err)
+	}
+	return fp
+}
+
+func loadRefGraph(t *testing.T, fp string) *Graph {
+	t.Helper()
+	f, err := os.Open(fp)
+	if err != nil {
+		t.Fatalf("Open(%q) = error %v", fp, err)
+	}
+	defer f.Close()
+	g, err := LoadJSON(f, fp, fp)
+	if err != nil {
+		t.Fatalf("LoadJSON(%q) = error %v", fp, err)
+	}
+	return g
+}
+
+func TestLink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "link")
+	if err != nil {
+		t.Fatalf("TempDir() = error %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// a -> b -> c, and a -> c.
+	fa := writeRefGraph(t, dir, "a.szgo", "b.szgo", "c.szgo")
+	writeRefGraph(t, dir, "b.szgo", "c.szgo")
+	writeRefGraph(t, dir, "c.szgo")
+
+	g := loadRefGraph(t, fa)
+	if err := g.Link(); err != nil {
+		t.Fatalf("g.Link() = error %v", err)
+	}
+	b := g.Nodes["n0"].Part.(GraphRef).Subgraph()
+	if b == nil || b.Name != "b.szgo" {
+		t.Fatalf("n0 subgraph = %v, want b.szgo", b)
+	}
+	c1 := g.Nodes["n1"].Part.(GraphRef).Subgraph()
+	c2 := b.Nodes["n0"].Part.(GraphRef).Subgraph()
+	if c1 == nil || c1 != c2 {
+		t.Errorf("subgraphs for c.szgo = %p, %p, want the same graph", c1, c2)
+	}
+}
+
+func TestLinkCycle(t *testing.T) {
+	dir, err := ioutil.TempDir("", "link")
+	if err != nil {
+		t.Fatalf("TempDir() = error %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	fa := writeRefGraph(t, dir, "a.szgo", "b.szgo")
+	writeRefGraph(t, dir, "b.szgo", "a.szgo")
+	fs := writeRefGraph(t, dir, "self.szgo", "self.szgo")
+
+	g := loadRefGraph(t, fa)
+	err = g.Link()
+	if err == nil {
+		t.Fatal("g.Link() = nil error, want cycle error")
+	}
+	want := filepath.Join(dir, "a.szgo") + " -> " + filepath.Join(dir, "b.szgo") + " -> " + filepath.Join(dir, "a.szgo")
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("g.Link() = error %v, want error containing %q", err, want)
+	}
+
+	g = loadRefGraph(t, fs)
+	ds := g.Check()
+	if len(ds) != 1 || ds[0].Severity != SeverityError || ds[0].Node != "n0" || !strings.Contains(ds[0].Message, "cycle") {
+		t.Errorf("g.Check() = %v, want one cycle error about n0", ds)
+	}
+}
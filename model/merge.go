@@ -0,0 +1,299 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Conflict is a field of a graph, node, or channel that was changed in
+// different ways by both graphs being merged. Empty values mean the field
+// was unset, or for a whole node or channel (empty Field), that it didn't
+// exist.
+type Conflict struct {
+	What, Name, Field string
+
+	Base, Ours, Theirs string
+
+	// Detail explains conflicts that aren't simply different values.
+	Detail string
+}
+
+func (c Conflict) String() string {
+	k := fieldKey{c.What, c.Name, c.Field}
+	if c.Detail != "" {
+		return fmt.Sprintf("%v: %s", k, c.Detail)
+	}
+	if c.Field == "part" {
+		return fmt.Sprintf("%v: changed in both", k)
+	}
+	return fmt.Sprintf("%v: base %q, ours %q, theirs %q", k, c.Base, c.Ours, c.Theirs)
+}
+
+// Merge merges the changes made from base to ours and from base to theirs.
+// Where both change the same field differently, the result has our value,
+// and the field is reported as a conflict. Fields are as small as practical:
+// each property of the graph, each property of a node, the channel
+// connected to each pin, the capacity of each channel, and each property of
+// a parameter. Renames of nodes and channels (see Diff) are followed, so a
+// change to a node merges with a rename of the node. Conflicting node
+// positions are not reported, since they don't change the program.
+func Merge(base, ours, theirs *Graph) (*Graph, []Conflict, error) {
+	fb, err := flatten(base)
+	if err != nil {
+		return nil, nil, fmt.Errorf("base: %v", err)
+	}
+	fo, err := flatten(ours)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ours: %v", err)
+	}
+	ft, err := flatten(theirs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("theirs: %v", err)
+	}
+
+	// Merge using the base names, and then apply the renames.
+	onodes, ochans := renames(fb, fo)
+	tnodes, tchans := renames(fb, ft)
+	fo = fo.renamed(invert(onodes), invert(ochans))
+	ft = ft.renamed(invert(tnodes), invert(tchans))
+
+	var cs []Conflict
+	fm := make(flatGraph)
+	keys := make(map[fieldKey]bool)
+	for _, f := range []flatGraph{fb, fo, ft} {
+		for k := range f {
+			keys[k] = true
+		}
+	}
+	for k := range keys {
+		b, o, t := fb[k], fo[k], ft[k]
+		v := o
+		switch {
+		case o == t, t == b:
+		case o == b:
+			v = t
+		case k.field != "x" && k.field != "y":
+			cs = append(cs, Conflict{What: k.kind, Name: k.name, Field: k.field, Base: b, Ours: o, Theirs: t})
+		}
+		if v != "" {
+			fm[k] = v
+		}
+	}
+
+	nodes, ncs := mergeRenames(fm, kindNode, onodes, tnodes)
+	chans, ccs := mergeRenames(fm, kindChannel, ochans, tchans)
+	cs = append(cs, ncs...)
+	cs = append(cs, ccs...)
+	g, dcs, err := fm.renamed(nodes, chans).graph()
+	if err != nil {
+		return nil, cs, err
+	}
+	cs = append(cs, dcs...)
+	sort.Slice(cs, func(i, j int) bool {
+		return fieldKey{cs[i].What, cs[i].Name, cs[i].Field}.less(fieldKey{cs[j].What, cs[j].Name, cs[j].Field})
+	})
+	return g, cs, nil
+}
+
+// invert inverts renames, for renaming back.
+func invert(ren map[string]string) map[string]string {
+	inv := make(map[string]string, len(ren))
+	for from, to := range ren {
+		inv[to] = from
+	}
+	return inv
+}
+
+// mergeRenames combines the renames made by both sides, preferring ours if
+// they renamed the same thing differently. Renames to a name used by
+// something else in the merged fields are conflicts, and are not made.
+func mergeRenames(f flatGraph, kind string, ours, theirs map[string]string) (map[string]string, []Conflict) {
+	var cs []Conflict
+	m := make(map[string]string, len(ours)+len(theirs))
+	for from, to := range theirs {
+		m[from] = to
+	}
+	for from, to := range ours {
+		if tt, ok := theirs[from]; ok && tt != to {
+			cs = append(cs, Conflict{What: kind, Name: from, Field: "name", Base: from, Ours: to, Theirs: tt})
+		}
+		m[from] = to
+	}
+	for from, to := range m {
+		if f[fieldKey{kind, to, ""}] != "" {
+			cs = append(cs, Conflict{
+				What:   kind,
+				Name:   from,
+				Field:  "name",
+				Detail: fmt.Sprintf("renamed to %q, which is taken", to),
+			})
+			delete(m, from)
+		}
+	}
+	return m, cs
+}
+
+// graph turns fields back into a graph. Connections to channels that don't
+// exist are reported as conflicts, and disconnected.
+func (f flatGraph) graph() (*Graph, []Conflict, error) {
+	g := &Graph{
+		Nodes:    make(map[string]*Node),
+		Channels: make(map[string]*Channel),
+	}
+	for _, nn := range f.names(kindNode) {
+		g.Nodes[nn] = &Node{
+			Name:         nn,
+			Multiplicity: "1",
+			Connections:  make(map[string]string),
+		}
+	}
+	for _, cn := range f.names(kindChannel) {
+		g.Channels[cn] = &Channel{Name: cn}
+	}
+	if pns := f.names(kindParameter); len(pns) > 0 {
+		g.Parameters = make(map[string]*Parameter, len(pns))
+		for _, pn := range pns {
+			g.Parameters[pn] = &Parameter{Name: pn}
+		}
+	}
+
+	var cs []Conflict
+	parts := make(map[string]*PartJSON)
+	for k, v := range f {
+		if k.kind == kindGraph {
+			switch k.field {
+			case "name":
+				g.Name = v
+			case "package_path":
+				g.PackagePath = v
+			case "is_command":
+				g.IsCommand = v == "true"
+			case "use_context":
+				g.UseContext = v == "true"
+			case "telemetry":
+				g.Telemetry = v == "true"
+			case "output_dir":
+				g.OutputDir = v
+			case "generics":
+				g.Generics = v == "true"
+			}
+			continue
+		}
+		if k.kind == kindParameter {
+			if p := g.Parameters[k.name]; p != nil {
+				switch k.field {
+				case "type":
+					p.Type = v
+				case "default":
+					p.Default = v
+				case "help":
+					p.Help = v
+				}
+			}
+			continue
+		}
+		if k.kind == kindChannel {
+			c := g.Channels[k.name]
+			if c != nil && k.field == "cap" {
+				capacity, err := strconv.Atoi(v)
+				if err != nil {
+					return nil, nil, fmt.Errorf("channel %q capacity: %v", k.name, err)
+				}
+				c.Capacity = capacity
+			}
+			continue
+		}
+		n := g.Nodes[k.name]
+		if n == nil {
+			// The node was removed, but one of its fields changed.
+			// That's a conflict already.
+			continue
+		}
+		if parts[k.name] == nil {
+			parts[k.name] = new(PartJSON)
+		}
+		var err error
+		switch k.field {
+		case "part_type":
+			parts[k.name].Type = v
+		case "part":
+			parts[k.name].Part = json.RawMessage(v)
+		case "comment":
+			n.Comment = v
+		case "enabled":
+			n.Enabled = v == "true"
+		case "wait":
+			n.Wait = v == "true"
+		case "multiplicity":
+			n.Multiplicity = v
+		case "recover":
+			n.Recover = v == "true"
+		case "restart":
+			n.Restart = RestartPolicy(v)
+		case "max_restarts":
+			var m uint64
+			m, err = strconv.ParseUint(v, 10, 0)
+			n.MaxRestarts = uint(m)
+		case "restart_backoff":
+			n.RestartBackoff = v
+		case "restart_metrics":
+			n.RestartMetrics = v == "true"
+		case "x":
+			n.X, err = strconv.ParseFloat(v, 64)
+		case "y":
+			n.Y, err = strconv.ParseFloat(v, 64)
+		default:
+			if strings.HasPrefix(k.field, pinPrefix) {
+				pn := strings.TrimPrefix(k.field, pinPrefix)
+				if g.Channels[v] == nil {
+					cs = append(cs, Conflict{
+						What:   kindNode,
+						Name:   k.name,
+						Field:  k.field,
+						Ours:   v,
+						Detail: fmt.Sprintf("connected to channel %q, which was removed", v),
+					})
+					v = "nil"
+				}
+				n.Connections[pn] = v
+			}
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("node %q %s: %v", k.name, k.field, err)
+		}
+	}
+	for nn, n := range g.Nodes {
+		pj := parts[nn]
+		if pj == nil || pj.Type == "" {
+			return nil, nil, fmt.Errorf("node %q has no part", nn)
+		}
+		if len(pj.Part) == 0 {
+			pj.Part = json.RawMessage("{}")
+		}
+		p, err := pj.Unmarshal()
+		if err != nil {
+			return nil, nil, fmt.Errorf("node %q part: %v", nn, err)
+		}
+		n.Part = p
+		n.RefreshConnections()
+	}
+	g.RefreshChannelsPins()
+	return g, cs, nil
+}
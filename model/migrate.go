@@ -0,0 +1,116 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// FormatVersion is the version of the JSON format for graphs written by
+// this version of Shenzhen Go. Files without a version are version 0.
+//
+// Version history:
+//  0: Unversioned.
+//  1: Added format_version. Transform parts have input and output types.
+const FormatVersion = 1
+
+// GraphMigration upgrades the JSON of a graph to the next format version.
+// The graph is decoded into generic maps and slices, with numbers as
+// json.Number.
+type GraphMigration func(graph map[string]interface{}) error
+
+// PartMigration upgrades the JSON of a part to the next format version,
+// decoded like the graph for GraphMigration.
+type PartMigration func(part map[string]interface{}) error
+
+// graphMigrations are keyed by the format version they upgrade from.
+// Part migrations are in PartType.Migrations.
+var graphMigrations = map[int]GraphMigration{}
+
+// RegisterGraphMigration adds a migration from a format version to the
+// next. Migrations should be registered during init.
+func RegisterGraphMigration(from int, m GraphMigration) {
+	graphMigrations[from] = m
+}
+
+// MarshalJSON encodes the graph, with the current format version.
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	type plainGraph Graph // without the MarshalJSON method
+	return json.Marshal(&struct {
+		FormatVersion int `json:"format_version"`
+		plainGraph
+	}{
+		FormatVersion: FormatVersion,
+		plainGraph:    plainGraph(*g),
+	})
+}
+
+// Migrated reports whether the graph was upgraded from an older format
+// version when it was loaded, and if so, which version.
+func (g *Graph) Migrated() (from int, ok bool) {
+	return g.migratedFrom, g.migrated
+}
+
+// migrate upgrades the JSON of a graph to the current format version, and
+// returns the version it was before.
+func migrate(graph map[string]interface{}) (int, error) {
+	from := 0
+	if v, ok := graph["format_version"]; ok {
+		n, ok := v.(json.Number)
+		if !ok {
+			return 0, fmt.Errorf("format_version %v is not a number", v)
+		}
+		i, err := strconv.Atoi(n.String())
+		if err != nil {
+			return 0, fmt.Errorf("format_version %v: %v", v, err)
+		}
+		from = i
+	}
+	if from > FormatVersion {
+		return from, fmt.Errorf("format version %d is newer than this version of Shenzhen Go supports (%d)", from, FormatVersion)
+	}
+	for v := from; v < FormatVersion; v++ {
+		if m := graphMigrations[v]; m != nil {
+			if err := m(graph); err != nil {
+				return from, fmt.Errorf("migrating graph from format version %d: %v", v, err)
+			}
+		}
+		nodes, _ := graph["nodes"].(map[string]interface{})
+		for nn, n := range nodes {
+			node, ok := n.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			typ, _ := node["part_type"].(string)
+			pt := PartTypes[typ]
+			if pt == nil || pt.Migrations[v] == nil {
+				continue
+			}
+			part, ok := node["part"].(map[string]interface{})
+			if !ok {
+				// Parts with no settings have no JSON to migrate.
+				part = make(map[string]interface{})
+				node["part"] = part
+			}
+			if err := pt.Migrations[v](part); err != nil {
+				return from, fmt.Errorf("migrating node %q (%s) from format version %d: %v", nn, typ, v, err)
+			}
+		}
+	}
+	graph["format_version"] = json.Number(strconv.Itoa(FormatVersion))
+	return from, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func init() {
+	RegisterPartType("FakeRenamed", "Test", &PartType{
+		New: func() Part { return new(FakePart) },
+		Migrations: map[int]PartMigration{
+			// Pins used to be called "pinz".
+			0: func(part map[string]interface{}) error {
+				part["pins"] = part["pinz"]
+				delete(part, "pinz")
+				return nil
+			},
+		},
+	})
+}
+
+func TestLoadJSONMigrates(t *testing.T) {
+	tests := []struct {
+		desc         string
+		json         string
+		wantMigrated bool
+		wantPins     int
+	}{
+		{
+			desc:         "unversioned",
+			json:         `{"nodes": {"foo": {"part_type": "FakeRenamed", "part": {"pinz": {"x": {"type": "int", "dir": "in"}}}}}}`,
+			wantMigrated: true,
+			wantPins:     1,
+		},
+		{
+			desc:     "current",
+			json:     `{"format_version": 1, "nodes": {"foo": {"part_type": "FakeRenamed", "part": {"pins": {"x": {"type": "int", "dir": "in"}}}}}}`,
+			wantPins: 1,
+		},
+	}
+	for _, test := range tests {
+		g, err := LoadJSON(strings.NewReader(test.json), "", "")
+		if err != nil {
+			t.Errorf("%s: LoadJSON() = error %v", test.desc, err)
+			continue
+		}
+		from, migrated := g.Migrated()
+		if migrated != test.wantMigrated {
+			t.Errorf("%s: Migrated() = %d, %t, want migrated = %t", test.desc, from, migrated, test.wantMigrated)
+		}
+		if migrated && from != 0 {
+			t.Errorf("%s: Migrated() from = %d, want 0", test.desc, from)
+		}
+		if got := len(g.Nodes["foo"].Part.Pins()); got != test.wantPins {
+			t.Errorf("%s: got %d pins, want %d", test.desc, got, test.wantPins)
+		}
+		j, err := g.JSON()
+		if err != nil {
+			t.Errorf("%s: JSON() = error %v", test.desc, err)
+			continue
+		}
+		if !strings.Contains(j, `"format_version": 1`) {
+			t.Errorf("%s: JSON() = %s, want format_version 1", test.desc, j)
+		}
+	}
+}
+
+func TestLoadJSONTooNew(t *testing.T) {
+	_, err := LoadJSON(strings.NewReader(`{"format_version": 99}`), "", "")
+	if err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("LoadJSON(format version 99) = %v, want error about newer version", err)
+	}
+}
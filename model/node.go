@@ -16,6 +16,7 @@ package model
 
 import (
 	"encoding/json"
+	"math"
 	"regexp"
 	"strings"
 	"unicode"
@@ -42,6 +43,12 @@ type Node struct {
 	Connections  map[string]string // Pin name -> channel name
 	Impl         PartImpl          // Final implementation after type inference
 
+	Recover        bool          // Recover panics in the part, instead of crashing the program
+	Restart        RestartPolicy // What to do after recovering a panic
+	MaxRestarts    uint          // With RestartOnPanic: restarts per instance, or 0 for no limit
+	RestartBackoff string        // With RestartOnPanic: time.Duration before the first restart, or empty for DefaultRestartBackoff
+	RestartMetrics bool          // Count restarts in Prometheus metrics
+
 	TypeParams map[string]*source.Type // Local type parameter -> stringy type
 	PinTypes   map[string]*source.Type // Pin name -> inferred type of pin
 }
@@ -54,6 +61,12 @@ func (n *Node) Copy() *Node {
 		Multiplicity: n.Multiplicity,
 		Wait:         n.Wait,
 		Part:         n.Part.Clone(),
+
+		Recover:        n.Recover,
+		Restart:        n.Restart,
+		MaxRestarts:    n.MaxRestarts,
+		RestartBackoff: n.RestartBackoff,
+		RestartMetrics: n.RestartMetrics,
 		// TODO: find a better location
 		X: n.X + 8,
 		Y: n.Y + 100,
@@ -125,30 +138,41 @@ func (n *Node) Identifier() string {
 
 type jsonNode struct {
 	*PartJSON
-	Comment      string            `json:"comment,omitempty"`
-	Enabled      bool              `json:"enabled"`
-	Wait         bool              `json:"wait"`
-	Multiplicity string            `json:"multiplicity,omitempty"`
-	X            float64           `json:"x"`
-	Y            float64           `json:"y"`
-	Connections  map[string]string `json:"connections"`
+	Comment        string            `json:"comment,omitempty"`
+	Enabled        bool              `json:"enabled"`
+	Wait           bool              `json:"wait"`
+	Multiplicity   string            `json:"multiplicity,omitempty"`
+	Recover        bool              `json:"recover,omitempty"`
+	Restart        RestartPolicy     `json:"restart,omitempty"`
+	MaxRestarts    uint              `json:"max_restarts,omitempty"`
+	RestartBackoff string            `json:"restart_backoff,omitempty"`
+	RestartMetrics bool              `json:"restart_metrics,omitempty"`
+	X              float64           `json:"x"`
+	Y              float64           `json:"y"`
+	Connections    map[string]string `json:"connections"`
 }
 
-// MarshalJSON encodes the node and part as JSON.
+// MarshalJSON encodes the node and part as JSON, with the position rounded
+// to whole pixels.
 func (n *Node) MarshalJSON() ([]byte, error) {
 	pj, err := MarshalPart(n.Part)
 	if err != nil {
 		return nil, err
 	}
 	return json.Marshal(&jsonNode{
-		PartJSON:     pj,
-		Comment:      n.Comment,
-		Enabled:      n.Enabled,
-		Wait:         n.Wait,
-		Multiplicity: n.Multiplicity,
-		X:            n.X,
-		Y:            n.Y,
-		Connections:  n.Connections,
+		PartJSON:       pj,
+		Comment:        n.Comment,
+		Enabled:        n.Enabled,
+		Wait:           n.Wait,
+		Multiplicity:   n.Multiplicity,
+		Recover:        n.Recover,
+		Restart:        n.Restart,
+		MaxRestarts:    n.MaxRestarts,
+		RestartBackoff: n.RestartBackoff,
+		RestartMetrics: n.RestartMetrics,
+		X:              math.Round(n.X),
+		Y:              math.Round(n.Y),
+		Connections:    n.Connections,
 	})
 }
 
@@ -169,6 +193,11 @@ func (n *Node) UnmarshalJSON(j []byte) error {
 	n.Enabled = mp.Enabled
 	n.Wait = mp.Wait
 	n.Multiplicity = mp.Multiplicity
+	n.Recover = mp.Recover
+	n.Restart = mp.Restart
+	n.MaxRestarts = mp.MaxRestarts
+	n.RestartBackoff = mp.RestartBackoff
+	n.RestartMetrics = mp.RestartMetrics
 	n.Part = p
 	n.X, n.Y = mp.X, mp.Y
 	n.Connections = mp.Connections
@@ -195,3 +224,13 @@ func (n *Node) RefreshConnections() {
 func (n *Node) RefreshImpl() {
 	n.Impl = n.Part.Impl(n)
 }
+
+// refreshImpl refreshes the Impl of the node, using GenericImpl if the graph
+// has the Generics option and the part is a GenericPart.
+func (g *Graph) refreshImpl(n *Node) {
+	if gp, ok := n.Part.(GenericPart); ok && g.Generics {
+		n.Impl = gp.GenericImpl(n)
+		return
+	}
+	n.RefreshImpl()
+}
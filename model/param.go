@@ -0,0 +1,282 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParameterTypes maps the types a Parameter can have to the name of the
+// corresponding function in the flag package, less the "Var" suffix.
+var ParameterTypes = map[string]string{
+	"bool":          "Bool",
+	"float64":       "Float64",
+	"int":           "Int",
+	"int64":         "Int64",
+	"string":        "String",
+	"time.Duration": "Duration",
+	"uint":          "Uint",
+	"uint64":        "Uint64",
+}
+
+// reservedParameterNames can't be used as parameter names, because the
+// generated code already uses them in node functions.
+var reservedParameterNames = map[string]bool{
+	"config":         true,
+	"ctx":            true,
+	"instanceNumber": true,
+	"multiplicity":   true,
+	"n":              true, // Stands for runtime.NumCPU() in multiplicities.
+	"N":              true,
+}
+
+// Parameter is a named value that configures a graph, such as a listen
+// address or a cache size. The generated package has a Config struct with a
+// field for each parameter. Commands set the fields from command-line flags,
+// and in library mode, Run takes a Config. Code in nodes, and multiplicity
+// expressions, refer to a parameter by its name.
+type Parameter struct {
+	Name    string `json:"-"`
+	Type    string `json:"type"`              // a key of ParameterTypes
+	Default string `json:"default,omitempty"` // as it would be given as a flag; empty means the zero value
+	Help    string `json:"help,omitempty"`    // the usage message of the flag
+}
+
+// Field returns the name of the field of the generated Config struct: the
+// name of the parameter, starting with an upper-case letter.
+func (p *Parameter) Field() string {
+	r, n := utf8.DecodeRuneInString(p.Name)
+	return string(unicode.ToUpper(r)) + p.Name[n:]
+}
+
+// FlagFunc returns the name of the flag package function for defining a
+// flag of the parameter's type, e.g. "IntVar".
+func (p *Parameter) FlagFunc() string {
+	return ParameterTypes[p.Type] + "Var"
+}
+
+// Comment returns the help text as a Go comment.
+func (p *Parameter) Comment() string {
+	if p.Help == "" {
+		return ""
+	}
+	return "// " + strings.Replace(p.Help, "\n", "\n// ", -1)
+}
+
+// Value returns a Go expression for the default value of the parameter.
+func (p *Parameter) Value() (string, error) {
+	d := strings.TrimSpace(p.Default)
+	switch p.Type {
+	case "bool":
+		if d == "" {
+			return "false", nil
+		}
+		b, err := strconv.ParseBool(d)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(b), nil
+	case "int", "int64":
+		if d == "" {
+			return "0", nil
+		}
+		i, err := strconv.ParseInt(d, 0, 64)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(i, 10), nil
+	case "uint", "uint64":
+		if d == "" {
+			return "0", nil
+		}
+		u, err := strconv.ParseUint(d, 0, 64)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatUint(u, 10), nil
+	case "float64":
+		if d == "" {
+			return "0", nil
+		}
+		f, err := strconv.ParseFloat(d, 64)
+		if err != nil {
+			return "", err
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return "", fmt.Errorf("%v is not a Go constant", f)
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	case "string":
+		// Strings are used as-is, including any spaces.
+		return strconv.Quote(p.Default), nil
+	case "time.Duration":
+		if d == "" {
+			return "0", nil
+		}
+		t, err := time.ParseDuration(d)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("time.Duration(%d)", int64(t)), nil
+	}
+	return "", fmt.Errorf("unsupported type %q", p.Type)
+}
+
+// SortedParameters returns the parameters in order of name.
+func (g *Graph) SortedParameters() []*Parameter {
+	ps := make([]*Parameter, 0, len(g.Parameters))
+	for _, p := range g.Parameters {
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].Name < ps[j].Name
+	})
+	return ps
+}
+
+// NodeParameters returns the parameters that the node refers to, in its
+// multiplicity or the code of its implementation, in order of name.
+// Requires RefreshImpl to have been called.
+func (g *Graph) NodeParameters(n *Node) []*Parameter {
+	if len(g.Parameters) == 0 {
+		return nil
+	}
+	used := make(map[string]bool)
+	for _, src := range []string{n.Multiplicity, n.Impl.Head, n.Impl.Body, n.Impl.Tail} {
+		addIdents(used, src)
+	}
+	var ps []*Parameter
+	for _, p := range g.SortedParameters() {
+		if used[p.Name] {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// addIdents adds the identifiers in the Go source to m, except those
+// selected from something else (the b in a.b).
+func addIdents(m map[string]bool, src string) {
+	fs := token.NewFileSet()
+	b := []byte(src)
+	var s scanner.Scanner
+	// Errors are ignored; code with errors will fail to compile anyway.
+	s.Init(fs.AddFile("", -1, len(b)), b, nil, 0)
+	prev := token.ILLEGAL
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return
+		}
+		if tok == token.IDENT && prev != token.PERIOD {
+			m[lit] = true
+		}
+		prev = tok
+	}
+}
+
+// checkParameters adds diagnostics for parameters with unusable names,
+// unsupported types, or invalid defaults, and nodes that conflict with the
+// generated code for parameters.
+func (g *Graph) checkParameters(add addFunc) {
+	if len(g.Parameters) == 0 {
+		return
+	}
+	for _, nn := range sortedNodeNames(g.Nodes) {
+		switch g.Nodes[nn].Identifier() {
+		case "Config", "DefaultConfig", "flag":
+			add(SeverityError, nn, "", "", "conflicts with the generated code for parameters; rename it")
+		}
+	}
+	fields := make(map[string]string)
+	for _, p := range g.SortedParameters() {
+		if !isIdentifier(p.Name) || reservedParameterNames[p.Name] {
+			add(SeverityError, "", "", "", "parameter %q: name must be a Go identifier, other than %s", p.Name, strings.Join(reservedNames(), ", "))
+			continue
+		}
+		if _, ok := ParameterTypes[p.Type]; !ok {
+			add(SeverityError, "", "", "", "parameter %q: unsupported type %q", p.Name, p.Type)
+		} else if _, err := p.Value(); err != nil {
+			add(SeverityError, "", "", "", "parameter %q: invalid default %q: %v", p.Name, p.Default, err)
+		}
+		if o, ok := fields[p.Field()]; ok {
+			add(SeverityError, "", "", "", "parameters %q and %q would both be the Config field %s", o, p.Name, p.Field())
+		}
+		fields[p.Field()] = p.Name
+	}
+}
+
+// checkNodeParameters adds diagnostics for parameters used by a node that
+// have the same name as a pin of the node, or a package it imports. The
+// generated function for the node would declare the name twice, or the
+// parameter would hide the package.
+// Requires InferTypes to have been called, and refreshes each Impl.
+func (g *Graph) checkNodeParameters(add addFunc) {
+	if len(g.Parameters) == 0 {
+		return
+	}
+	for _, nn := range sortedNodeNames(g.Nodes) {
+		n := g.Nodes[nn]
+		if n.IsPort() {
+			continue
+		}
+		g.refreshImpl(n)
+		pins := n.Part.Pins()
+		imports := make(map[string]string)
+		for _, imp := range n.Impl.Imports {
+			imports[importName(imp)] = strings.TrimSpace(imp)
+		}
+		for _, p := range g.NodeParameters(n) {
+			if pins[p.Name] != nil {
+				add(SeverityError, nn, "", p.Name, "pin has the same name as parameter %q; rename one of them", p.Name)
+			}
+			if imp, ok := imports[p.Name]; ok {
+				add(SeverityError, nn, "", "", "parameter %q has the same name as the import %s; rename the parameter or alias the import", p.Name, imp)
+			}
+		}
+	}
+}
+
+func reservedNames() []string {
+	names := make([]string, 0, len(reservedParameterNames))
+	for n := range reservedParameterNames {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isIdentifier reports whether s is a Go identifier, and not a keyword or
+// the blank identifier.
+func isIdentifier(s string) bool {
+	if s == "" || s == "_" || token.Lookup(s).IsKeyword() {
+		return false
+	}
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
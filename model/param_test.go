@@ -0,0 +1,223 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+func TestParameterValue(t *testing.T) {
+	tests := []struct {
+		typ, def, want string
+		wantErr        bool
+	}{
+		{typ: "bool", def: "", want: "false"},
+		{typ: "bool", def: "T", want: "true"},
+		{typ: "int", def: "0x10", want: "16"},
+		{typ: "uint64", def: "-1", wantErr: true},
+		{typ: "float64", def: "1.5", want: "1.5"},
+		{typ: "float64", def: "Inf", wantErr: true},
+		{typ: "string", def: `say "hi" `, want: `"say \"hi\" "`},
+		{typ: "time.Duration", def: "1.5s", want: "time.Duration(1500000000)"},
+		{typ: "time.Duration", def: "soon", wantErr: true},
+		{typ: "complex128", def: "", wantErr: true},
+	}
+	for _, test := range tests {
+		p := &Parameter{Name: "p", Type: test.typ, Default: test.def}
+		got, err := p.Value()
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("Parameter{Type: %q, Default: %q}.Value() error = %v, want error %t", test.typ, test.def, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("Parameter{Type: %q, Default: %q}.Value() = %s, want %s", test.typ, test.def, got, test.want)
+		}
+	}
+}
+
+func paramGraph() *Graph {
+	a := fakeNode("a", true, "workers", map[string]string{"output": "c"},
+		&pin.Definition{Name: "output", Type: "string", Direction: pin.Output},
+	)
+	a.Part.(*FakePart).Body = "output <- addr // workers.addr is not a parameter"
+	b := fakeNode("b", true, "1", map[string]string{"input": "c"},
+		&pin.Definition{Name: "input", Type: "string", Direction: pin.Input},
+	)
+	b.Part.(*FakePart).Body = "for range input { x.workers++ }"
+	g := testGraph("params", []*Node{a, b}, &Channel{Name: "c"})
+	g.Parameters = map[string]*Parameter{
+		"addr":    {Name: "addr", Type: "string", Default: ":8080", Help: "address to listen on"},
+		"timeout": {Name: "timeout", Type: "time.Duration", Default: "5s"},
+		"workers": {Name: "workers", Type: "int", Default: "4"},
+	}
+	return g
+}
+
+func TestNodeParameters(t *testing.T) {
+	g := paramGraph()
+	for _, n := range g.Nodes {
+		n.RefreshImpl()
+	}
+	tests := map[string]string{
+		"a": "addr workers",
+		"b": "",
+	}
+	for nn, want := range tests {
+		var names []string
+		for _, p := range g.NodeParameters(g.Nodes[nn]) {
+			names = append(names, p.Name)
+		}
+		if got := strings.Join(names, " "); got != want {
+			t.Errorf("NodeParameters(%s) = [%s], want [%s]", nn, got, want)
+		}
+	}
+}
+
+func TestGoParameters(t *testing.T) {
+	tests := []struct {
+		desc              string
+		isCommand, useCtx bool
+		want, notWant     []string
+	}{
+		{
+			desc: "library",
+			want: []string{
+				"type Config struct {",
+				"\t// address to listen on\n\tAddr ",
+				"Timeout: time.Duration(5000000000),",
+				"func Run(config Config) {",
+				"go a(config, c)",
+				"addr := config.Addr",
+				"multiplicity := workers",
+			},
+			notWant: []string{`"flag"`, "timeout := config.Timeout"},
+		},
+		{
+			desc:   "library with context",
+			useCtx: true,
+			want: []string{
+				"func Run(ctx context.Context, config Config) error {",
+				"a(ctx, config, c)",
+			},
+		},
+		{
+			desc:      "command",
+			isCommand: true,
+			want: []string{
+				`flag.StringVar(&config.Addr, "addr", config.Addr, "address to listen on")`,
+				`flag.DurationVar(&config.Timeout, "timeout", config.Timeout, "")`,
+				"flag.Parse()",
+			},
+			notWant: []string{"func Run("},
+		},
+		{
+			desc:      "command with context",
+			isCommand: true,
+			useCtx:    true,
+			want: []string{
+				"flag.Parse()",
+				"if err := Run(ctx, config); err != nil {",
+			},
+		},
+	}
+	for _, test := range tests {
+		g := paramGraph()
+		g.IsCommand, g.UseContext = test.isCommand, test.useCtx
+		src, err := g.Go()
+		if err != nil {
+			t.Errorf("%s: Go() = error %v", test.desc, err)
+			continue
+		}
+		for _, w := range test.want {
+			if !strings.Contains(src, w) {
+				t.Errorf("%s: Go() does not contain %q\n%s", test.desc, w, src)
+			}
+		}
+		for _, nw := range test.notWant {
+			if strings.Contains(src, nw) {
+				t.Errorf("%s: Go() contains %q", test.desc, nw)
+			}
+		}
+		if _, err := g.GoFiles(); err != nil {
+			t.Errorf("%s: GoFiles() = error %v", test.desc, err)
+		}
+	}
+}
+
+func TestCheckParametersErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		edit func(*Graph)
+		want string
+	}{
+		{
+			desc: "not an identifier",
+			edit: func(g *Graph) { g.Parameters["cache size"] = &Parameter{Name: "cache size", Type: "int"} },
+			want: "must be a Go identifier",
+		},
+		{
+			desc: "reserved",
+			edit: func(g *Graph) { g.Parameters["ctx"] = &Parameter{Name: "ctx", Type: "int"} },
+			want: "must be a Go identifier",
+		},
+		{
+			desc: "type",
+			edit: func(g *Graph) { g.Parameters["workers"].Type = "int32" },
+			want: `unsupported type "int32"`,
+		},
+		{
+			desc: "default",
+			edit: func(g *Graph) { g.Parameters["workers"].Default = "many" },
+			want: `invalid default "many"`,
+		},
+		{
+			desc: "same field",
+			edit: func(g *Graph) { g.Parameters["Workers"] = &Parameter{Name: "Workers", Type: "int"} },
+			want: "both be the Config field Workers",
+		},
+		{
+			desc: "node name",
+			edit: func(g *Graph) { g.Nodes["Config"] = fakeNode("Config", true, "1", nil) },
+			want: `node "Config": conflicts`,
+		},
+		{
+			desc: "pin name",
+			edit: func(g *Graph) { g.Parameters["output"] = &Parameter{Name: "output", Type: "string"} },
+			want: `node "a" pin "output": pin has the same name as parameter "output"`,
+		},
+		{
+			desc: "import name",
+			edit: func(g *Graph) { g.Nodes["a"].Part.(*FakePart).Impts = []string{`"example.com/addr"`} },
+			want: `node "a": parameter "addr" has the same name as the import "example.com/addr"`,
+		},
+	}
+	for _, test := range tests {
+		g := paramGraph()
+		test.edit(g)
+		_, err := g.Go()
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: Go() = error %v, want error containing %q", test.desc, err, test.want)
+		}
+
+		g = paramGraph()
+		test.edit(g)
+		if err := g.Check().Err(); err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: Check().Err() = %v, want error containing %q", test.desc, err, test.want)
+		}
+	}
+}
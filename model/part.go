@@ -64,6 +64,23 @@ type Part interface {
 	TypeKey() string
 }
 
+// GenericPart is implemented by parts that can also be implemented by
+// calling generic functions, such as those in GenericPackagePath, instead of
+// by code specialised to the inferred types. GenericImpl is used in place of
+// Impl for graphs with the Generics option.
+type GenericPart interface {
+	Part
+
+	// GenericImpl is like Impl, but the code may use generics. It may
+	// return the same as Impl, when the part can't use generic functions in
+	// some configuration.
+	GenericImpl(n *Node) PartImpl
+}
+
+// GenericPackagePath is the import path of the package of generic functions
+// for the standard parts.
+const GenericPackagePath = "github.com/google/shenzhen-go/parts/generic"
+
 // PartImpl wraps the mostly-formed Go source code that can be inserted into
 // the template.
 type PartImpl struct {
@@ -86,6 +103,10 @@ type PartType struct {
 
 	// Panels defines the UI for controlling the settings of the part.
 	Panels []PartPanel
+
+	// Migrations upgrade the JSON of old parts of this type, keyed by the
+	// graph format version they upgrade from.
+	Migrations map[int]PartMigration
 }
 
 // PartPanel describes one panel of the editor interface specific to a part type.
@@ -39,11 +39,26 @@ func (d Direction) Type() string {
 	return ""
 }
 
+// Opposite returns the other direction.
+func (d Direction) Opposite() Direction {
+	switch d {
+	case Input:
+		return Output
+	case Output:
+		return Input
+	}
+	return d
+}
+
 // Definition describes the main properties of a pin.
 type Definition struct {
 	Name      string    `json:"-"`
 	Type      string    `json:"type"`
 	Direction Direction `json:"dir"`
+
+	// Optional is set when the part works with the pin not connected
+	// (nil), so there's no need to warn about it.
+	Optional bool `json:"optional,omitempty"`
 }
 
 // Map is a map from pin names to pin definitions.
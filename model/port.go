@@ -0,0 +1,103 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"sort"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+// Port is implemented by parts that connect a library graph to the Go
+// program using it. Nodes with Port parts do not run as goroutines. Instead,
+// the channel connected to the port's pin becomes a parameter of the
+// generated Run function, named after the node.
+type Port interface {
+	Part
+
+	// PortPin returns the name of the pin connecting the port to the graph.
+	PortPin() string
+}
+
+// PortParam describes a parameter of the generated Run function.
+type PortParam struct {
+	Node    *Node
+	Channel string // Name of the connected channel, or "nil"
+}
+
+// Identifier returns the parameter name.
+func (p *PortParam) Identifier() string { return p.Node.Identifier() }
+
+// Direction returns the direction of the parameter from the point of view
+// of the graph; Input means the graph reads from the channel.
+func (p *PortParam) Direction() pin.Direction {
+	pn := p.Node.Part.(Port).PortPin()
+	return p.Node.Part.Pins()[pn].Direction.Opposite()
+}
+
+// ElemType returns the type of values sent over the channel, e.g. "int".
+// Requires InferTypes to have been called.
+func (p *PortParam) ElemType() string {
+	pn := p.Node.Part.(Port).PortPin()
+	return p.Node.PinTypes[pn].String()
+}
+
+// Type returns the full type of the parameter, e.g. "<-chan int".
+// Requires InferTypes to have been called.
+func (p *PortParam) Type() string {
+	return p.Direction().Type() + " " + p.ElemType()
+}
+
+// IsPort reports whether the node's part is a Port.
+func (n *Node) IsPort() bool {
+	_, ok := n.Part.(Port)
+	return ok
+}
+
+// Ports returns the parameters of the generated Run function, in order of
+// identifier. Ports are ignored when the graph is a command.
+func (g *Graph) Ports() []*PortParam {
+	if g.IsCommand {
+		return nil
+	}
+	var ps []*PortParam
+	for _, n := range g.Nodes {
+		pt, ok := n.Part.(Port)
+		if !ok {
+			continue
+		}
+		ps = append(ps, &PortParam{
+			Node:    n,
+			Channel: n.Connections[pt.PortPin()],
+		})
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].Identifier() < ps[j].Identifier()
+	})
+	return ps
+}
+
+// PortChannels maps the names of channels connected to ports to the
+// corresponding Run parameter. Such channels are not made by Run.
+func (g *Graph) PortChannels() map[string]string {
+	m := make(map[string]string)
+	for _, p := range g.Ports() {
+		if p.Channel == "" || p.Channel == "nil" {
+			continue
+		}
+		m[p.Channel] = p.Identifier()
+	}
+	return m
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/d4l3k/messagediff.v1"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+type fakePort struct {
+	FakePart
+}
+
+func (p *fakePort) PortPin() string {
+	for pn := range p.Pns {
+		return pn
+	}
+	return ""
+}
+
+func portNode(name, conn string, dir pin.Direction) *Node {
+	return &Node{
+		Part:         &fakePort{FakePart{Pns: pin.NewMap(&pin.Definition{Name: "p", Type: "$T", Direction: dir})}},
+		Name:         name,
+		Enabled:      true,
+		Multiplicity: "1",
+		Connections:  map[string]string{"p": conn},
+	}
+}
+
+func portGraph() *Graph {
+	return testGraph("ports", []*Node{
+		portNode("in", "c0", pin.Output),
+		portNode("out", "c1", pin.Input),
+		fakeNode("mid", true, "1", map[string]string{"input": "c0", "output": "c1"},
+			&pin.Definition{Name: "input", Type: "int", Direction: pin.Input},
+			&pin.Definition{Name: "output", Type: "string", Direction: pin.Output},
+		),
+	}, &Channel{Name: "c0"}, &Channel{Name: "c1"})
+}
+
+func TestPorts(t *testing.T) {
+	g := portGraph()
+	if err := g.InferTypes(); err != nil {
+		t.Fatalf("InferTypes() = error %v", err)
+	}
+	type port struct{ Ident, Type, Channel string }
+	var got []port
+	for _, p := range g.Ports() {
+		got = append(got, port{p.Identifier(), p.Type(), p.Channel})
+	}
+	want := []port{
+		{"in", "<-chan int", "c0"},
+		{"out", "chan<- string", "c1"},
+	}
+	if diff, equal := messagediff.PrettyDiff(got, want); !equal {
+		t.Errorf("g.Ports() diff (got -> want)\n%v", diff)
+	}
+	if diff, equal := messagediff.PrettyDiff(g.PortChannels(), map[string]string{"c0": "in", "c1": "out"}); !equal {
+		t.Errorf("g.PortChannels() diff (got -> want)\n%v", diff)
+	}
+
+	g.IsCommand = true
+	if ps := g.Ports(); len(ps) != 0 {
+		t.Errorf("g.Ports() for command = %v, want none", ps)
+	}
+}
+
+func TestGoTemplatePorts(t *testing.T) {
+	g := portGraph()
+	src, err := g.Go()
+	if err != nil {
+		t.Fatalf("g.Go() = error %v", err)
+	}
+	for _, want := range []string{
+		"func Run(in <-chan int, out chan<- string) {",
+		"c0 := in\n",
+		"c1 := out\n",
+		"go mid(c0, c1)",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("g.Go() does not contain %q:\n%s", want, src)
+		}
+	}
+	for _, notWant := range []string{"func in(", "go in(", "make(chan"} {
+		if strings.Contains(src, notWant) {
+			t.Errorf("g.Go() contains %q:\n%s", notWant, src)
+		}
+	}
+}
+
+func TestCheckPorts(t *testing.T) {
+	g := portGraph()
+	g.Nodes["in2"] = portNode("in2", "c0", pin.Output)
+	g.Nodes["c1"] = portNode("c1", "nil", pin.Input)
+	g.RefreshChannelsPins()
+
+	want := Diagnostics{
+		{Severity: SeverityError, Node: "c1", Channel: "c1", Message: "port name clashes with a channel name"},
+		{Severity: SeverityError, Node: "in", Channel: "c0", Message: `channel is also connected to ports "in2"`},
+		{Severity: SeverityError, Node: "in", Channel: "c0", Pin: "p", Message: `pin has the same direction as port "in2" on the same channel`},
+		{Severity: SeverityError, Node: "in2", Channel: "c0", Message: `channel is also connected to ports "in"`},
+		{Severity: SeverityError, Node: "in2", Channel: "c0", Pin: "p", Message: `pin has the same direction as port "in" on the same channel`},
+		{Severity: SeverityWarning, Node: "c1", Pin: "p", Message: "pin is not connected"},
+	}
+	if diff, equal := messagediff.PrettyDiff(g.Check(), want); !equal {
+		t.Errorf("g.Check() diff (got -> want)\n%v", diff)
+	}
+}
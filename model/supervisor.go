@@ -0,0 +1,171 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RestartPolicy says what happens to an instance of a node after a panic
+// in its part is recovered.
+type RestartPolicy string
+
+// Restart policies.
+const (
+	// RestartNever lets the instance finish. With a context-aware Run, the
+	// panic becomes an error returned by Run; otherwise it is logged.
+	RestartNever RestartPolicy = ""
+
+	// RestartOnPanic runs the part's body again after a backoff. The
+	// backoff starts at RestartBackoff (or DefaultRestartBackoff, if that is
+	// empty), and doubles after each restart, up to a minute. After
+	// MaxRestarts restarts, it is like RestartNever.
+	RestartOnPanic RestartPolicy = "on_panic"
+)
+
+// RestartPolicies maps the valid restart policies to descriptions.
+var RestartPolicies = map[RestartPolicy]string{
+	RestartNever:   "Never",
+	RestartOnPanic: "On panic",
+}
+
+// Identifiers in the generated code for supervising nodes, which nodes
+// can't use.
+const (
+	supervisorIdent = "supervisor"
+	restartsIdent   = "nodeRestarts"
+)
+
+// Supervised reports whether any node recovers panics. The generated code
+// then includes the supervisor type.
+func (g *Graph) Supervised() bool {
+	for _, n := range g.Nodes {
+		if n.Recover && !n.IsPort() {
+			return true
+		}
+	}
+	return false
+}
+
+// RestartMetrics reports whether any node that recovers panics counts
+// restarts in Prometheus metrics.
+func (g *Graph) RestartMetrics() bool {
+	for _, n := range g.Nodes {
+		if n.Recover && n.RestartMetrics && !n.IsPort() {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRestartBackoff is the backoff before the first restart of nodes
+// that don't set RestartBackoff.
+const DefaultRestartBackoff = time.Second
+
+// restartBackoff parses RestartBackoff.
+func (n *Node) restartBackoff() (time.Duration, error) {
+	b := strings.TrimSpace(n.RestartBackoff)
+	if b == "" {
+		return DefaultRestartBackoff, nil
+	}
+	d, err := time.ParseDuration(b)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("restart backoff %v is negative", d)
+	}
+	return d, nil
+}
+
+// Supervisor returns a Go expression for the supervisor of the node, used
+// by the generated code if the node recovers panics.
+func (n *Node) Supervisor() (string, error) {
+	s := fmt.Sprintf("%s{node: %q", supervisorIdent, n.Name)
+	if n.Restart == RestartOnPanic {
+		d, err := n.restartBackoff()
+		if err != nil {
+			return "", err
+		}
+		s += fmt.Sprintf(", restart: true, maxRestarts: %d, backoff: time.Duration(%d)", n.MaxRestarts, int64(d))
+	}
+	if n.RestartMetrics {
+		s += ", metrics: true"
+	}
+	return s + "}", nil
+}
+
+// checkSupervision returns an error if the supervision settings of the
+// node are invalid.
+func (n *Node) checkSupervision() error {
+	if _, ok := RestartPolicies[n.Restart]; !ok {
+		return fmt.Errorf("unknown restart policy %q", n.Restart)
+	}
+	d, err := n.restartBackoff()
+	if err != nil {
+		return fmt.Errorf("invalid restart backoff %q: %v", n.RestartBackoff, err)
+	}
+	if d == 0 && n.Restart == RestartOnPanic && n.MaxRestarts == 0 {
+		// Otherwise a node that always panics would restart in a busy loop.
+		return errors.New("restart backoff must be positive when restarts are unlimited")
+	}
+	return nil
+}
+
+// checkSupervisors checks the supervision settings of all the nodes, and
+// that no node conflicts with the generated supervisor code.
+func (g *Graph) checkSupervisors() error {
+	if !g.Supervised() {
+		return nil
+	}
+	for _, nn := range sortedNodeNames(g.Nodes) {
+		n := g.Nodes[nn]
+		switch n.Identifier() {
+		case supervisorIdent, restartsIdent:
+			return fmt.Errorf("node %q conflicts with the generated code for recovering panics; rename it", n.Name)
+		}
+		if !n.Recover {
+			continue
+		}
+		if err := n.checkSupervision(); err != nil {
+			return fmt.Errorf("node %q: %v", n.Name, err)
+		}
+	}
+	return nil
+}
+
+// checkSupervision adds diagnostics for the supervision settings of nodes.
+func (g *Graph) checkSupervision(add addFunc) {
+	supervised := g.Supervised()
+	for _, nn := range sortedNodeNames(g.Nodes) {
+		n := g.Nodes[nn]
+		if n.IsPort() {
+			continue
+		}
+		if id := n.Identifier(); supervised && (id == supervisorIdent || id == restartsIdent) {
+			add(SeverityError, n.Name, "", "", "node name %q is reserved when recovering panics", n.Name)
+		}
+		if err := n.checkSupervision(); err != nil {
+			add(SeverityError, n.Name, "", "", "%v", err)
+			continue
+		}
+		if !n.Recover && (n.Restart != RestartNever || n.RestartMetrics) {
+			add(SeverityWarning, n.Name, "", "", "restart settings have no effect unless panics are recovered")
+		}
+	}
+}
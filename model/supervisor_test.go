@@ -0,0 +1,123 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/shenzhen-go/model/pin"
+	"gopkg.in/d4l3k/messagediff.v1"
+)
+
+func TestNodeSupervisor(t *testing.T) {
+	tests := []struct {
+		node *Node
+		want string
+	}{
+		{
+			node: &Node{Name: "a", Recover: true},
+			want: `supervisor{node: "a"}`,
+		},
+		{
+			node: &Node{Name: "b", Recover: true, Restart: RestartOnPanic, MaxRestarts: 3, RestartBackoff: "100ms", RestartMetrics: true},
+			want: `supervisor{node: "b", restart: true, maxRestarts: 3, backoff: time.Duration(100000000), metrics: true}`,
+		},
+		{
+			node: &Node{Name: "c", Recover: true, Restart: RestartOnPanic},
+			want: `supervisor{node: "c", restart: true, maxRestarts: 0, backoff: time.Duration(1000000000)}`,
+		},
+	}
+	for _, test := range tests {
+		got, err := test.node.Supervisor()
+		if err != nil {
+			t.Errorf("node %q Supervisor() = error %v", test.node.Name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("node %q Supervisor() = %s, want %s", test.node.Name, got, test.want)
+		}
+	}
+}
+
+func TestGoSupervised(t *testing.T) {
+	for _, useContext := range []bool{false, true} {
+		n := fakeNode("worker", true, "2", map[string]string{}, &pin.Definition{Name: "input", Type: "int", Direction: pin.Input})
+		n.Part.(*FakePart).Body = "panic(input)"
+		n.Recover = true
+		n.Restart = RestartOnPanic
+		n.RestartBackoff = "1s"
+		n.RestartMetrics = true
+		g := testGraph("supervised", []*Node{n})
+		g.UseContext = useContext
+		src, err := g.Go()
+		if err != nil {
+			t.Fatalf("Go() with UseContext = %t = error %v", useContext, err)
+		}
+		for _, want := range []string{
+			`nodeSupervisor := supervisor{node: "worker", restart: true, maxRestarts: 0, backoff: time.Duration(1000000000), metrics: true}`,
+			"nodeSupervisor.run(",
+			"type supervisor struct",
+			"var nodeRestarts = prometheus.NewCounterVec(",
+		} {
+			if !strings.Contains(src, want) {
+				t.Errorf("Go() with UseContext = %t does not contain %q; got:\n%s", useContext, want, src)
+			}
+		}
+	}
+}
+
+func TestCheckSupervision(t *testing.T) {
+	recovering := fakeNode("a", true, "1", nil)
+	recovering.Recover = true
+	badBackoff := fakeNode("b", true, "1", nil)
+	badBackoff.Recover = true
+	badBackoff.Restart = RestartOnPanic
+	badBackoff.RestartBackoff = "soon"
+	unused := fakeNode("c", true, "1", nil)
+	unused.Restart = RestartOnPanic
+	busy := fakeNode("d", true, "1", nil)
+	busy.Recover = true
+	busy.Restart = RestartOnPanic
+	busy.RestartBackoff = "0s"
+	limited := fakeNode("e", true, "1", nil)
+	limited.Recover = true
+	limited.Restart = RestartOnPanic
+	limited.RestartBackoff = "0s"
+	limited.MaxRestarts = 3
+	g := &Graph{
+		Nodes: map[string]*Node{
+			"a":          recovering,
+			"b":          badBackoff,
+			"c":          unused,
+			"d":          busy,
+			"e":          limited,
+			"supervisor": fakeNode("supervisor", true, "1", nil),
+		},
+		Channels: map[string]*Channel{},
+	}
+	want := Diagnostics{
+		{Severity: SeverityError, Node: "b", Message: `invalid restart backoff "soon": time: invalid duration "soon"`},
+		{Severity: SeverityError, Node: "d", Message: "restart backoff must be positive when restarts are unlimited"},
+		{Severity: SeverityError, Node: "supervisor", Message: `node name "supervisor" is reserved when recovering panics`},
+		{Severity: SeverityWarning, Node: "c", Message: "restart settings have no effect unless panics are recovered"},
+	}
+	if diff, equal := messagediff.PrettyDiff(g.Check(), want); !equal {
+		t.Errorf("g.Check() diff (got -> want)\n%v", diff)
+	}
+	if _, err := g.Go(); err == nil {
+		t.Error("g.Go() = nil error, want error")
+	}
+}
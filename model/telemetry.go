@@ -0,0 +1,45 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "sort"
+
+// TelemetryPackagePath is the import path of the package used by generated
+// code when Telemetry is set.
+const TelemetryPackagePath = "github.com/google/shenzhen-go/telemetry"
+
+// telemetryReserved contains identifiers used in Run when Telemetry is set,
+// which cannot be used as channel or port names.
+var telemetryReserved = map[string]bool{
+	"rec":       true,
+	"send":      true,
+	"telemetry": true,
+}
+
+// SendChannels returns the channels that senders use via a relay when
+// Telemetry is set, which is every channel not connected to a port, sorted
+// by name.
+func (g *Graph) SendChannels() []*Channel {
+	portChans := g.PortChannels()
+	var cs []*Channel
+	for cn, c := range g.Channels {
+		if portChans[cn] != "" {
+			continue
+		}
+		cs = append(cs, c)
+	}
+	sort.Slice(cs, func(i, j int) bool { return cs[i].Name < cs[j].Name })
+	return cs
+}
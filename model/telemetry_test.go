@@ -0,0 +1,92 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/d4l3k/messagediff.v1"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+func telemetryGraph(useContext bool) *Graph {
+	g := testGraph("telemetry", []*Node{
+		portNode("in", "c0", pin.Output),
+		fakeNode("mid", true, "1", map[string]string{"input": "c0", "output": "c1"},
+			&pin.Definition{Name: "input", Type: "int", Direction: pin.Input},
+			&pin.Definition{Name: "output", Type: "int", Direction: pin.Output},
+		),
+		fakeNode("end", true, "1", map[string]string{"input": "c1"},
+			&pin.Definition{Name: "input", Type: "int", Direction: pin.Input},
+		),
+	}, &Channel{Name: "c0"}, &Channel{Name: "c1", Capacity: 3})
+	g.Telemetry, g.UseContext = true, useContext
+	return g
+}
+
+func TestGoTemplateTelemetry(t *testing.T) {
+	for _, useContext := range []bool{false, true} {
+		g := telemetryGraph(useContext)
+		src, err := g.Go()
+		if err != nil {
+			t.Fatalf("Go() = error %v", err)
+		}
+		want := []string{
+			`"github.com/google/shenzhen-go/telemetry"`,
+			"rec := telemetry.Start()",
+			"defer rec.Stop()",
+			"c1 chan<- int",
+			`send.c1 = rec.Channel("c1", c1).(chan int)`,
+			`rec.Node("mid").Start()`,
+			`rec.Node("end").Finish()`,
+			"c0, send.c1)",
+			"c0 := in",
+		}
+		if useContext {
+			want = append(want, `rec.Node("mid").Fail(err)`)
+		}
+		for _, w := range want {
+			if !strings.Contains(src, w) {
+				t.Errorf("Go() with UseContext = %t does not contain %q; got:\n%s", useContext, w, src)
+			}
+		}
+		// c0 belongs to a port, so it isn't relayed.
+		if notWant := "send.c0"; strings.Contains(src, notWant) {
+			t.Errorf("Go() with UseContext = %t contains %q", useContext, notWant)
+		}
+	}
+}
+
+func TestCheckTelemetryReserved(t *testing.T) {
+	g := &Graph{
+		Telemetry: true,
+		Nodes: map[string]*Node{
+			"rec": portNode("rec", "nil", pin.Output),
+		},
+		Channels: map[string]*Channel{
+			"send": {Name: "send"},
+		},
+	}
+	want := Diagnostics{
+		{Severity: SeverityError, Channel: "send", Message: `channel name "send" is reserved when generating telemetry`},
+		{Severity: SeverityError, Node: "rec", Message: `port name "rec" is reserved when generating telemetry`},
+		{Severity: SeverityWarning, Node: "rec", Pin: "p", Message: "pin is not connected"},
+	}
+	if diff, equal := messagediff.PrettyDiff(g.Check(), want); !equal {
+		t.Errorf("g.Check() diff (got -> want)\n%v", diff)
+	}
+}
@@ -17,8 +17,12 @@ package model
 import (
 	"bytes"
 	"encoding/json"
-	"go/format"
+	"fmt"
 	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/google/shenzhen-go/source"
@@ -26,7 +30,7 @@ import (
 
 const (
 	// Fun fact: go/format fixes trailing commas in function args.
-	goTemplateSrc = `{{if .IsCommand -}}
+	goTemplateSrc = `{{template "buildConstraint" .}}{{if .IsCommand -}}
 // The {{.PackageName}} command was automatically generated by Shenzhen Go.
 package main
 {{else -}}
@@ -46,189 +50,614 @@ var _ = runtime.Compiler
 {{.}}
 {{end -}}
 
-{{range .Nodes}}
-{{if .Comment -}}
-/* {{.Comment}} */
+{{range .Nodes}}{{if not .IsPort}}
+{{template "node" ($.NodeFile .)}}
+{{end}}{{end}}
+
+{{template "run" .}}
+{{template "supervisor" .}}`
+
+	nodeTemplateSrc = `{{template "buildConstraint" .Graph}}// Code generated by Shenzhen Go from node {{printf "%q" .Name}} of graph {{printf "%q" .Graph.SourceName}}. DO NOT EDIT.
+
+{{if .Graph.IsCommand -}}
+package main
+{{else -}}
+package {{.Graph.PackageName}}
+{{end}}
+
+import (
+	{{range .Imports -}}
+	{{.}}
+	{{end -}}
+)
+
+var (
+	_ = runtime.Compiler
+	_ = sync.NewCond
+)
+
+{{with .Init -}}
+{{.}}
 {{end -}}
-func {{.Identifier}}({{range $name, $type := .PinFullTypes}}{{$name}} {{$type}},{{end}}) {
-	// {{ .Name }}
-	{{if .UsesMultiplicity -}}
-	multiplicity := {{.ExpandedMult}}
+
+{{template "node" .}}`
+
+	mainTemplateSrc = `{{template "buildConstraint" .}}// Code generated by Shenzhen Go from graph {{printf "%q" .SourceName}}. DO NOT EDIT.
+
+{{if .IsCommand -}}
+// The {{.PackageName}} command was automatically generated by Shenzhen Go.
+package main
+{{else -}}
+// Package {{.PackageName}} was automatically generated by Shenzhen Go.
+package {{.PackageName}}{{if ne .PackagePath .PackageName}} // import "{{.PackagePath}}"{{end}}
+{{end}}
+
+import (
+	{{range .MainImports -}}
+	{{.}}
 	{{end -}}
-	{{.Impl.Head}}
-	{{if .Impl.Tail -}}
-	defer func() {
-		{{.Impl.Tail}}
-	}()
+)
+
+{{template "run" .}}
+{{template "supervisor" .}}`
+
+	// buildConstraintSrc starts every generated file. Generic functions
+	// need Go 1.18, even if the module containing the package is older.
+	buildConstraintSrc = `{{define "buildConstraint"}}{{if .Generics}}//go:build go1.18
+
+{{end}}{{end}}`
+
+	// supervisorTemplateSrc is the supervisor type, shared by goTemplate
+	// and mainTemplate, used by nodes that recover panics.
+	supervisorTemplateSrc = `{{define "supervisor"}}{{if .Supervised}}
+// supervisor recovers panics in the part of a node, and may restart it.
+// Panics in other goroutines started by the part aren't recovered.
+type supervisor struct {
+	node        string
+	restart     bool          // restart after recovering a panic
+	maxRestarts int           // restarts per instance, or 0 for no limit
+	backoff     time.Duration // before the first restart; doubles after each restart
+	metrics     bool          // count restarts in Prometheus metrics
+}
+{{if .UseContext}}
+// run calls body until it returns without panicking, restarting it after a
+// panic if s allows. A panic that isn't followed by a restart is returned
+// as an error.
+func (s supervisor) run(ctx context.Context, instanceNumber int, body func() error) error {
+	backoff := s.backoff
+	for restarts := 0; ; restarts++ {
+		p, err := s.call(body)
+		if p == nil {
+			return err
+		}
+		if !s.restart || (s.maxRestarts > 0 && restarts >= s.maxRestarts) {
+			return fmt.Errorf("node %q panicked: %v", s.node, p)
+		}
+		log.Printf("Node %q panicked, restarting in %v: %v", s.node, backoff, p)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("node %q panicked: %v", s.node, p)
+		case <-time.After(backoff):
+		}
+		s.restarted(instanceNumber)
+		if backoff *= 2; backoff > time.Minute {
+			backoff = time.Minute
+		}
+	}
+}
+
+// call calls body, returning the value of any panic.
+func (supervisor) call(body func() error) (p interface{}, err error) {
+	defer func() { p = recover() }()
+	return nil, body()
+}
+{{else}}
+// run calls body until it returns without panicking, restarting it after a
+// panic if s allows. A panic that isn't followed by a restart is logged.
+func (s supervisor) run(instanceNumber int, body func()) {
+	backoff := s.backoff
+	for restarts := 0; ; restarts++ {
+		p := s.call(body)
+		if p == nil {
+			return
+		}
+		if !s.restart || (s.maxRestarts > 0 && restarts >= s.maxRestarts) {
+			log.Printf("Node %q panicked: %v", s.node, p)
+			return
+		}
+		log.Printf("Node %q panicked, restarting in %v: %v", s.node, backoff, p)
+		time.Sleep(backoff)
+		s.restarted(instanceNumber)
+		if backoff *= 2; backoff > time.Minute {
+			backoff = time.Minute
+		}
+	}
+}
+
+// call calls body, returning the value of any panic.
+func (supervisor) call(body func()) (p interface{}) {
+	defer func() { p = recover() }()
+	body()
+	return nil
+}
+{{end}}
+// restarted records that an instance of the node was restarted.
+func (s supervisor) restarted(instanceNumber int) {
+	{{- if .RestartMetrics}}
+	if s.metrics {
+		nodeRestarts.With(prometheus.Labels{
+			"node_name":    s.node,
+			"instance_num": strconv.Itoa(instanceNumber),
+		}).Inc()
+	}
+	{{- end}}
+}
+{{if .RestartMetrics}}
+var nodeRestarts = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "shenzhen_go",
+		Subsystem: "node",
+		Name:      "restarts",
+		Help:      "Restarts of nodes after recovering a panic",
+	},
+	[]string{"node_name", "instance_num"},
+)
+
+func init() {
+	prometheus.MustRegister(nodeRestarts)
+}
+{{end}}{{end}}{{end}}`
+
+	// runTemplateSrc is main and/or Run, shared by goTemplate and mainTemplate.
+	runTemplateSrc = `{{define "run"}}{{with .SortedParameters}}
+// Config holds the parameters of the graph.
+type Config struct {
+	{{range . -}}
+	{{with .Comment}}{{.}}
 	{{end -}}
-	{{if eq .Multiplicity "1" -}}
-	{{if .UsesInstanceNum -}}
-	const instanceNumber = 0
+	{{.Field}} {{.Type}}
 	{{end -}}
-	{{.Impl.Body}}
-	{{else -}}
-	var multWG sync.WaitGroup
-	multWG.Add(multiplicity)
-	defer multWG.Wait()
-	for n:=0; n<multiplicity; n++ {
-		{{if .UsesInstanceNum -}}
-		instanceNumber := n
+}
+
+// DefaultConfig returns a Config with the default value of each parameter.
+func DefaultConfig() Config {
+	return Config{
+		{{range . -}}
+		{{.Field}}: {{.Value}},
 		{{end -}}
-		go func() {
-			defer multWG.Done()
-			{{.Impl.Body}}
-		}()
 	}
-	{{end -}}
+}
+{{end}}{{if .UseContext}}
+{{if .IsCommand -}}
+func main() {
+	{{- template "flags" .}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first SIGINT or SIGTERM cancels the context; after that, the
+	// default behaviour is restored so a second signal kills the process.
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		signal.Stop(sig)
+		cancel()
+	}()
+
+	if err := Run(ctx{{if .Parameters}}, config{{end}}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
 {{end}}
 
-{{if .IsCommand}}
+// Run executes all the goroutines associated with the graph that generated
+// this package, and waits for any that were marked as "wait for this to
+// finish" to finish before returning. The goroutines are passed a context
+// derived from ctx. The first non-nil error returned by any goroutine cancels
+// that context, and is returned by Run.
+{{- if .Parameters}}
+// The values of the graph's parameters are taken from config.
+{{- end}}
+{{- if .Ports}}
+// The other arguments are channels for the ports of the graph.
+{{- end}}
+func Run(ctx context.Context, {{if .Parameters}}config Config, {{end}}{{range .Ports}}{{.Identifier}} {{.Type}},{{end}}) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errc := make(chan error, 1)
+	fail := func(err error) {
+		select {
+		case errc <- err:
+		default:
+		}
+		cancel()
+	}
+{{else if .IsCommand}}
 func main() {
+	{{- template "flags" .}}
 {{else}}
 // Run executes all the goroutines associated with the graph that generated 
 // this package, and waits for any that were marked as "wait for this to 
 // finish" to finish before returning.
-func Run() {
+{{- if .Parameters}}
+// The values of the graph's parameters are taken from config.
+{{- end}}
+{{- if .Ports}}
+// The arguments are channels for the ports of the graph.
+{{- end}}
+func Run({{if .Parameters}}config Config, {{end}}{{range .Ports}}{{.Identifier}} {{.Type}},{{end}}) {
 {{end}}
+	{{- $portChans := .PortChannels}}
+	{{- if .Telemetry}}
+	rec := telemetry.Start()
+	defer rec.Stop()
+		{{- with .SendChannels}}
+
+	// Senders use relays that count values, instead of the channels.
+	var send struct {
+			{{- range .}}
+		{{.Name}} chan<- {{.Type}}
+			{{- end}}
+	}
+		{{- end}}
+	{{- end}}
 	{{- range $n, $c := .Channels}}
+		{{- with index $portChans $n}}
+	{{$n}} := {{.}}
+		{{- else}}
 	{{$n}} := make(chan {{$c.Type}}, {{$c.Capacity}})
+			{{- if $.Telemetry}}
+	send.{{$n}} = rec.Channel({{printf "%q" $n}}, {{$n}}).(chan {{$c.Type}})
+			{{- end}}
+		{{- end}}
 	{{- end}}
 
 	var wg sync.WaitGroup
 	{{range $node := .Nodes}}
-		{{if $node.Enabled -}}
+		{{if and $node.Enabled (not $node.IsPort) -}}
 			{{if $node.Wait -}}
 	wg.Add(1)
+			{{- end}}
+			{{if or $.UseContext $.Telemetry $node.Wait -}}
 	go func() {
-			{{$node.Identifier}}({{range $pin := $node.Part.Pins}}{{index $node.Connections $pin.Name}},{{end}})
+				{{- if $.Telemetry}}
+		rec.Node({{printf "%q" $node.Name}}).Start()
+				{{- end}}
+				{{- if $.UseContext}}
+		if err := {{$node.Identifier}}(ctx, {{$.RunArgs $node}}); err != nil {
+					{{- if $.Telemetry}}
+			rec.Node({{printf "%q" $node.Name}}).Fail(err)
+					{{- end}}
+			fail(err)
+		}
+				{{- else}}
+		{{$node.Identifier}}({{$.RunArgs $node}})
+				{{- end}}
+				{{- if $.Telemetry}}
+		rec.Node({{printf "%q" $node.Name}}).Finish()
+				{{- end}}
+				{{- if $node.Wait}}
 		wg.Done()
+				{{- end}}
 	}()
 			{{else}}
-	go {{$node.Identifier}}({{range $pin := $node.Part.Pins}}{{index $node.Connections $pin.Name}},{{end}})
+	go {{$node.Identifier}}({{$.RunArgs $node}})
 			{{- end}}
 		{{- end}}
 	{{- end}}
 
 	// Wait for the various goroutines to finish.
 	wg.Wait()
-}`
+	{{- if .UseContext}}
 
-	nodeTemplateSrc = `{{if .Graph.IsCommand -}}
-	package main
-	{{else -}}
-	package {{.Graph.PackageName}}
-	{{end}}
-	
-	import (
-		{{range .Impl.Imports -}}
-		{{.}}
-		{{end -}}
-	)
-	
-	var (
-		_ = runtime.Compiler
-		_ = sync.NewCond
-	)
-	
-	{{if .Comment -}}
-	/* {{.Comment}} */
+	select {
+	case err := <-errc:
+		return err
+	default:
+		return nil
+	}
+	{{- end}}
+}{{end}}
+
+{{- /* flags defines a flag for each parameter and parses the command line. */ -}}
+{{define "flags"}}{{with .SortedParameters}}
+	config := DefaultConfig()
+	{{range . -}}
+	flag.{{.FlagFunc}}(&config.{{.Field}}, {{printf "%q" .Name}}, config.{{.Field}}, {{printf "%q" .Help}})
 	{{end -}}
-	func {{.Identifier}}({{range $name, $type := .PinFullTypes}}{{$name}} {{$type}},{{end}}) {
-		// {{ .Name }}
-		{{if .UsesMultiplicity -}}
-		multiplicity := {{.ExpandedMult}}
-		{{end -}}
-		{{.Impl.Head}}
-		{{if .Impl.Tail -}}
-		defer func() {
-			{{.Impl.Tail}}
-		}()
+	flag.Parse()
+{{end}}{{end}}`
+
+	// nodeFuncTemplateSrc is the function for a node, shared by goTemplate
+	// and nodeTemplate. It is executed with a *nodeFile.
+	nodeFuncTemplateSrc = `{{define "node"}}{{if .Comment -}}
+/* {{.Comment}} */
+{{end -}}
+func {{.Identifier}}({{if .Graph.UseContext}}ctx context.Context, {{end}}{{if .Graph.Parameters}}config Config, {{end}}{{range $name, $type := .PinFullTypes}}{{$name}} {{$type}},{{end}}) {{if .Graph.UseContext}}(err error) {{end}}{
+	// {{ .Name }}
+	{{range .Graph.NodeParameters .Node -}}
+	{{.Name}} := config.{{.Field}}
+	{{end -}}
+	{{if .UsesMultiplicity -}}
+	multiplicity := {{.ExpandedMult}}
+	{{end -}}
+	{{if .Recover -}}
+	nodeSupervisor := {{.Supervisor}}
+	{{end -}}
+	{{.MarkedCode "head"}}
+	{{if .Impl.Tail -}}
+	defer func() {
+		{{.MarkedCode "tail"}}
+	}()
+	{{end -}}
+	{{if eq .Multiplicity "1" -}}
+	{{if or .UsesInstanceNum .Recover -}}
+	const instanceNumber = 0
+	{{end -}}
+	{{if .Recover -}}
+	{{if .Graph.UseContext}}return {{end}}nodeSupervisor.run({{if .Graph.UseContext}}ctx, {{end}}instanceNumber, func() {{if .Graph.UseContext}}(err error) {{end}}{
+		{{.MarkedCode "body"}}
+		{{if .Graph.UseContext -}}
+		return
 		{{end -}}
-		{{if eq .Multiplicity "1" -}}
-		{{if .UsesInstanceNum -}}
-		const instanceNumber = 0
+	})
+	{{else -}}
+	{{.MarkedCode "body"}}
+	{{if .Graph.UseContext -}}
+	return
+	{{end -}}
+	{{end -}}
+	{{else -}}
+	var multWG sync.WaitGroup
+	{{if .Graph.UseContext -}}
+	multErr := make(chan error, 1)
+	{{end -}}
+	multWG.Add(multiplicity)
+	{{if not .Graph.UseContext -}}
+	defer multWG.Wait()
+	{{end -}}
+	for n:=0; n<multiplicity; n++ {
+		{{if or .UsesInstanceNum .Recover -}}
+		instanceNumber := n
 		{{end -}}
-		{{.Impl.Body}}
-		{{else -}}
-		var multWG sync.WaitGroup
-		multWG.Add(multiplicity)
-		defer multWG.Wait()
-		for n:=0; n<multiplicity; n++ {
-			{{if .UsesInstanceNum -}}
-			instanceNumber := n
+		go func() {
+			defer multWG.Done()
+			{{if .Graph.UseContext -}}
+			if err := {{if .Recover}}nodeSupervisor.run(ctx, instanceNumber, {{end}}func() (err error) {
+				{{.MarkedCode "body"}}
+				return
+			}{{if .Recover}}){{else}}(){{end}}; err != nil {
+				select {
+				case multErr <- err:
+				default:
+				}
+			}
+			{{else if .Recover -}}
+			nodeSupervisor.run(instanceNumber, func() {
+				{{.MarkedCode "body"}}
+			})
+			{{else -}}
+			{{.MarkedCode "body"}}
 			{{end -}}
-			go func() {
-				defer multWG.Done()
-				{{.Impl.Body}}
-			}()
-		}
-		{{end -}}
-	}`
+		}()
+	}
+	{{if .Graph.UseContext -}}
+	multWG.Wait()
+	select {
+	case err = <-multErr:
+	default:
+	}
+	return
+	{{end -}}
+	{{end -}}
+}{{end}}`
 
-	mainTemplateSrc = `{{if .IsCommand -}}
-// The {{.PackageName}} command was automatically generated by Shenzhen Go.
-package main
-{{else -}}
-// Package {{.PackageName}} was automatically generated by Shenzhen Go.
-package {{.PackageName}}{{if ne .PackagePath .PackageName}} // import "{{.PackagePath}}"{{end}}
-{{end}}
+	// testHarnessTemplateSrc is the test helper for a library graph with
+	// ports, regenerated with the package.
+	testHarnessTemplateSrc = `{{template "buildConstraint" .}}// Code generated by Shenzhen Go. DO NOT EDIT.
+
+package {{.PackageName}}
 
 import (
-	{{range .MainImports -}}
+	{{range .Imports -}}
 	{{.}}
 	{{end -}}
 )
 
-{{range .Inits -}}
-{{.}}
-{{end -}}
+// defaultTestTimeout is how long a TestCase may take if it has no Timeout.
+const defaultTestTimeout = 10 * time.Second
 
-{{if .IsCommand}}
-func main() {
-{{else}}
-// Run executes all the goroutines associated with the graph that generated 
-// this package, and waits for any that were marked as "wait for this to 
-// finish" to finish before returning.
-func Run() {
-{{end}}
-	{{- range $n, $c := .Channels}}
-	{{$n}} := make(chan {{$c.Type}}, {{$c.Capacity}})
+// TestCase is a test of Run: the values to send to each input port, and
+// the values expected from each output port.
+type TestCase struct {
+	Name string
+
+	// Timeout limits how long the case may take. Zero means 10 seconds.
+	Timeout time.Duration
+	{{- if .Parameters}}
+
+	// Config is passed to Run. Nil means DefaultConfig().
+	Config *Config
+	{{- end}}
+	{{- with .Inputs}}
+
+	// Values sent to each input port, in order. The channel is closed
+	// after the last value is sent.
+	{{- range .}}
+	{{.Field}} []{{.ElemType}}
+	{{- end}}
+	{{- end}}
+	{{- with .Outputs}}
+
+	// Values expected from each output port, in order. The port must
+	// send exactly these values before it is closed or Run returns.
+	{{- range .}}
+	{{.Field}} []{{.ElemType}}
 	{{- end}}
+	{{- end}}
+}
+
+// RunTestCases runs each test case as a subtest.
+func RunTestCases(t *testing.T, tcs []TestCase) {
+	t.Helper()
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) { runTestCase(t, tc) })
+	}
+}
 
+// runTestCase runs the graph, feeding in the inputs of tc, and checks that
+// each output sends exactly the expected values before the output is
+// closed, or Run returns.
+func runTestCase(t *testing.T, tc TestCase) {
+	timeout := tc.Timeout
+	if timeout == 0 {
+		timeout = defaultTestTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	{{- if .Parameters}}
+
+	config := DefaultConfig()
+	if tc.Config != nil {
+		config = *tc.Config
+	}
+	{{- end}}
+	{{range .Ports}}
+	p{{.Field}} := make(chan {{.ElemType}})
+	{{- end}}
+
+	done := make(chan struct{})
+	{{- if .UseContext}}
+	var runErr error
+	go func() {
+		runErr = Run(ctx, {{if .Parameters}}config, {{end}}{{range .Ports}}p{{.Field}}, {{end}})
+		close(done)
+	}()
+	{{else}}
+	go func() {
+		Run({{if .Parameters}}config, {{end}}{{range .Ports}}p{{.Field}}, {{end}})
+		close(done)
+	}()
+	{{end}}
+	{{- range .Inputs}}
+	go func() {
+		for _, v := range tc.{{.Field}} {
+			select {
+			case p{{.Field}} <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+		close(p{{.Field}})
+	}()
+	{{end}}
 	var wg sync.WaitGroup
-	{{range $node := .Nodes}}
-		{{if $node.Enabled -}}
-			{{if $node.Wait -}}
+	{{- range .Outputs}}
+	var got{{.Field}} []{{.ElemType}}
 	wg.Add(1)
 	go func() {
-			{{$node.Identifier}}({{range $pin := $node.Part.Pins}}{{index $node.Connections $pin.Name}},{{end}})
-		wg.Done()
+		defer wg.Done()
+		for {
+			select {
+			case v, ok := <-p{{.Field}}:
+				if !ok {
+					return
+				}
+				got{{.Field}} = append(got{{.Field}}, v)
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
 	}()
-			{{else}}
-	go {{$node.Identifier}}({{range $pin := $node.Part.Pins}}{{index $node.Connections $pin.Name}},{{end}})
-			{{- end}}
-		{{- end}}
 	{{- end}}
-
-	// Wait for the various goroutines to finish.
 	wg.Wait()
-}`
+	if err := ctx.Err(); err != nil {
+		t.Errorf("outputs not closed, and Run did not return, within %v", timeout)
+	}
+	{{- range .Outputs}}
+	if len(got{{.Field}}) != len(tc.{{.Field}}) || (len(got{{.Field}}) > 0 && !reflect.DeepEqual(got{{.Field}}, tc.{{.Field}})) {
+		t.Errorf("{{.Identifier}} got %v, want %v", got{{.Field}}, tc.{{.Field}})
+	}
+	{{- end}}
+	{{- if .UseContext}}
+
+	// Stop the graph, and check it stops cleanly.
+	cancel()
+	select {
+	case <-done:
+		if runErr != nil && runErr != context.Canceled && runErr != context.DeadlineExceeded {
+			t.Errorf("Run() = %v", runErr)
+		}
+	case <-time.After(timeout):
+		t.Errorf("Run() did not return within %v of cancelling", timeout)
+	}
+	{{- end}}
+}
+`
+
+	// testScaffoldTemplateSrc is a starting point for tests of a library
+	// graph with ports. It is only written if it doesn't exist.
+	testScaffoldTemplateSrc = `{{template "buildConstraint" .}}package {{.PackageName}}
+
+import "testing"
+
+func TestRun(t *testing.T) {
+	RunTestCases(t, []TestCase{
+		{
+			Name: "example",
+			// TODO: Add values to send to each input port.
+			{{- range .Inputs}}
+			{{.Field}}: []{{.ElemType}}{},
+			{{- end}}
+			// TODO: Add the values expected from each output port.
+			{{- range .Outputs}}
+			{{.Field}}: []{{.ElemType}}{},
+			{{- end}}
+		},
+	})
+}
+`
 )
 
 var (
-	goTemplate   = template.Must(template.New("golang").Parse(goTemplateSrc))
-	nodeTemplate = template.Must(template.New("golang-node").Parse(nodeTemplateSrc))
-	mainTemplate = template.Must(template.New("golang-main").Parse(mainTemplateSrc))
+	goTemplate   = parseTemplate("golang", goTemplateSrc, nodeFuncTemplateSrc, runTemplateSrc, supervisorTemplateSrc, buildConstraintSrc)
+	nodeTemplate = parseTemplate("golang-node", nodeTemplateSrc, nodeFuncTemplateSrc, buildConstraintSrc)
+	mainTemplate = parseTemplate("golang-main", mainTemplateSrc, runTemplateSrc, supervisorTemplateSrc, buildConstraintSrc)
+
+	testHarnessTemplate  = parseTemplate("golang-test-harness", testHarnessTemplateSrc, buildConstraintSrc)
+	testScaffoldTemplate = parseTemplate("golang-test-scaffold", testScaffoldTemplateSrc, buildConstraintSrc)
 )
 
+// parseTemplate parses the sources into one template, panicking on errors.
+func parseTemplate(name string, srcs ...string) *template.Template {
+	t := template.New(name)
+	for _, src := range srcs {
+		t = template.Must(t.Parse(src))
+	}
+	return t
+}
+
 // WriteRawGoTo writes the Go language view of the graph to the io.Writer, without gofmt-ing.
 func (g *Graph) WriteRawGoTo(w io.Writer) error {
+	if err := firstError(g.checkParameters); err != nil {
+		return err
+	}
+	if err := g.checkSupervisors(); err != nil {
+		return err
+	}
 	if err := g.InferTypes(); err != nil {
 		return err
 	}
-	for _, n := range g.Nodes {
-		n.RefreshImpl()
+	if err := firstError(g.checkNodeParameters); err != nil {
+		return err
+	}
+	if err := g.resolveImportConflicts(); err != nil {
+		return err
 	}
 	return goTemplate.Execute(w, g)
 }
@@ -248,7 +677,12 @@ func (g *Graph) WriteGoTo(w io.Writer) error {
 	if err := g.WriteRawGoTo(buf); err != nil {
 		return err
 	}
-	return source.GoFmt(w, buf)
+	o, err := g.formatGo(MainFileName, buf.Bytes(), NewLineMap())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(o)
+	return err
 }
 
 // Go outputs the Go language view of the graph.
@@ -257,19 +691,195 @@ func (g *Graph) Go() (string, error) {
 	if err := g.WriteRawGoTo(buf); err != nil {
 		return "", err
 	}
-	o, err := format.Source(buf.Bytes())
+	o, err := g.formatGo(MainFileName, buf.Bytes(), NewLineMap())
 	return string(o), err
 }
 
-// WriteJSONTo writes nicely-formatted JSON to the given Writer.
+// WriteJSONTo writes nicely-formatted JSON to the given Writer. The JSON is
+// canonical, for diffability: keys are sorted, node positions are rounded,
+// parts save code as trimmed lines (see CodeLines), and characters such as
+// "<" are not escaped.
 func (g *Graph) WriteJSONTo(w io.Writer) error {
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "\t") // For diffability!
-	return enc.Encode(g)
+	j, err := json.MarshalIndent(g, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(unescapeHTML(j), '\n'))
+	return err
 }
 
-// JSON returns the JSON view of the graph.
+// JSON returns the JSON view of the graph, as written by WriteJSONTo.
 func (g *Graph) JSON() (string, error) {
-	o, err := json.MarshalIndent(g, "", "\t")
-	return string(o), err
+	buf := &bytes.Buffer{}
+	if err := g.WriteJSONTo(buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// nodeFile is the data used to execute nodeTemplate, and the "node"
+// template for the function of the node.
+type nodeFile struct {
+	*Node
+	Graph *Graph
+	Init  string // init section of the part type, if this file includes it
+}
+
+// NodeFile returns the data for executing the "node" template for n, which
+// is the function of the node.
+func (g *Graph) NodeFile(n *Node) *nodeFile {
+	return &nodeFile{Node: n, Graph: g}
+}
+
+// Imports returns the imports needed by the file for the node.
+func (f *nodeFile) Imports() []string {
+	m := source.NewStringSet(`"runtime"`, `"sync"`)
+	if f.Graph.UseContext {
+		m.Add(`"context"`)
+	}
+	addImports(m, f.Impl.Imports)
+	if f.Recover && f.Restart == RestartOnPanic {
+		m.Add(`"time"`) // for the backoff
+	}
+	for _, t := range f.PinTypes {
+		f.Graph.addQualifierImports(m, t)
+	}
+	return m.Slice()
+}
+
+// MainFileName is the name of the file containing the package clause and
+// either main or Run, written by GoFiles.
+const MainFileName = "generated.go"
+
+// NodeFileName returns the name of the Go file for a node, written by
+// GoFiles. The identifier is between dots so that it cannot make the
+// file a test or give it an implicit build constraint (e.g. "_test" or
+// "_linux").
+func NodeFileName(n *Node) string {
+	return "generated." + n.Identifier() + ".node.go"
+}
+
+// IsNodeFileName reports whether name could have been returned by
+// NodeFileName. This is useful for cleaning up files for deleted nodes.
+func IsNodeFileName(name string) bool {
+	return strings.HasPrefix(name, "generated.") && strings.HasSuffix(name, ".node.go")
+}
+
+// generatedFromRE matches the comment at the top of files written by
+// GoFiles, which says which graph they were generated from.
+var generatedFromRE = regexp.MustCompile(`(?m)^// Code generated by Shenzhen Go from (?:node "(?:[^"\\]|\\.)*" of )?graph ("(?:[^"\\]|\\.)*")\. DO NOT EDIT\.$`)
+
+// SourceName returns the path of the graph file, which files written by
+// GoFiles record as where they came from. Inside a module, the path is
+// relative to the module, so it doesn't depend on where the module is
+// checked out. Otherwise it is the absolute path.
+func (g *Graph) SourceName() string {
+	if g.FilePath == "" {
+		return ""
+	}
+	abs, err := filepath.Abs(g.FilePath)
+	if err != nil {
+		return filepath.Clean(g.FilePath)
+	}
+	modDir, _, err := source.FindModule(filepath.Dir(abs))
+	if err != nil {
+		return abs
+	}
+	rel, err := filepath.Rel(modDir, abs)
+	if err != nil {
+		return abs
+	}
+	return filepath.ToSlash(rel)
+}
+
+// GeneratedFrom returns the SourceName of the graph that a file written by
+// GoFiles was generated from, or false if src doesn't say. Files generated
+// before the graph was recorded don't say.
+func GeneratedFrom(src []byte) (string, bool) {
+	m := generatedFromRE.FindSubmatch(src)
+	if m == nil {
+		return "", false
+	}
+	sn, err := strconv.Unquote(string(m[1]))
+	if err != nil {
+		return "", false
+	}
+	return sn, true
+}
+
+// RawGoFiles returns the package as one file per node, plus MainFileName,
+// keyed by file name, without gofmt-ing.
+func (g *Graph) RawGoFiles() (map[string][]byte, error) {
+	if err := firstError(g.checkParameters); err != nil {
+		return nil, err
+	}
+	if err := g.checkSupervisors(); err != nil {
+		return nil, err
+	}
+	if err := g.InferTypes(); err != nil {
+		return nil, err
+	}
+	for _, n := range g.Nodes {
+		g.refreshImpl(n)
+	}
+	if err := firstError(g.checkNodeParameters); err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	buf := &bytes.Buffer{}
+	if err := mainTemplate.Execute(buf, g); err != nil {
+		return nil, err
+	}
+	files[MainFileName] = buf.Bytes()
+
+	// Each init section goes in the file of the first node needing it,
+	// which has the imports it needs.
+	inits := g.Inits()
+	for _, nn := range sortedNodeNames(g.Nodes) {
+		n := g.Nodes[nn]
+		if n.IsPort() {
+			continue
+		}
+		f := &nodeFile{Node: n, Graph: g}
+		if k := n.Part.TypeKey(); n.Impl.NeedsInit && inits[k] != "" {
+			f.Init = inits[k]
+			delete(inits, k)
+		}
+		buf := &bytes.Buffer{}
+		if err := nodeTemplate.Execute(buf, f); err != nil {
+			return nil, fmt.Errorf("node %q: %v", nn, err)
+		}
+		files[NodeFileName(n)] = buf.Bytes()
+	}
+	return files, nil
+}
+
+// GoFiles returns the Go source of the package as one file per node, plus
+// MainFileName. The files are keyed by file name and are gofmt-ed.
+func (g *Graph) GoFiles() (map[string][]byte, error) {
+	files, _, err := g.GoFilesWithLineMap()
+	return files, err
+}
+
+// GoFilesWithLineMap is like GoFiles, but also returns a LineMap from the
+// lines of the files to the code of the nodes.
+func (g *Graph) GoFilesWithLineMap() (map[string][]byte, *LineMap, error) {
+	files, err := g.RawGoFiles()
+	if err != nil {
+		return nil, nil, err
+	}
+	m := NewLineMap()
+	for _, n := range g.Nodes {
+		if !n.IsPort() {
+			m.nodeFiles[NodeFileName(n)] = n.Name
+		}
+	}
+	for name, src := range files {
+		o, err := g.formatGo(name, src, m)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", name, err)
+		}
+		files[name] = o
+	}
+	return files, m, nil
 }
@@ -14,7 +14,15 @@
 
 package model
 
-import "testing"
+import (
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
 
 type nopWriter struct{}
 
@@ -31,3 +39,109 @@ func TestGoTemplate(t *testing.T) {
 		}
 	}
 }
+
+func TestGoTemplateUseContext(t *testing.T) {
+	for name, tg := range TestGraphs {
+		g := *tg
+		g.UseContext = true
+		g.RefreshChannelsPins()
+		src, err := g.Go()
+		if err != nil {
+			t.Errorf("Go() for %v = error %v", name, err)
+			continue
+		}
+		if want := "func Run(ctx context.Context) error {"; !strings.Contains(src, want) {
+			t.Errorf("Go() for %v does not contain %q", name, want)
+		}
+		if got, want := strings.Contains(src, "signal.Notify("), g.IsCommand; got != want {
+			t.Errorf("Go() for %v contains signal.Notify = %t, want %t", name, got, want)
+		}
+	}
+}
+
+func TestGoFiles(t *testing.T) {
+	a := fakeNode("a", true, "1", map[string]string{"output": "c"},
+		&pin.Definition{Name: "output", Type: "time.Duration", Direction: pin.Output},
+	)
+	a.Part.(*FakePart).Impts = []string{`"math/rand"`, `"time"`}
+	a.Part.(*FakePart).Body = "output <- time.Duration(rand.Int())"
+	b := fakeNode("b", true, "1", map[string]string{"input": "c"},
+		&pin.Definition{Name: "input", Type: "$T", Direction: pin.Input},
+	)
+	b.Part.(*FakePart).Impts = []string{`"crypto/rand"`}
+	b.Part.(*FakePart).Body = "rand.Reader.Read(nil); <-input"
+	g := testGraph("files", []*Node{a, b}, &Channel{Name: "c"})
+	g.FilePath = "some/dir/files.szgo"
+
+	files, err := g.GoFiles()
+	if err != nil {
+		t.Fatalf("GoFiles() = error %v", err)
+	}
+	// Which imports each file should and should not have.
+	tests := map[string]struct {
+		want, notWant []string
+	}{
+		MainFileName:          {want: []string{"sync", "time"}, notWant: []string{"math/rand", "crypto/rand"}},
+		"generated.a.node.go": {want: []string{"math/rand", "time"}, notWant: []string{"crypto/rand"}},
+		"generated.b.node.go": {want: []string{"crypto/rand", "time"}, notWant: []string{"math/rand"}},
+	}
+	if got, want := len(files), len(tests); got != want {
+		t.Errorf("len(GoFiles()) = %d, want %d", got, want)
+	}
+	for name, test := range tests {
+		src, ok := files[name]
+		if !ok {
+			t.Errorf("GoFiles() has no file %q", name)
+			continue
+		}
+		if !IsNodeFileName(name) && name != MainFileName {
+			t.Errorf("IsNodeFileName(%q) = false, want true", name)
+		}
+		// The tests run in the model directory of the module.
+		if sn, ok := GeneratedFrom(src); !ok || sn != "model/some/dir/files.szgo" {
+			t.Errorf("GeneratedFrom(%s) = %q, %t, want model/some/dir/files.szgo, true", name, sn, ok)
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), name, src, parser.ImportsOnly)
+		if err != nil {
+			t.Errorf("parsing %s: %v", name, err)
+			continue
+		}
+		imps := make(map[string]bool)
+		for _, is := range f.Imports {
+			p, _ := strconv.Unquote(is.Path.Value)
+			imps[p] = true
+		}
+		for _, w := range test.want {
+			if !imps[w] {
+				t.Errorf("%s does not import %q", name, w)
+			}
+		}
+		for _, nw := range test.notWant {
+			if imps[nw] {
+				t.Errorf("%s imports %q", name, nw)
+			}
+		}
+	}
+	if IsNodeFileName(MainFileName) {
+		t.Errorf("IsNodeFileName(%q) = true, want false", MainFileName)
+	}
+}
+
+func TestGeneratedFrom(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+		ok   bool
+	}{
+		{"// Code generated by Shenzhen Go from graph \"a.szgo\". DO NOT EDIT.\n\npackage a\n", "a.szgo", true},
+		{"//go:build go1.18\n\n// Code generated by Shenzhen Go from node \"say \\\"hi\\\"\" of graph \"b.szgo\". DO NOT EDIT.\n", "b.szgo", true},
+		{"// Code generated by Shenzhen Go from node \"a\". DO NOT EDIT.\n", "", false},
+		{"// Package a was automatically generated by Shenzhen Go.\npackage a\n", "", false},
+	}
+	for _, test := range tests {
+		got, ok := GeneratedFrom([]byte(test.src))
+		if got != test.want || ok != test.ok {
+			t.Errorf("GeneratedFrom(%q) = %q, %t, want %q, %t", test.src, got, ok, test.want, test.ok)
+		}
+	}
+}
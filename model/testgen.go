@@ -0,0 +1,142 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/shenzhen-go/model/pin"
+	"github.com/google/shenzhen-go/source"
+)
+
+const (
+	// TestHarnessFileName is the name of the file containing TestCase and
+	// RunTestCases, which is regenerated with the package.
+	TestHarnessFileName = "generated_test.go"
+
+	// TestScaffoldFileName is the name of the file of tests that use the
+	// harness. It is a starting point for editing, so it is only written if
+	// it doesn't exist.
+	TestScaffoldFileName = "run_test.go"
+)
+
+// Identifiers in the test harness, which nodes can't use.
+var testHarnessIdents = map[string]bool{
+	"TestCase":           true,
+	"RunTestCases":       true,
+	"runTestCase":        true,
+	"defaultTestTimeout": true,
+}
+
+// testPort is a port of the graph, as a field of the generated TestCase.
+type testPort struct {
+	*PortParam
+}
+
+// Field returns the name of the TestCase field for the port: the
+// identifier, starting with an upper-case letter.
+func (p testPort) Field() string {
+	id := p.Identifier()
+	r, n := utf8.DecodeRuneInString(id)
+	return string(unicode.ToUpper(r)) + id[n:]
+}
+
+// testHarness is the data used to execute the test templates.
+type testHarness struct {
+	*Graph
+	Ports, Inputs, Outputs []testPort
+	Imports                []string
+}
+
+// newTestHarness checks the graph can be tested, and arranges its ports.
+func (g *Graph) newTestHarness() (*testHarness, error) {
+	if g.IsCommand {
+		return nil, errors.New("only library graphs can be tested; this graph is a command")
+	}
+	if err := firstError(g.checkParameters); err != nil {
+		return nil, err
+	}
+	if err := g.InferTypes(); err != nil {
+		return nil, err
+	}
+	for _, n := range g.Nodes {
+		g.refreshImpl(n)
+	}
+	ps := g.Ports()
+	if len(ps) == 0 {
+		return nil, errors.New("graph has no ports to test with")
+	}
+	for _, nn := range sortedNodeNames(g.Nodes) {
+		if id := g.Nodes[nn].Identifier(); testHarnessIdents[id] {
+			return nil, fmt.Errorf("node %q conflicts with the generated test harness; rename it", nn)
+		}
+	}
+	h := &testHarness{Graph: g}
+	fields := map[string]string{"Name": "", "Timeout": "", "Config": ""}
+	imps := source.NewStringSet(`"context"`, `"reflect"`, `"sync"`, `"testing"`, `"time"`)
+	for _, p := range ps {
+		tp := testPort{p}
+		if o, ok := fields[tp.Field()]; ok {
+			if o == "" {
+				return nil, fmt.Errorf("port %q would be the TestCase field %s, which is reserved; rename it", p.Node.Name, tp.Field())
+			}
+			return nil, fmt.Errorf("ports %q and %q would both be the TestCase field %s", o, p.Node.Name, tp.Field())
+		}
+		fields[tp.Field()] = p.Node.Name
+		h.Ports = append(h.Ports, tp)
+		if p.Direction() == pin.Input {
+			h.Inputs = append(h.Inputs, tp)
+		} else {
+			h.Outputs = append(h.Outputs, tp)
+		}
+		g.addQualifierImports(imps, p.Node.PinTypes[p.Node.Part.(Port).PortPin()])
+	}
+	h.Imports = imps.Slice()
+	return h, nil
+}
+
+// TestHarness returns the Go source of a test helper for the graph, to be
+// written to TestHarnessFileName in the package. It declares TestCase,
+// which lists the values to send to each input port and the values
+// expected from each output port, and RunTestCases, which runs the graph
+// for each TestCase and checks the outputs arrive before a timeout.
+// Only library graphs with ports can be tested.
+func (g *Graph) TestHarness() ([]byte, error) {
+	return g.executeTestTemplate(testHarnessTemplate)
+}
+
+// TestScaffold returns the Go source of a test using the harness, with an
+// example TestCase, to be written to TestScaffoldFileName.
+func (g *Graph) TestScaffold() ([]byte, error) {
+	return g.executeTestTemplate(testScaffoldTemplate)
+}
+
+func (g *Graph) executeTestTemplate(t *template.Template) ([]byte, error) {
+	h, err := g.newTestHarness()
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := t.Execute(buf, h); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
@@ -0,0 +1,116 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+func TestTestHarness(t *testing.T) {
+	g := portGraph()
+	g.UseContext = true
+	src, err := g.TestHarness()
+	if err != nil {
+		t.Fatalf("g.TestHarness() = error %v", err)
+	}
+	for _, want := range []string{
+		"package ports\n",
+		"\tIn []int\n",
+		"\tOut []string\n",
+		"runErr = Run(ctx, pIn, pOut)",
+		"case pIn <- v:",
+		"case v, ok := <-pOut:",
+		"func RunTestCases(t *testing.T, tcs []TestCase) {",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("g.TestHarness() does not contain %q:\n%s", want, src)
+		}
+	}
+	if strings.Contains(string(src), "Config") {
+		t.Errorf("g.TestHarness() for graph without parameters contains Config:\n%s", src)
+	}
+
+	src, err = g.TestScaffold()
+	if err != nil {
+		t.Fatalf("g.TestScaffold() = error %v", err)
+	}
+	for _, want := range []string{
+		"In: []int{},",
+		"Out: []string{},",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("g.TestScaffold() does not contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestTestHarnessErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph func() *Graph
+	}{
+		{
+			name: "command",
+			graph: func() *Graph {
+				g := portGraph()
+				g.IsCommand = true
+				return g
+			},
+		},
+		{
+			name: "no ports",
+			graph: func() *Graph {
+				g := portGraph()
+				delete(g.Nodes, "in")
+				delete(g.Nodes, "out")
+				return g
+			},
+		},
+		{
+			name: "reserved field",
+			graph: func() *Graph {
+				g := portGraph()
+				g.Nodes["timeout"] = portNode("timeout", "c0", pin.Output)
+				return g
+			},
+		},
+		{
+			name: "same field",
+			graph: func() *Graph {
+				g := portGraph()
+				g.Nodes["In"] = portNode("In", "c0", pin.Output)
+				return g
+			},
+		},
+		{
+			name: "reserved node",
+			graph: func() *Graph {
+				g := portGraph()
+				g.Nodes["TestCase"] = fakeNode("TestCase", true, "1", nil)
+				return g
+			},
+		},
+	}
+	for _, test := range tests {
+		g := test.graph()
+		g.RefreshChannelsPins()
+		if _, err := g.TestHarness(); err == nil {
+			t.Errorf("%s: TestHarness() = nil error, want error", test.name)
+		}
+	}
+}
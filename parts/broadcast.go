@@ -17,6 +17,7 @@ package parts
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/google/shenzhen-go/model"
 	"github.com/google/shenzhen-go/model/pin"
@@ -72,6 +73,20 @@ func (b Broadcast) Impl(n *model.Node) model.PartImpl {
 	}
 }
 
+// GenericImpl returns an implementation calling generic.Broadcast.
+func (b Broadcast) GenericImpl(n *model.Node) model.PartImpl {
+	args := []string{"input"}
+	for i := uint(0); i < b.OutputNum; i++ {
+		if o := fmt.Sprintf("output%d", i); n.Connections[o] != "nil" {
+			args = append(args, o)
+		}
+	}
+	impl := b.Impl(n)
+	impl.Imports = append(impl.Imports, genericImport)
+	impl.Body = fmt.Sprintf("generic.Broadcast(%s)", strings.Join(args, ", "))
+	return impl
+}
+
 // Pins returns a map with one input and N outputs.
 func (b Broadcast) Pins() pin.Map {
 	m := pin.NewMap(&pin.Definition{
@@ -85,6 +100,7 @@ func (b Broadcast) Pins() pin.Map {
 			Name:      n,
 			Direction: pin.Output,
 			Type:      "$Any",
+			Optional:  true,
 		}
 	}
 	return m
@@ -45,7 +45,7 @@ var (
 		&pin.Definition{
 			Name:      "get",
 			Direction: pin.Input,
-			Type:      cacheGetType(cacheKeyTypeParam, cacheCtxTypeParam),
+			Type:      cacheGetType(cacheKeyTypeParam+":comparable", cacheCtxTypeParam), // keys of the map
 		},
 		&pin.Definition{
 			Name:      "put",
@@ -398,6 +398,25 @@ func (c *Cache) Impl(n *model.Node) model.PartImpl {
 	}
 }
 
+// GenericImpl returns an implementation using generic.Cache. Caches with
+// Prometheus metrics use the same implementation as Impl.
+func (c *Cache) GenericImpl(n *model.Node) model.PartImpl {
+	if c.EnablePrometheus {
+		return c.Impl(n)
+	}
+	mode := "generic.EvictLRU"
+	if c.EvictionMode == EvictMRU {
+		mode = "generic.EvictMRU"
+	}
+	return model.PartImpl{
+		Imports: []string{genericImport},
+		Head: fmt.Sprintf("cache := generic.NewCache[%s, %s](%d, %s)",
+			n.TypeParams[cacheKeyTypeParam], n.TypeParams[cacheCtxTypeParam], c.ContentBytesLimit, mode),
+		Body: "cache.Serve(get, put, hit, miss)",
+		Tail: `close(hit); close(miss)`,
+	}
+}
+
 // Pins returns a pin map.
 func (c *Cache) Pins() pin.Map {
 	return cachePins
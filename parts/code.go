@@ -15,6 +15,7 @@
 package parts
 
 import (
+	"encoding/json"
 	"strings"
 
 	"github.com/google/shenzhen-go/model"
@@ -115,6 +116,17 @@ func NewCode(imports []string, head, body, tail string, pins pin.Map) *Code {
 	}
 }
 
+// MarshalJSON encodes the Code part, with canonical lines of code.
+func (c *Code) MarshalJSON() ([]byte, error) {
+	type plainCode Code // without the MarshalJSON method
+	pc := plainCode(*c)
+	pc.Imports = model.CodeLines(c.Imports)
+	pc.Head = model.CodeLines(c.Head)
+	pc.Body = model.CodeLines(c.Body)
+	pc.Tail = model.CodeLines(c.Tail)
+	return json.Marshal(&pc)
+}
+
 // Pins returns pins. These are 100% user-defined.
 func (c *Code) Pins() pin.Map { return c.PinMap }
 
@@ -22,11 +22,13 @@ import (
 	"strings"
 
 	"github.com/google/shenzhen-go/dom"
+	"github.com/google/shenzhen-go/model"
 	"github.com/google/shenzhen-go/model/pin"
 )
 
 var (
 	codePinsSession, codeImportsSession, codeHeadSession, codeBodySession, codeTailSession *dom.AceSession
+	codeHeadEditor, codeBodyEditor, codeTailEditor                                         *dom.AceEditor
 
 	linkCodeFormatHead = doc.ElementByID("code-format-head-link")
 	linkCodeFormatBody = doc.ElementByID("code-format-body-link")
@@ -39,9 +41,12 @@ var (
 func init() {
 	codePinsSession = setupAce("code-pins", dom.AceJSONMode, codePinsChange)
 	codeImportsSession = setupAce("code-imports", dom.AceGoMode, codeImportsChange)
-	codeHeadSession = setupAce("code-head", dom.AceGoMode, codeHeadChange)
-	codeBodySession = setupAce("code-body", dom.AceGoMode, codeBodyChange)
-	codeTailSession = setupAce("code-tail", dom.AceGoMode, codeTailChange)
+	codeHeadEditor = setupAceEditor("code-head", dom.AceGoMode, codeHeadChange)
+	codeBodyEditor = setupAceEditor("code-body", dom.AceGoMode, codeBodyChange)
+	codeTailEditor = setupAceEditor("code-tail", dom.AceGoMode, codeTailChange)
+	codeHeadSession = codeHeadEditor.Session()
+	codeBodySession = codeBodyEditor.Session()
+	codeTailSession = codeTailEditor.Session()
 
 	linkCodeFormatHead.AddEventListener("click", formatHandler(codeHeadSession))
 	linkCodeFormatBody.AddEventListener("click", formatHandler(codeBodySession))
@@ -85,4 +90,38 @@ func (c *Code) GainFocus() {
 	codeHeadSession.SetValue(strings.Join(c.Head, "\n"))
 	codeBodySession.SetValue(strings.Join(c.Body, "\n"))
 	codeTailSession.SetValue(strings.Join(c.Tail, "\n"))
+	for _, s := range []*dom.AceSession{codeHeadSession, codeBodySession, codeTailSession} {
+		s.ClearAnnotations()
+	}
+}
+
+// CodePanel returns the name of the panel for editing a section.
+func (c *Code) CodePanel(s model.CodeSection) string {
+	switch s {
+	case model.HeadSection:
+		return "Head"
+	case model.BodySection:
+		return "Body"
+	case model.TailSection:
+		return "Tail"
+	}
+	return ""
+}
+
+// HighlightCode scrolls to a line of a section, and marks it with the
+// message.
+func (c *Code) HighlightCode(s model.CodeSection, line int, message string) {
+	var e *dom.AceEditor
+	switch s {
+	case model.HeadSection:
+		e = codeHeadEditor
+	case model.BodySection:
+		e = codeBodyEditor
+	case model.TailSection:
+		e = codeTailEditor
+	default:
+		return
+	}
+	e.Session().SetErrorAnnotation(line, message)
+	e.GotoLine(line)
 }
@@ -17,6 +17,7 @@ package parts
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/google/shenzhen-go/model"
 	"github.com/google/shenzhen-go/model/pin"
@@ -87,6 +88,20 @@ func (g Gather) Impl(n *model.Node) model.PartImpl {
 	}
 }
 
+// GenericImpl returns an implementation calling generic.Gather.
+func (g Gather) GenericImpl(n *model.Node) model.PartImpl {
+	args := []string{"output"}
+	for i := uint(0); i < g.InputNum; i++ {
+		if in := fmt.Sprintf("input%d", i); n.Connections[in] != "nil" {
+			args = append(args, in)
+		}
+	}
+	impl := g.Impl(n)
+	impl.Imports = append(impl.Imports, genericImport)
+	impl.Body = fmt.Sprintf("generic.Gather(%s)", strings.Join(args, ", "))
+	return impl
+}
+
 // Pins returns a map with N inputs and 1 output.
 func (g Gather) Pins() pin.Map {
 	m := pin.NewMap(&pin.Definition{
@@ -100,6 +115,7 @@ func (g Gather) Pins() pin.Map {
 			Name:      n,
 			Direction: pin.Input,
 			Type:      "$Any",
+			Optional:  true,
 		}
 	}
 	return m
@@ -0,0 +1,27 @@
+//go:build go1.18
+
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generic
+
+// Broadcast sends every value read from input to each of the outputs, until
+// input is closed.
+func Broadcast[T any](input <-chan T, outputs ...chan<- T) {
+	for in := range input {
+		for _, o := range outputs {
+			o <- in
+		}
+	}
+}
@@ -0,0 +1,166 @@
+//go:build go1.18
+
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generic
+
+import (
+	"sync"
+	"time"
+)
+
+// EvictionMode is how a Cache decides which content to evict to stay under
+// its memory limit.
+type EvictionMode int
+
+// Cache eviction modes.
+const (
+	EvictLRU EvictionMode = iota // Least recently used
+	EvictMRU                     // Most recently used
+)
+
+type cacheEntry struct {
+	data []byte
+	last time.Time
+	sync.Mutex
+}
+
+// Cache caches content in memory, keyed by values of type K. Requests for
+// content come with a context of type C, which is passed along with the
+// response. A Cache is safe for concurrent use, so the instances of a node
+// can share one.
+type Cache[K comparable, C any] struct {
+	bytesLimit uint64
+	mode       EvictionMode
+
+	mu         sync.RWMutex
+	totalBytes uint64
+	entries    map[K]*cacheEntry
+}
+
+// NewCache returns a new, empty Cache holding up to bytesLimit bytes of
+// content.
+func NewCache[K comparable, C any](bytesLimit uint64, mode EvictionMode) *Cache[K, C] {
+	return &Cache[K, C]{
+		bytesLimit: bytesLimit,
+		mode:       mode,
+		entries:    make(map[K]*cacheEntry),
+	}
+}
+
+// Serve handles requests until get is closed. Each request read from get is
+// sent to hit with the content, if it is in the cache, and otherwise to
+// miss. Content read from put is added to the cache, evicting other content
+// as needed, unless it is bigger than the cache.
+func (c *Cache[K, C]) Serve(
+	get <-chan struct {
+		Key K
+		Ctx C
+	},
+	put <-chan struct {
+		Key  K
+		Data []byte
+	},
+	hit chan<- struct {
+		Key  K
+		Ctx  C
+		Data []byte
+	},
+	miss chan<- struct {
+		Key K
+		Ctx C
+	},
+) {
+	for {
+		select {
+		case g, open := <-get:
+			if !open {
+				return
+			}
+			c.mu.RLock()
+			e, ok := c.entries[g.Key]
+			c.mu.RUnlock()
+			if !ok {
+				miss <- g
+				continue
+			}
+			e.Lock()
+			hit <- struct {
+				Key  K
+				Ctx  C
+				Data []byte
+			}{
+				Key:  g.Key,
+				Ctx:  g.Ctx,
+				Data: e.data,
+			}
+			e.last = time.Now()
+			e.Unlock()
+
+		case p, open := <-put:
+			if !open {
+				put = nil
+				continue
+			}
+			c.put(p.Key, p.Data)
+		}
+	}
+}
+
+// put adds content to the cache.
+func (c *Cache[K, C]) put(key K, data []byte) {
+	size := uint64(len(data))
+	if size > c.bytesLimit {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.entries[key]; ok {
+		// Replacing content frees the old content.
+		old.Lock()
+		c.totalBytes -= uint64(len(old.data))
+		old.Unlock()
+		delete(c.entries, key)
+	}
+	// TODO: Can improve eviction algorithm - this is simplistic but O(n^2)
+	for c.totalBytes+size > c.bytesLimit {
+		// Find something to evict.
+		var ek K
+		var ee *cacheEntry
+		et := time.Now()
+		if c.mode == EvictMRU {
+			et = time.Time{}
+		}
+		for k, e := range c.entries {
+			e.Lock()
+			if (c.mode == EvictLRU && e.last.Before(et)) || (c.mode == EvictMRU && e.last.After(et)) {
+				ee, et, ek = e, e.last, k
+			}
+			e.Unlock()
+		}
+		if ee == nil {
+			break
+		}
+		ee.Lock()
+		c.totalBytes -= uint64(len(ee.data))
+		ee.Unlock()
+		delete(c.entries, ek)
+	}
+	c.entries[key] = &cacheEntry{
+		data: data,
+		last: time.Now(),
+	}
+	c.totalBytes += size
+}
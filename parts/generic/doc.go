@@ -0,0 +1,23 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package generic implements standard Shenzhen Go parts as generic
+// functions. Graphs with the Generics option call these functions, with
+// the types inferred for each node, instead of having a copy of the part's
+// code specialised to the types. It requires Go 1.18 or later.
+//
+// The functions don't close their output channels, since a node with a
+// multiplicity greater than one runs the function in several goroutines.
+// Closing outputs is left to the caller.
+package generic
@@ -0,0 +1,48 @@
+//go:build go1.18
+
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generic
+
+import "reflect"
+
+// Gather sends every value read from any of the inputs to output, until all
+// the inputs are closed. Nil inputs are ignored. Like the Gather part, it
+// reads one value at a time, so it doesn't read from any input while it is
+// blocked sending to output.
+func Gather[T any](output chan<- T, inputs ...<-chan T) {
+	cases := make([]reflect.SelectCase, len(inputs))
+	open := 0
+	for i, input := range inputs {
+		cases[i].Dir = reflect.SelectRecv
+		if input != nil {
+			// A case with the zero Value for Chan is never chosen.
+			cases[i].Chan = reflect.ValueOf(input)
+			open++
+		}
+	}
+	for open > 0 {
+		i, in, ok := reflect.Select(cases)
+		if !ok {
+			cases[i].Chan = reflect.Value{}
+			open--
+			continue
+		}
+		// The assertion fails only for nil values of interface types,
+		// leaving v as nil.
+		v, _ := in.Interface().(T)
+		output <- v
+	}
+}
@@ -0,0 +1,242 @@
+//go:build go1.18
+
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generic
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// send returns a closed channel holding the values.
+func send[T any](vals ...T) <-chan T {
+	c := make(chan T, len(vals))
+	for _, v := range vals {
+		c <- v
+	}
+	close(c)
+	return c
+}
+
+// collect runs f with a channel, closes the channel after f returns, and
+// returns the values sent.
+func collect[T any](f func(chan<- T)) []T {
+	c := make(chan T)
+	go func() {
+		f(c)
+		close(c)
+	}()
+	var got []T
+	for v := range c {
+		got = append(got, v)
+	}
+	return got
+}
+
+func TestBroadcast(t *testing.T) {
+	o0, o1 := make(chan int, 3), make(chan int, 3)
+	Broadcast(send(1, 2, 3), o0, o1)
+	close(o0)
+	close(o1)
+	for i, o := range []chan int{o0, o1} {
+		var got []int
+		for v := range o {
+			got = append(got, v)
+		}
+		if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+			t.Errorf("output%d got %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestGather(t *testing.T) {
+	got := collect(func(out chan<- string) {
+		Gather(out, send("a", "b"), send("c"))
+	})
+	sort.Strings(got)
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Gather got %v, want %v", got, want)
+	}
+
+	// Nil inputs are ignored, and nil values of interface types are sent.
+	got2 := collect(func(out chan<- error) {
+		Gather(out, nil, send[error](nil))
+	})
+	if want := []error{nil}; !reflect.DeepEqual(got2, want) {
+		t.Errorf("Gather got %v, want %v", got2, want)
+	}
+}
+
+func TestGatherOneAtATime(t *testing.T) {
+	a, b, output := make(chan int), make(chan int), make(chan int)
+	done := make(chan struct{})
+	go func() {
+		Gather(output, a, b)
+		close(done)
+	}()
+	a <- 1
+	// Gather is now blocked sending 1 to output, so it shouldn't read b.
+	select {
+	case b <- 2:
+		t.Fatal("Gather read from b while blocked sending to output")
+	case <-time.After(10 * time.Millisecond):
+	}
+	if got := <-output; got != 1 {
+		t.Errorf("Gather sent %d, want 1", got)
+	}
+	close(a)
+	go func() {
+		b <- 3
+		close(b)
+	}()
+	if got := <-output; got != 3 {
+		t.Errorf("Gather sent %d, want 3", got)
+	}
+	<-done
+}
+
+func TestKeyCounter(t *testing.T) {
+	result := make(chan map[string]uint, 1)
+	got := collect(func(out chan<- string) {
+		KeyCounter(send("a", "b", "a"), out, result)
+	})
+	if want := []string{"a", "b", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("KeyCounter output got %v, want %v", got, want)
+	}
+	if got, want := <-result, map[string]uint{"a": 2, "b": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("KeyCounter result = %v, want %v", got, want)
+	}
+
+	// The output is optional.
+	KeyCounter(send(1), nil, make(chan map[int]uint, 1))
+}
+
+// runQueue sends the values to a Queue one at a time, and only then reads
+// the output, so the order of the output is predictable.
+func runQueue(maxItems int, mode QueueMode, vals ...int) (got, dropped []int) {
+	input, output, drop := make(chan int), make(chan int), make(chan int, len(vals))
+	go func() {
+		Queue(input, output, drop, maxItems, mode)
+		close(output)
+		close(drop)
+	}()
+	for _, v := range vals {
+		input <- v
+	}
+	close(input)
+	for v := range output {
+		got = append(got, v)
+	}
+	for v := range drop {
+		dropped = append(dropped, v)
+	}
+	return got, dropped
+}
+
+func TestQueue(t *testing.T) {
+	tests := []struct {
+		maxItems          int
+		mode              QueueMode
+		wantOut, wantDrop []int
+	}{
+		{maxItems: 10, mode: FIFO, wantOut: []int{1, 2, 3, 4}},
+		{maxItems: 10, mode: LIFO, wantOut: []int{4, 3, 2, 1}},
+		{maxItems: 2, mode: FIFO, wantOut: []int{3, 4}, wantDrop: []int{1, 2}},
+		{maxItems: 2, mode: LIFO, wantOut: []int{4, 3}, wantDrop: []int{1, 2}},
+	}
+	for _, test := range tests {
+		got, dropped := runQueue(test.maxItems, test.mode, 1, 2, 3, 4)
+		if !reflect.DeepEqual(got, test.wantOut) || !reflect.DeepEqual(dropped, test.wantDrop) {
+			t.Errorf("Queue(maxItems = %d, mode = %d) sent %v, dropped %v; want %v, %v", test.maxItems, test.mode, got, dropped, test.wantOut, test.wantDrop)
+		}
+	}
+}
+
+func TestUnbatch(t *testing.T) {
+	got := collect(func(out chan<- int) {
+		Unbatch(send([]int{1, 2}, nil, []int{3}), out)
+	})
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unbatch got %v, want %v", got, want)
+	}
+}
+
+func TestZip(t *testing.T) {
+	type pair = struct {
+		Field0 int
+		Field1 string
+	}
+	zip := func(untilFirstClose bool) []pair {
+		in0, in1 := send(1, 2), send("a")
+		return collect(func(out chan<- pair) {
+			var v pair
+			Zip(out, untilFirstClose, &v, Field(in0, &v.Field0), Field(in1, &v.Field1))
+		})
+	}
+	if got, want := zip(false), []pair{{1, "a"}, {2, ""}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Zip(untilFirstClose = false) got %v, want %v", got, want)
+	}
+	if got, want := zip(true), []pair{{1, "a"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Zip(untilFirstClose = true) got %v, want %v", got, want)
+	}
+}
+
+func TestCache(t *testing.T) {
+	type (
+		getT = struct {
+			Key string
+			Ctx int
+		}
+		hitT = struct {
+			Key  string
+			Ctx  int
+			Data []byte
+		}
+	)
+	c := NewCache[string, int](4, EvictLRU)
+	c.put("a", []byte("aa"))
+	c.put("b", []byte("bb"))
+	c.put("c", []byte("cc"))        // evicts a
+	c.put("huge", []byte("toobig")) // not cached
+	get, hit, miss := make(chan getT), make(chan hitT, 4), make(chan getT, 4)
+	done := make(chan struct{})
+	go func() {
+		c.Serve(get, nil, hit, miss)
+		close(done)
+	}()
+	for i, k := range []string{"a", "b", "c", "huge"} {
+		get <- getT{k, i}
+	}
+	close(get)
+	<-done
+	close(hit)
+	close(miss)
+	var hits, misses []string
+	for h := range hit {
+		hits = append(hits, h.Key+"="+string(h.Data))
+	}
+	for m := range miss {
+		misses = append(misses, m.Key)
+	}
+	if want := []string{"b=bb", "c=cc"}; !reflect.DeepEqual(hits, want) {
+		t.Errorf("Cache hits = %v, want %v", hits, want)
+	}
+	if want := []string{"a", "huge"}; !reflect.DeepEqual(misses, want) {
+		t.Errorf("Cache misses = %v, want %v", misses, want)
+	}
+}
@@ -0,0 +1,31 @@
+//go:build go1.18
+
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generic
+
+// KeyCounter counts how many times each value is read from input, and sends
+// the counts to result once input is closed. If output is not nil, each
+// value is also passed on to output.
+func KeyCounter[K comparable](input <-chan K, output chan<- K, result chan<- map[K]uint) {
+	m := make(map[K]uint)
+	for in := range input {
+		m[in]++
+		if output != nil {
+			output <- in
+		}
+	}
+	result <- m
+}
@@ -0,0 +1,75 @@
+//go:build go1.18
+
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generic
+
+// QueueMode chooses which value a Queue sends next.
+type QueueMode int
+
+// Queue modes.
+const (
+	FIFO QueueMode = iota // Send the least recently read value.
+	LIFO                  // Send the most recently read value.
+)
+
+// Queue reads values from input and sends them to output as soon as
+// possible, storing up to maxItems in the meantime. When reading a value
+// puts the queue over maxItems, the least recently read value is sent to
+// drop, if that wouldn't block, and removed from the queue. Queue returns
+// once input is closed and the queue is empty.
+func Queue[T any](input <-chan T, output, drop chan<- T, maxItems int, mode QueueMode) {
+	queue := make([]T, 0, maxItems)
+	for {
+		if len(queue) == 0 {
+			if input == nil {
+				break
+			}
+			in, open := <-input
+			if !open {
+				break
+			}
+			queue = append(queue, in)
+		}
+		idx := 0
+		if mode == LIFO {
+			idx = len(queue) - 1
+		}
+		out := queue[idx]
+		select {
+		case in, open := <-input:
+			if !open {
+				input = nil
+				break // select
+			}
+			queue = append(queue, in)
+			if len(queue) <= maxItems {
+				break // select
+			}
+			// Drop least-recently read item, but don't block.
+			select {
+			case drop <- queue[0]:
+			default:
+			}
+			queue = queue[1:]
+		case output <- out:
+			if mode == LIFO {
+				queue = queue[:idx]
+			} else {
+				queue = queue[1:]
+			}
+		}
+	}
+}
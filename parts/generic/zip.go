@@ -0,0 +1,55 @@
+//go:build go1.18
+
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generic
+
+// ZipReceiver receives a value for one field of a zipped value, and reports
+// whether its input is still open.
+type ZipReceiver func() (open bool)
+
+// Field returns a ZipReceiver that receives values from input into *field.
+func Field[T any](input <-chan T, field *T) ZipReceiver {
+	return func() bool {
+		in, open := <-input
+		*field = in
+		return open
+	}
+}
+
+// Zip reads one value from each input into the fields of *v, and sends *v
+// to output. The inputs are given by the fields, which are usually made with
+// Field. Zip returns once all the inputs are closed. If untilFirstClose is
+// true, values are only sent while all the inputs are open; otherwise
+// closed inputs leave their fields as zero values.
+func Zip[T any](output chan<- T, untilFirstClose bool, v *T, fields ...ZipReceiver) {
+	var zero T
+	for {
+		*v = zero
+		allClosed, send := true, true
+		for _, recv := range fields {
+			open := recv()
+			allClosed = allClosed && !open
+			send = send && open
+		}
+		if allClosed {
+			break
+		}
+		if untilFirstClose && !send {
+			continue
+		}
+		output <- *v
+	}
+}
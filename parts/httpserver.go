@@ -38,6 +38,7 @@ var httpServerPins = pin.NewMap(
 		Name:      "errors",
 		Direction: pin.Output,
 		Type:      "error",
+		Optional:  true,
 	},
 )
 
@@ -31,15 +31,21 @@ var (
 )
 
 func setupAce(id, mode string, handler func(dom.Object)) *dom.AceSession {
+	return setupAceEditor(id, mode, handler).Session()
+}
+
+// setupAceEditor is like setupAce, but returns the editor.
+func setupAceEditor(id, mode string, handler func(dom.Object)) *dom.AceEditor {
 	e := ace.Edit(id)
 	if e == nil {
 		log.Fatalf("Couldn't ace.edit(%q)", id)
 	}
 	e.SetTheme("ace/theme/" + aceTheme)
-	return e.Session().
+	e.Session().
 		SetMode(mode).
 		SetUseSoftTabs(false).
 		On("change", handler)
+	return e
 }
 
 func formatHandler(session *dom.AceSession) func(dom.Object) {
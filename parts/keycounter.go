@@ -27,12 +27,13 @@ var keyCounterPins = pin.NewMap(
 	&pin.Definition{
 		Name:      "input",
 		Direction: pin.Input,
-		Type:      keyCounterTypeParam,
+		Type:      keyCounterTypeParam + ":comparable", // keys of the map
 	},
 	&pin.Definition{
 		Name:      "output",
 		Direction: pin.Output,
 		Type:      keyCounterTypeParam,
+		Optional:  true,
 	},
 	&pin.Definition{
 		Name:      "result",
@@ -89,6 +90,14 @@ func (KeyCounter) Impl(n *model.Node) model.PartImpl {
 	}
 }
 
+// GenericImpl returns an implementation calling generic.KeyCounter.
+func (k KeyCounter) GenericImpl(n *model.Node) model.PartImpl {
+	impl := k.Impl(n)
+	impl.Imports = append(impl.Imports, genericImport)
+	impl.Body = "generic.KeyCounter(input, output, result)"
+	return impl
+}
+
 // Pins returns a map declaring an input/output pair of the same type,
 // and a result output with map type.
 func (KeyCounter) Pins() pin.Map { return keyCounterPins }
@@ -15,7 +15,15 @@
 // Package parts contains various pre-made bits and pieces to combine into the graph.
 package parts
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/google/shenzhen-go/model"
+)
+
+// genericImport is the import line used by GenericImpl methods.
+var genericImport = strconv.Quote(model.GenericPackagePath)
 
 func stripCR(in []string) []string {
 	for i := range in {
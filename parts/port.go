@@ -0,0 +1,111 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parts
+
+import (
+	"github.com/google/shenzhen-go/model"
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+var (
+	inputPortPins = pin.NewMap(&pin.Definition{
+		Name:      "output",
+		Direction: pin.Output,
+		Type:      "$Any",
+	})
+
+	outputPortPins = pin.NewMap(&pin.Definition{
+		Name:      "input",
+		Direction: pin.Input,
+		Type:      "$Any",
+	})
+)
+
+func init() {
+	model.RegisterPartType("InputPort", "Ports", &model.PartType{
+		New: func() model.Part { return &InputPort{} },
+		Panels: []model.PartPanel{{
+			Name: "Help",
+			Editor: `<div>
+			<p>
+				An InputPort part lets Go code using a library graph send
+				values into the graph. The connected channel becomes a 
+				receive-only channel parameter of the generated Run function,
+				named after the node. The type is inferred from the pins 
+				the channel is connected to.
+			</p>
+			<p>
+				Ports are ignored when the graph is a command.
+			</p>
+			</div>`,
+		}},
+	})
+	model.RegisterPartType("OutputPort", "Ports", &model.PartType{
+		New: func() model.Part { return &OutputPort{} },
+		Panels: []model.PartPanel{{
+			Name: "Help",
+			Editor: `<div>
+			<p>
+				An OutputPort part lets Go code using a library graph receive
+				values from the graph. The connected channel becomes a 
+				send-only channel parameter of the generated Run function,
+				named after the node. The type is inferred from the pins 
+				the channel is connected to.
+			</p>
+			<p>
+				Ports are ignored when the graph is a command.
+			</p>
+			</div>`,
+		}},
+	})
+}
+
+// InputPort is a part representing a channel supplied to Run, which the
+// graph reads from.
+type InputPort struct{}
+
+// Clone returns a clone of this InputPort.
+func (InputPort) Clone() model.Part { return &InputPort{} }
+
+// Impl returns an empty implementation; ports do not run as goroutines.
+func (InputPort) Impl(*model.Node) model.PartImpl { return model.PartImpl{} }
+
+// Pins returns a map declaring a single output of any type.
+func (InputPort) Pins() pin.Map { return inputPortPins }
+
+// PortPin returns "output".
+func (InputPort) PortPin() string { return "output" }
+
+// TypeKey returns "InputPort".
+func (InputPort) TypeKey() string { return "InputPort" }
+
+// OutputPort is a part representing a channel supplied to Run, which the
+// graph writes to.
+type OutputPort struct{}
+
+// Clone returns a clone of this OutputPort.
+func (OutputPort) Clone() model.Part { return &OutputPort{} }
+
+// Impl returns an empty implementation; ports do not run as goroutines.
+func (OutputPort) Impl(*model.Node) model.PartImpl { return model.PartImpl{} }
+
+// Pins returns a map declaring a single input of any type.
+func (OutputPort) Pins() pin.Map { return outputPortPins }
+
+// PortPin returns "input".
+func (OutputPort) PortPin() string { return "input" }
+
+// TypeKey returns "OutputPort".
+func (OutputPort) TypeKey() string { return "OutputPort" }
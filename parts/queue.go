@@ -38,6 +38,7 @@ var queuePins = pin.NewMap(
 		Name:      "drop",
 		Direction: pin.Output,
 		Type:      queueTypeParam,
+		Optional:  true,
 	},
 )
 
@@ -173,6 +174,18 @@ func (q *Queue) Impl(n *model.Node) model.PartImpl {
 	}
 }
 
+// GenericImpl returns an implementation calling generic.Queue.
+func (q *Queue) GenericImpl(n *model.Node) model.PartImpl {
+	mode := "generic.FIFO"
+	if q.Mode == QueueModeLIFO {
+		mode = "generic.LIFO"
+	}
+	impl := q.Impl(n)
+	impl.Imports = append(impl.Imports, genericImport)
+	impl.Body = fmt.Sprintf("generic.Queue(input, output, drop, maxItems, %s)", mode)
+	return impl
+}
+
 // Pins returns a map declaring an input and two outputs of the same arbitrary type.
 func (q *Queue) Pins() pin.Map { return queuePins }
 
@@ -0,0 +1,171 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/google/shenzhen-go/model"
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+func init() {
+	model.RegisterPartType("Subgraph", "General", &model.PartType{
+		New: func() model.Part { return &Subgraph{PinMap: pin.Map{}} },
+		Panels: []model.PartPanel{
+			{
+				Name: "Subgraph",
+				Editor: `<div class="form"><div class="formfield">
+					<label>Graph file: <input id="subgraph-file" type="text"></input></label>
+				</div></div>`,
+			},
+			{
+				Name: "Help",
+				Editor: `<div>
+			<p>
+				A Subgraph part runs another graph as part of this one. The 
+				graph file is relative to the directory containing this graph.
+			</p><p>
+				The other graph must be a library (not a command). Each of its
+				ports becomes a pin of this node: an InputPort becomes an input,
+				and an OutputPort becomes an output. The node calls the Run 
+				function of the other graph's generated package, which is 
+				imported using the other graph's package path. Both graphs must
+				agree on whether Run takes a context. If the other graph has
+				parameters, Run is given the defaults from its DefaultConfig.
+			</p><p>
+				The pins are updated when the graph is checked or generated,
+				or when it is next loaded.
+			</p>
+			</div>`,
+			},
+		},
+	})
+}
+
+// Subgraph is a part that runs another graph by calling its Run function.
+// The details of the other graph needed to generate code are copied into
+// the part when it is linked, so that the part can be used without
+// reloading the other graph.
+type Subgraph struct {
+	File          string  `json:"file"`
+	PackagePath   string  `json:"package_path,omitempty"`
+	UseContext    bool    `json:"use_context,omitempty"`
+	HasParameters bool    `json:"has_parameters,omitempty"`
+	PinMap        pin.Map `json:"pins"`
+
+	graph *model.Graph
+}
+
+// Clone returns a copy of this Subgraph part.
+func (s *Subgraph) Clone() model.Part {
+	pins := make(pin.Map, len(s.PinMap))
+	for k, v := range s.PinMap {
+		p := *v
+		pins[k] = &p
+	}
+	return &Subgraph{
+		File:          s.File,
+		PackagePath:   s.PackagePath,
+		UseContext:    s.UseContext,
+		HasParameters: s.HasParameters,
+		PinMap:        pins,
+		graph:         s.graph,
+	}
+}
+
+// Link loads the other graph, and updates the pins and other details.
+func (s *Subgraph) Link(dir string, load func(string) (*model.Graph, error)) error {
+	if s.File == "" {
+		return errors.New("no graph file set")
+	}
+	path := filepath.FromSlash(s.File)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	g, err := load(path)
+	if err != nil {
+		return err
+	}
+	if err := g.InferTypes(); err != nil {
+		return fmt.Errorf("%s: %v", s.File, err)
+	}
+	pins := make(pin.Map)
+	for _, p := range g.Ports() {
+		pins[p.Identifier()] = &pin.Definition{
+			Name:      p.Identifier(),
+			Type:      p.ElemType(),
+			Direction: p.Direction(),
+		}
+	}
+	s.PackagePath = g.PackagePath
+	s.UseContext = g.UseContext
+	s.HasParameters = len(g.Parameters) > 0
+	s.PinMap = pins
+	s.graph = g
+	return nil
+}
+
+// Subgraph returns the graph loaded by Link.
+func (s *Subgraph) Subgraph() *model.Graph { return s.graph }
+
+// packageName returns an identifier to import the package as.
+func (s *Subgraph) packageName() string {
+	return model.Mangle((&model.Graph{PackagePath: s.PackagePath}).PackageName())
+}
+
+// Impl returns a call to Run in the other graph's package, passing the
+// default config if the other graph has parameters.
+func (s *Subgraph) Impl(*model.Node) model.PartImpl {
+	if s.PackagePath == "" {
+		return model.PartImpl{
+			Body: fmt.Sprintf("panic(%q)", "subgraph "+s.File+" was never linked"),
+		}
+	}
+	names := make([]string, 0, len(s.PinMap))
+	for pn := range s.PinMap {
+		names = append(names, pn)
+	}
+	sort.Strings(names)
+
+	pkg := s.packageName()
+	buf := new(bytes.Buffer)
+	if s.UseContext {
+		fmt.Fprintf(buf, "return %s.Run(ctx, ", pkg)
+	} else {
+		fmt.Fprintf(buf, "%s.Run(", pkg)
+	}
+	if s.HasParameters {
+		fmt.Fprintf(buf, "%s.DefaultConfig(), ", pkg)
+	}
+	for _, pn := range names {
+		fmt.Fprintf(buf, "%s, ", pn)
+	}
+	buf.WriteString(")")
+	return model.PartImpl{
+		Imports: []string{fmt.Sprintf("%s %q", pkg, s.PackagePath)},
+		Body:    buf.String(),
+	}
+}
+
+// Pins returns the pins copied from the other graph's ports.
+func (s *Subgraph) Pins() pin.Map { return s.PinMap }
+
+// TypeKey returns "Subgraph".
+func (*Subgraph) TypeKey() string { return "Subgraph" }
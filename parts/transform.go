@@ -15,6 +15,7 @@
 package parts
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -36,11 +37,11 @@ func init() {
 				Editor: `<div class="form">
 					<div class="formfield">
 						<label for="transform-inputtype">Input type</label>
-						<input id="transform-inputtype" name="transform-inputtype" type="text"></input>
+						<input id="transform-inputtype" name="transform-inputtype" type="text" title="A Go type, which may use type parameters such as $T. A parameter can be followed by a constraint: any, comparable, integer, float, ordered, or an interface, e.g. map[$K:comparable]$V."></input>
 					</div>
 					<div class="formfield">
 						<label for="transform-outputtype">Output type</label>
-						<input id="transform-outputtype" name="transform-outputtype" type="text"></input>
+						<input id="transform-outputtype" name="transform-outputtype" type="text" title="A Go type, which may use type parameters such as $T. A parameter can be followed by a constraint: any, comparable, integer, float, ordered, or an interface, e.g. map[$K:comparable]$V."></input>
 					</div>
 				</div>`,
 			},
@@ -68,6 +69,18 @@ func init() {
 			</div>`,
 			},
 		},
+		Migrations: map[int]model.PartMigration{
+			// Transforms used to have no types, and worked with any types.
+			0: func(part map[string]interface{}) error {
+				if t, _ := part["input_type"].(string); t == "" {
+					part["input_type"] = "$AnyIn"
+				}
+				if t, _ := part["output_type"].(string); t == "" {
+					part["output_type"] = "$AnyOut"
+				}
+				return nil
+			},
+		},
 	})
 }
 
@@ -84,6 +97,15 @@ func (t *Transform) Clone() model.Part {
 	return &Transform{Body: t.Body}
 }
 
+// MarshalJSON encodes the Transform, with canonical lines of code.
+func (t *Transform) MarshalJSON() ([]byte, error) {
+	type plainTransform Transform // without the MarshalJSON method
+	pt := plainTransform(*t)
+	pt.Imports = model.CodeLines(t.Imports)
+	pt.Body = model.CodeLines(t.Body)
+	return json.Marshal(&pt)
+}
+
 // Impl returns the Transform implementation.
 func (t *Transform) Impl(n *model.Node) model.PartImpl {
 	return model.PartImpl{
@@ -109,6 +131,7 @@ func (t *Transform) Pins() pin.Map {
 			Name:      "outputs",
 			Direction: pin.Output,
 			Type:      t.OutputType,
+			Optional:  true,
 		},
 	)
 }
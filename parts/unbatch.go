@@ -69,6 +69,14 @@ func (Unbatch) Impl(*model.Node) model.PartImpl {
 	}
 }
 
+// GenericImpl returns an implementation calling generic.Unbatch.
+func (u Unbatch) GenericImpl(n *model.Node) model.PartImpl {
+	impl := u.Impl(n)
+	impl.Imports = append(impl.Imports, genericImport)
+	impl.Body = "generic.Unbatch(input, output)"
+	return impl
+}
+
 // Pins returns a map declaring a single input of any slice type
 // and a single output of the slice element type.
 func (Unbatch) Pins() pin.Map { return unbatchPins }
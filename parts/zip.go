@@ -132,12 +132,32 @@ func (z Zip) Impl(n *model.Node) model.PartImpl {
 	}
 }
 
+// GenericImpl returns an implementation calling generic.Zip.
+func (z Zip) GenericImpl(n *model.Node) model.PartImpl {
+	impl := z.Impl(n)
+	if n.Connections["output"] == "nil" {
+		return impl
+	}
+	args := []string{"output", fmt.Sprint(z.FinishMode == ZipUntilFirstClose), "&v"}
+	for i := uint(0); i < z.InputNum; i++ {
+		input := fmt.Sprintf("input%d", i)
+		if n.Connections[input] == "nil" {
+			continue
+		}
+		args = append(args, fmt.Sprintf("generic.Field(%s, &v.Field%d)", input, i))
+	}
+	impl.Imports = append(impl.Imports, genericImport)
+	impl.Body = fmt.Sprintf("var v %s\ngeneric.Zip(%s)", z.outputType(n.TypeParams), strings.Join(args, ", "))
+	return impl
+}
+
 // Pins returns a map with N inputs and 1 output.
 func (z Zip) Pins() pin.Map {
 	m := pin.NewMap(&pin.Definition{
 		Name:      "output",
 		Direction: pin.Output,
 		Type:      z.outputType(nil),
+		Optional:  true,
 	})
 	for i := uint(0); i < z.InputNum; i++ {
 		name := fmt.Sprintf("input%d", i)
@@ -145,6 +165,7 @@ func (z Zip) Pins() pin.Map {
 			Name:      name,
 			Direction: pin.Input,
 			Type:      fmt.Sprintf("$T%d", i),
+			Optional:  true,
 		}
 	}
 	return m
@@ -31,6 +31,7 @@ const (
 	ActionRequest_GENERATE ActionRequest_Action = 2
 	ActionRequest_BUILD    ActionRequest_Action = 3
 	ActionRequest_INSTALL  ActionRequest_Action = 4
+	ActionRequest_CHECK    ActionRequest_Action = 5
 )
 
 var ActionRequest_Action_name = map[int32]string{
@@ -39,6 +40,7 @@ var ActionRequest_Action_name = map[int32]string{
 	2: "GENERATE",
 	3: "BUILD",
 	4: "INSTALL",
+	5: "CHECK",
 }
 var ActionRequest_Action_value = map[string]int32{
 	"SAVE":     0,
@@ -46,13 +48,14 @@ var ActionRequest_Action_value = map[string]int32{
 	"GENERATE": 2,
 	"BUILD":    3,
 	"INSTALL":  4,
+	"CHECK":    5,
 }
 
 func (x ActionRequest_Action) String() string {
 	return proto.EnumName(ActionRequest_Action_name, int32(x))
 }
 func (ActionRequest_Action) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{4, 0}
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{4, 0}
 }
 
 type Empty struct {
@@ -65,7 +68,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{0}
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{0}
 }
 func (m *Empty) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Empty.Unmarshal(m, b)
@@ -97,7 +100,7 @@ func (m *NodePin) Reset()         { *m = NodePin{} }
 func (m *NodePin) String() string { return proto.CompactTextString(m) }
 func (*NodePin) ProtoMessage()    {}
 func (*NodePin) Descriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{1}
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{1}
 }
 func (m *NodePin) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodePin.Unmarshal(m, b)
@@ -144,7 +147,7 @@ func (m *ChannelConfig) Reset()         { *m = ChannelConfig{} }
 func (m *ChannelConfig) String() string { return proto.CompactTextString(m) }
 func (*ChannelConfig) ProtoMessage()    {}
 func (*ChannelConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{2}
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{2}
 }
 func (m *ChannelConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelConfig.Unmarshal(m, b)
@@ -195,6 +198,11 @@ type NodeConfig struct {
 	PartType             string   `protobuf:"bytes,7,opt,name=part_type,json=partType,proto3" json:"part_type,omitempty"`
 	X                    float64  `protobuf:"fixed64,8,opt,name=x,proto3" json:"x,omitempty"`
 	Y                    float64  `protobuf:"fixed64,9,opt,name=y,proto3" json:"y,omitempty"`
+	Recover              bool     `protobuf:"varint,10,opt,name=recover,proto3" json:"recover,omitempty"`
+	Restart              string   `protobuf:"bytes,11,opt,name=restart,proto3" json:"restart,omitempty"`
+	MaxRestarts          uint32   `protobuf:"varint,12,opt,name=max_restarts,json=maxRestarts,proto3" json:"max_restarts,omitempty"`
+	RestartBackoff       string   `protobuf:"bytes,13,opt,name=restart_backoff,json=restartBackoff,proto3" json:"restart_backoff,omitempty"`
+	RestartMetrics       bool     `protobuf:"varint,14,opt,name=restart_metrics,json=restartMetrics,proto3" json:"restart_metrics,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -204,7 +212,7 @@ func (m *NodeConfig) Reset()         { *m = NodeConfig{} }
 func (m *NodeConfig) String() string { return proto.CompactTextString(m) }
 func (*NodeConfig) ProtoMessage()    {}
 func (*NodeConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{3}
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{3}
 }
 func (m *NodeConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeConfig.Unmarshal(m, b)
@@ -287,6 +295,41 @@ func (m *NodeConfig) GetY() float64 {
 	return 0
 }
 
+func (m *NodeConfig) GetRecover() bool {
+	if m != nil {
+		return m.Recover
+	}
+	return false
+}
+
+func (m *NodeConfig) GetRestart() string {
+	if m != nil {
+		return m.Restart
+	}
+	return ""
+}
+
+func (m *NodeConfig) GetMaxRestarts() uint32 {
+	if m != nil {
+		return m.MaxRestarts
+	}
+	return 0
+}
+
+func (m *NodeConfig) GetRestartBackoff() string {
+	if m != nil {
+		return m.RestartBackoff
+	}
+	return ""
+}
+
+func (m *NodeConfig) GetRestartMetrics() bool {
+	if m != nil {
+		return m.RestartMetrics
+	}
+	return false
+}
+
 type ActionRequest struct {
 	Graph                string               `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
 	Action               ActionRequest_Action `protobuf:"varint,2,opt,name=action,proto3,enum=proto.ActionRequest_Action" json:"action,omitempty"`
@@ -299,7 +342,7 @@ func (m *ActionRequest) Reset()         { *m = ActionRequest{} }
 func (m *ActionRequest) String() string { return proto.CompactTextString(m) }
 func (*ActionRequest) ProtoMessage()    {}
 func (*ActionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{4}
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{4}
 }
 func (m *ActionRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ActionRequest.Unmarshal(m, b)
@@ -334,17 +377,18 @@ func (m *ActionRequest) GetAction() ActionRequest_Action {
 }
 
 type ActionResponse struct {
-	Output               string   `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Output               string          `protobuf:"bytes,1,opt,name=output,proto3" json:"output,omitempty"`
+	CompileErrors        []*CompileError `protobuf:"bytes,2,rep,name=compile_errors,json=compileErrors,proto3" json:"compile_errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *ActionResponse) Reset()         { *m = ActionResponse{} }
 func (m *ActionResponse) String() string { return proto.CompactTextString(m) }
 func (*ActionResponse) ProtoMessage()    {}
 func (*ActionResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{5}
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{5}
 }
 func (m *ActionResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ActionResponse.Unmarshal(m, b)
@@ -371,6 +415,13 @@ func (m *ActionResponse) GetOutput() string {
 	return ""
 }
 
+func (m *ActionResponse) GetCompileErrors() []*CompileError {
+	if m != nil {
+		return m.CompileErrors
+	}
+	return nil
+}
+
 type Input struct {
 	Graph                string   `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
 	In                   string   `protobuf:"bytes,2,opt,name=in,proto3" json:"in,omitempty"`
@@ -383,7 +434,7 @@ func (m *Input) Reset()         { *m = Input{} }
 func (m *Input) String() string { return proto.CompactTextString(m) }
 func (*Input) ProtoMessage()    {}
 func (*Input) Descriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{6}
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{6}
 }
 func (m *Input) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Input.Unmarshal(m, b)
@@ -429,7 +480,7 @@ func (m *Output) Reset()         { *m = Output{} }
 func (m *Output) String() string { return proto.CompactTextString(m) }
 func (*Output) ProtoMessage()    {}
 func (*Output) Descriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{7}
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{7}
 }
 func (m *Output) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Output.Unmarshal(m, b)
@@ -467,6 +518,7 @@ type SetChannelRequest struct {
 	Graph                string         `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
 	Channel              string         `protobuf:"bytes,2,opt,name=channel,proto3" json:"channel,omitempty"`
 	Config               *ChannelConfig `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	Version              uint64         `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
 	XXX_sizecache        int32          `json:"-"`
@@ -476,7 +528,7 @@ func (m *SetChannelRequest) Reset()         { *m = SetChannelRequest{} }
 func (m *SetChannelRequest) String() string { return proto.CompactTextString(m) }
 func (*SetChannelRequest) ProtoMessage()    {}
 func (*SetChannelRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{8}
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{8}
 }
 func (m *SetChannelRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetChannelRequest.Unmarshal(m, b)
@@ -517,11 +569,23 @@ func (m *SetChannelRequest) GetConfig() *ChannelConfig {
 	return nil
 }
 
+func (m *SetChannelRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 type SetGraphPropertiesRequest struct {
 	Graph                string   `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
 	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	PackagePath          string   `protobuf:"bytes,3,opt,name=package_path,json=packagePath,proto3" json:"package_path,omitempty"`
 	IsCommand            bool     `protobuf:"varint,4,opt,name=is_command,json=isCommand,proto3" json:"is_command,omitempty"`
+	OutputDir            string   `protobuf:"bytes,5,opt,name=output_dir,json=outputDir,proto3" json:"output_dir,omitempty"`
+	UseContext           bool     `protobuf:"varint,6,opt,name=use_context,json=useContext,proto3" json:"use_context,omitempty"`
+	Telemetry            bool     `protobuf:"varint,7,opt,name=telemetry,proto3" json:"telemetry,omitempty"`
+	Version              uint64   `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	Generics             bool     `protobuf:"varint,9,opt,name=generics,proto3" json:"generics,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -531,7 +595,7 @@ func (m *SetGraphPropertiesRequest) Reset()         { *m = SetGraphPropertiesReq
 func (m *SetGraphPropertiesRequest) String() string { return proto.CompactTextString(m) }
 func (*SetGraphPropertiesRequest) ProtoMessage()    {}
 func (*SetGraphPropertiesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{9}
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{9}
 }
 func (m *SetGraphPropertiesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetGraphPropertiesRequest.Unmarshal(m, b)
@@ -539,158 +603,1192 @@ func (m *SetGraphPropertiesRequest) XXX_Unmarshal(b []byte) error {
 func (m *SetGraphPropertiesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_SetGraphPropertiesRequest.Marshal(b, m, deterministic)
 }
-func (dst *SetGraphPropertiesRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetGraphPropertiesRequest.Merge(dst, src)
+func (dst *SetGraphPropertiesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetGraphPropertiesRequest.Merge(dst, src)
+}
+func (m *SetGraphPropertiesRequest) XXX_Size() int {
+	return xxx_messageInfo_SetGraphPropertiesRequest.Size(m)
+}
+func (m *SetGraphPropertiesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetGraphPropertiesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetGraphPropertiesRequest proto.InternalMessageInfo
+
+func (m *SetGraphPropertiesRequest) GetGraph() string {
+	if m != nil {
+		return m.Graph
+	}
+	return ""
+}
+
+func (m *SetGraphPropertiesRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SetGraphPropertiesRequest) GetPackagePath() string {
+	if m != nil {
+		return m.PackagePath
+	}
+	return ""
+}
+
+func (m *SetGraphPropertiesRequest) GetIsCommand() bool {
+	if m != nil {
+		return m.IsCommand
+	}
+	return false
+}
+
+func (m *SetGraphPropertiesRequest) GetOutputDir() string {
+	if m != nil {
+		return m.OutputDir
+	}
+	return ""
+}
+
+func (m *SetGraphPropertiesRequest) GetUseContext() bool {
+	if m != nil {
+		return m.UseContext
+	}
+	return false
+}
+
+func (m *SetGraphPropertiesRequest) GetTelemetry() bool {
+	if m != nil {
+		return m.Telemetry
+	}
+	return false
+}
+
+func (m *SetGraphPropertiesRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *SetGraphPropertiesRequest) GetGenerics() bool {
+	if m != nil {
+		return m.Generics
+	}
+	return false
+}
+
+type Mutation struct {
+	// Types that are valid to be assigned to Mutation:
+	//	*Mutation_SetChannel
+	//	*Mutation_SetGraphProperties
+	//	*Mutation_SetNode
+	//	*Mutation_SetPosition
+	Mutation             isMutation_Mutation `protobuf_oneof:"mutation"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *Mutation) Reset()         { *m = Mutation{} }
+func (m *Mutation) String() string { return proto.CompactTextString(m) }
+func (*Mutation) ProtoMessage()    {}
+func (*Mutation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{10}
+}
+func (m *Mutation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Mutation.Unmarshal(m, b)
+}
+func (m *Mutation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Mutation.Marshal(b, m, deterministic)
+}
+func (dst *Mutation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Mutation.Merge(dst, src)
+}
+func (m *Mutation) XXX_Size() int {
+	return xxx_messageInfo_Mutation.Size(m)
+}
+func (m *Mutation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Mutation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Mutation proto.InternalMessageInfo
+
+type isMutation_Mutation interface {
+	isMutation_Mutation()
+}
+
+type Mutation_SetChannel struct {
+	SetChannel *SetChannelRequest `protobuf:"bytes,1,opt,name=set_channel,json=setChannel,proto3,oneof"`
+}
+
+type Mutation_SetGraphProperties struct {
+	SetGraphProperties *SetGraphPropertiesRequest `protobuf:"bytes,2,opt,name=set_graph_properties,json=setGraphProperties,proto3,oneof"`
+}
+
+type Mutation_SetNode struct {
+	SetNode *SetNodeRequest `protobuf:"bytes,3,opt,name=set_node,json=setNode,proto3,oneof"`
+}
+
+type Mutation_SetPosition struct {
+	SetPosition *SetPositionRequest `protobuf:"bytes,4,opt,name=set_position,json=setPosition,proto3,oneof"`
+}
+
+func (*Mutation_SetChannel) isMutation_Mutation() {}
+
+func (*Mutation_SetGraphProperties) isMutation_Mutation() {}
+
+func (*Mutation_SetNode) isMutation_Mutation() {}
+
+func (*Mutation_SetPosition) isMutation_Mutation() {}
+
+func (m *Mutation) GetMutation() isMutation_Mutation {
+	if m != nil {
+		return m.Mutation
+	}
+	return nil
+}
+
+func (m *Mutation) GetSetChannel() *SetChannelRequest {
+	if x, ok := m.GetMutation().(*Mutation_SetChannel); ok {
+		return x.SetChannel
+	}
+	return nil
+}
+
+func (m *Mutation) GetSetGraphProperties() *SetGraphPropertiesRequest {
+	if x, ok := m.GetMutation().(*Mutation_SetGraphProperties); ok {
+		return x.SetGraphProperties
+	}
+	return nil
+}
+
+func (m *Mutation) GetSetNode() *SetNodeRequest {
+	if x, ok := m.GetMutation().(*Mutation_SetNode); ok {
+		return x.SetNode
+	}
+	return nil
+}
+
+func (m *Mutation) GetSetPosition() *SetPositionRequest {
+	if x, ok := m.GetMutation().(*Mutation_SetPosition); ok {
+		return x.SetPosition
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*Mutation) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _Mutation_OneofMarshaler, _Mutation_OneofUnmarshaler, _Mutation_OneofSizer, []interface{}{
+		(*Mutation_SetChannel)(nil),
+		(*Mutation_SetGraphProperties)(nil),
+		(*Mutation_SetNode)(nil),
+		(*Mutation_SetPosition)(nil),
+	}
+}
+
+func _Mutation_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*Mutation)
+	// mutation
+	switch x := m.Mutation.(type) {
+	case *Mutation_SetChannel:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SetChannel); err != nil {
+			return err
+		}
+	case *Mutation_SetGraphProperties:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SetGraphProperties); err != nil {
+			return err
+		}
+	case *Mutation_SetNode:
+		b.EncodeVarint(3<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SetNode); err != nil {
+			return err
+		}
+	case *Mutation_SetPosition:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SetPosition); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("Mutation.Mutation has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _Mutation_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*Mutation)
+	switch tag {
+	case 1: // mutation.set_channel
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(SetChannelRequest)
+		err := b.DecodeMessage(msg)
+		m.Mutation = &Mutation_SetChannel{msg}
+		return true, err
+	case 2: // mutation.set_graph_properties
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(SetGraphPropertiesRequest)
+		err := b.DecodeMessage(msg)
+		m.Mutation = &Mutation_SetGraphProperties{msg}
+		return true, err
+	case 3: // mutation.set_node
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(SetNodeRequest)
+		err := b.DecodeMessage(msg)
+		m.Mutation = &Mutation_SetNode{msg}
+		return true, err
+	case 4: // mutation.set_position
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(SetPositionRequest)
+		err := b.DecodeMessage(msg)
+		m.Mutation = &Mutation_SetPosition{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _Mutation_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*Mutation)
+	// mutation
+	switch x := m.Mutation.(type) {
+	case *Mutation_SetChannel:
+		s := proto.Size(x.SetChannel)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Mutation_SetGraphProperties:
+		s := proto.Size(x.SetGraphProperties)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Mutation_SetNode:
+		s := proto.Size(x.SetNode)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Mutation_SetPosition:
+		s := proto.Size(x.SetPosition)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type BatchRequest struct {
+	Graph                string      `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
+	Mutations            []*Mutation `protobuf:"bytes,2,rep,name=mutations,proto3" json:"mutations,omitempty"`
+	Version              uint64      `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *BatchRequest) Reset()         { *m = BatchRequest{} }
+func (m *BatchRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchRequest) ProtoMessage()    {}
+func (*BatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{11}
+}
+func (m *BatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchRequest.Unmarshal(m, b)
+}
+func (m *BatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchRequest.Marshal(b, m, deterministic)
+}
+func (dst *BatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchRequest.Merge(dst, src)
+}
+func (m *BatchRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchRequest.Size(m)
+}
+func (m *BatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchRequest proto.InternalMessageInfo
+
+func (m *BatchRequest) GetGraph() string {
+	if m != nil {
+		return m.Graph
+	}
+	return ""
+}
+
+func (m *BatchRequest) GetMutations() []*Mutation {
+	if m != nil {
+		return m.Mutations
+	}
+	return nil
+}
+
+func (m *BatchRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type ChannelTelemetry struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Sent                 uint64   `protobuf:"varint,2,opt,name=sent,proto3" json:"sent,omitempty"`
+	Received             uint64   `protobuf:"varint,3,opt,name=received,proto3" json:"received,omitempty"`
+	Len                  uint64   `protobuf:"varint,4,opt,name=len,proto3" json:"len,omitempty"`
+	Cap                  uint64   `protobuf:"varint,5,opt,name=cap,proto3" json:"cap,omitempty"`
+	Blocked              bool     `protobuf:"varint,6,opt,name=blocked,proto3" json:"blocked,omitempty"`
+	Closed               bool     `protobuf:"varint,7,opt,name=closed,proto3" json:"closed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChannelTelemetry) Reset()         { *m = ChannelTelemetry{} }
+func (m *ChannelTelemetry) String() string { return proto.CompactTextString(m) }
+func (*ChannelTelemetry) ProtoMessage()    {}
+func (*ChannelTelemetry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{12}
+}
+func (m *ChannelTelemetry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChannelTelemetry.Unmarshal(m, b)
+}
+func (m *ChannelTelemetry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChannelTelemetry.Marshal(b, m, deterministic)
+}
+func (dst *ChannelTelemetry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChannelTelemetry.Merge(dst, src)
+}
+func (m *ChannelTelemetry) XXX_Size() int {
+	return xxx_messageInfo_ChannelTelemetry.Size(m)
+}
+func (m *ChannelTelemetry) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChannelTelemetry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChannelTelemetry proto.InternalMessageInfo
+
+func (m *ChannelTelemetry) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ChannelTelemetry) GetSent() uint64 {
+	if m != nil {
+		return m.Sent
+	}
+	return 0
+}
+
+func (m *ChannelTelemetry) GetReceived() uint64 {
+	if m != nil {
+		return m.Received
+	}
+	return 0
+}
+
+func (m *ChannelTelemetry) GetLen() uint64 {
+	if m != nil {
+		return m.Len
+	}
+	return 0
+}
+
+func (m *ChannelTelemetry) GetCap() uint64 {
+	if m != nil {
+		return m.Cap
+	}
+	return 0
+}
+
+func (m *ChannelTelemetry) GetBlocked() bool {
+	if m != nil {
+		return m.Blocked
+	}
+	return false
+}
+
+func (m *ChannelTelemetry) GetClosed() bool {
+	if m != nil {
+		return m.Closed
+	}
+	return false
+}
+
+type NodeTelemetry struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Running              bool     `protobuf:"varint,2,opt,name=running,proto3" json:"running,omitempty"`
+	Finished             bool     `protobuf:"varint,3,opt,name=finished,proto3" json:"finished,omitempty"`
+	Err                  string   `protobuf:"bytes,4,opt,name=err,proto3" json:"err,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeTelemetry) Reset()         { *m = NodeTelemetry{} }
+func (m *NodeTelemetry) String() string { return proto.CompactTextString(m) }
+func (*NodeTelemetry) ProtoMessage()    {}
+func (*NodeTelemetry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{13}
+}
+func (m *NodeTelemetry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeTelemetry.Unmarshal(m, b)
+}
+func (m *NodeTelemetry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeTelemetry.Marshal(b, m, deterministic)
+}
+func (dst *NodeTelemetry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeTelemetry.Merge(dst, src)
+}
+func (m *NodeTelemetry) XXX_Size() int {
+	return xxx_messageInfo_NodeTelemetry.Size(m)
+}
+func (m *NodeTelemetry) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeTelemetry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeTelemetry proto.InternalMessageInfo
+
+func (m *NodeTelemetry) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *NodeTelemetry) GetRunning() bool {
+	if m != nil {
+		return m.Running
+	}
+	return false
+}
+
+func (m *NodeTelemetry) GetFinished() bool {
+	if m != nil {
+		return m.Finished
+	}
+	return false
+}
+
+func (m *NodeTelemetry) GetErr() string {
+	if m != nil {
+		return m.Err
+	}
+	return ""
+}
+
+type Telemetry struct {
+	Channels             []*ChannelTelemetry `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+	Nodes                []*NodeTelemetry    `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *Telemetry) Reset()         { *m = Telemetry{} }
+func (m *Telemetry) String() string { return proto.CompactTextString(m) }
+func (*Telemetry) ProtoMessage()    {}
+func (*Telemetry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{14}
+}
+func (m *Telemetry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Telemetry.Unmarshal(m, b)
+}
+func (m *Telemetry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Telemetry.Marshal(b, m, deterministic)
+}
+func (dst *Telemetry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Telemetry.Merge(dst, src)
+}
+func (m *Telemetry) XXX_Size() int {
+	return xxx_messageInfo_Telemetry.Size(m)
+}
+func (m *Telemetry) XXX_DiscardUnknown() {
+	xxx_messageInfo_Telemetry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Telemetry proto.InternalMessageInfo
+
+func (m *Telemetry) GetChannels() []*ChannelTelemetry {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+func (m *Telemetry) GetNodes() []*NodeTelemetry {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type UndoRequest struct {
+	Graph                string   `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UndoRequest) Reset()         { *m = UndoRequest{} }
+func (m *UndoRequest) String() string { return proto.CompactTextString(m) }
+func (*UndoRequest) ProtoMessage()    {}
+func (*UndoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{15}
+}
+func (m *UndoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UndoRequest.Unmarshal(m, b)
+}
+func (m *UndoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UndoRequest.Marshal(b, m, deterministic)
+}
+func (dst *UndoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UndoRequest.Merge(dst, src)
+}
+func (m *UndoRequest) XXX_Size() int {
+	return xxx_messageInfo_UndoRequest.Size(m)
+}
+func (m *UndoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UndoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UndoRequest proto.InternalMessageInfo
+
+func (m *UndoRequest) GetGraph() string {
+	if m != nil {
+		return m.Graph
+	}
+	return ""
+}
+
+type WatchGraphRequest struct {
+	Graph                string   `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
+	Version              uint64   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchGraphRequest) Reset()         { *m = WatchGraphRequest{} }
+func (m *WatchGraphRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchGraphRequest) ProtoMessage()    {}
+func (*WatchGraphRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{16}
+}
+func (m *WatchGraphRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchGraphRequest.Unmarshal(m, b)
+}
+func (m *WatchGraphRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchGraphRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchGraphRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchGraphRequest.Merge(dst, src)
+}
+func (m *WatchGraphRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchGraphRequest.Size(m)
+}
+func (m *WatchGraphRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchGraphRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchGraphRequest proto.InternalMessageInfo
+
+func (m *WatchGraphRequest) GetGraph() string {
+	if m != nil {
+		return m.Graph
+	}
+	return ""
+}
+
+func (m *WatchGraphRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type WatchTelemetryRequest struct {
+	Graph                string   `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchTelemetryRequest) Reset()         { *m = WatchTelemetryRequest{} }
+func (m *WatchTelemetryRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchTelemetryRequest) ProtoMessage()    {}
+func (*WatchTelemetryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{17}
+}
+func (m *WatchTelemetryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchTelemetryRequest.Unmarshal(m, b)
+}
+func (m *WatchTelemetryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchTelemetryRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchTelemetryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchTelemetryRequest.Merge(dst, src)
+}
+func (m *WatchTelemetryRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchTelemetryRequest.Size(m)
+}
+func (m *WatchTelemetryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchTelemetryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchTelemetryRequest proto.InternalMessageInfo
+
+func (m *WatchTelemetryRequest) GetGraph() string {
+	if m != nil {
+		return m.Graph
+	}
+	return ""
+}
+
+type RedoRequest struct {
+	Graph                string   `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RedoRequest) Reset()         { *m = RedoRequest{} }
+func (m *RedoRequest) String() string { return proto.CompactTextString(m) }
+func (*RedoRequest) ProtoMessage()    {}
+func (*RedoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{18}
+}
+func (m *RedoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RedoRequest.Unmarshal(m, b)
+}
+func (m *RedoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RedoRequest.Marshal(b, m, deterministic)
+}
+func (dst *RedoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RedoRequest.Merge(dst, src)
+}
+func (m *RedoRequest) XXX_Size() int {
+	return xxx_messageInfo_RedoRequest.Size(m)
+}
+func (m *RedoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RedoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RedoRequest proto.InternalMessageInfo
+
+func (m *RedoRequest) GetGraph() string {
+	if m != nil {
+		return m.Graph
+	}
+	return ""
+}
+
+type SetNodeRequest struct {
+	Graph                string      `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
+	Node                 string      `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	Config               *NodeConfig `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
+	Version              uint64      `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *SetNodeRequest) Reset()         { *m = SetNodeRequest{} }
+func (m *SetNodeRequest) String() string { return proto.CompactTextString(m) }
+func (*SetNodeRequest) ProtoMessage()    {}
+func (*SetNodeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{19}
+}
+func (m *SetNodeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetNodeRequest.Unmarshal(m, b)
+}
+func (m *SetNodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetNodeRequest.Marshal(b, m, deterministic)
+}
+func (dst *SetNodeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetNodeRequest.Merge(dst, src)
+}
+func (m *SetNodeRequest) XXX_Size() int {
+	return xxx_messageInfo_SetNodeRequest.Size(m)
+}
+func (m *SetNodeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetNodeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetNodeRequest proto.InternalMessageInfo
+
+func (m *SetNodeRequest) GetGraph() string {
+	if m != nil {
+		return m.Graph
+	}
+	return ""
+}
+
+func (m *SetNodeRequest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *SetNodeRequest) GetConfig() *NodeConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *SetNodeRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type SetPositionRequest struct {
+	Graph                string   `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
+	Node                 string   `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	X                    float64  `protobuf:"fixed64,3,opt,name=x,proto3" json:"x,omitempty"`
+	Y                    float64  `protobuf:"fixed64,4,opt,name=y,proto3" json:"y,omitempty"`
+	Version              uint64   `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetPositionRequest) Reset()         { *m = SetPositionRequest{} }
+func (m *SetPositionRequest) String() string { return proto.CompactTextString(m) }
+func (*SetPositionRequest) ProtoMessage()    {}
+func (*SetPositionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{20}
+}
+func (m *SetPositionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetPositionRequest.Unmarshal(m, b)
+}
+func (m *SetPositionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetPositionRequest.Marshal(b, m, deterministic)
+}
+func (dst *SetPositionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetPositionRequest.Merge(dst, src)
+}
+func (m *SetPositionRequest) XXX_Size() int {
+	return xxx_messageInfo_SetPositionRequest.Size(m)
+}
+func (m *SetPositionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetPositionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetPositionRequest proto.InternalMessageInfo
+
+func (m *SetPositionRequest) GetGraph() string {
+	if m != nil {
+		return m.Graph
+	}
+	return ""
+}
+
+func (m *SetPositionRequest) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *SetPositionRequest) GetX() float64 {
+	if m != nil {
+		return m.X
+	}
+	return 0
+}
+
+func (m *SetPositionRequest) GetY() float64 {
+	if m != nil {
+		return m.Y
+	}
+	return 0
+}
+
+func (m *SetPositionRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type GraphProperties struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PackagePath          string   `protobuf:"bytes,2,opt,name=package_path,json=packagePath,proto3" json:"package_path,omitempty"`
+	IsCommand            bool     `protobuf:"varint,3,opt,name=is_command,json=isCommand,proto3" json:"is_command,omitempty"`
+	OutputDir            string   `protobuf:"bytes,4,opt,name=output_dir,json=outputDir,proto3" json:"output_dir,omitempty"`
+	UseContext           bool     `protobuf:"varint,5,opt,name=use_context,json=useContext,proto3" json:"use_context,omitempty"`
+	Telemetry            bool     `protobuf:"varint,6,opt,name=telemetry,proto3" json:"telemetry,omitempty"`
+	Generics             bool     `protobuf:"varint,7,opt,name=generics,proto3" json:"generics,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GraphProperties) Reset()         { *m = GraphProperties{} }
+func (m *GraphProperties) String() string { return proto.CompactTextString(m) }
+func (*GraphProperties) ProtoMessage()    {}
+func (*GraphProperties) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{21}
+}
+func (m *GraphProperties) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GraphProperties.Unmarshal(m, b)
+}
+func (m *GraphProperties) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GraphProperties.Marshal(b, m, deterministic)
+}
+func (dst *GraphProperties) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GraphProperties.Merge(dst, src)
+}
+func (m *GraphProperties) XXX_Size() int {
+	return xxx_messageInfo_GraphProperties.Size(m)
+}
+func (m *GraphProperties) XXX_DiscardUnknown() {
+	xxx_messageInfo_GraphProperties.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GraphProperties proto.InternalMessageInfo
+
+func (m *GraphProperties) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GraphProperties) GetPackagePath() string {
+	if m != nil {
+		return m.PackagePath
+	}
+	return ""
+}
+
+func (m *GraphProperties) GetIsCommand() bool {
+	if m != nil {
+		return m.IsCommand
+	}
+	return false
+}
+
+func (m *GraphProperties) GetOutputDir() string {
+	if m != nil {
+		return m.OutputDir
+	}
+	return ""
+}
+
+func (m *GraphProperties) GetUseContext() bool {
+	if m != nil {
+		return m.UseContext
+	}
+	return false
+}
+
+func (m *GraphProperties) GetTelemetry() bool {
+	if m != nil {
+		return m.Telemetry
+	}
+	return false
+}
+
+func (m *GraphProperties) GetGenerics() bool {
+	if m != nil {
+		return m.Generics
+	}
+	return false
+}
+
+type NodeChange struct {
+	Name                 string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Config               *NodeConfig `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *NodeChange) Reset()         { *m = NodeChange{} }
+func (m *NodeChange) String() string { return proto.CompactTextString(m) }
+func (*NodeChange) ProtoMessage()    {}
+func (*NodeChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{22}
+}
+func (m *NodeChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeChange.Unmarshal(m, b)
+}
+func (m *NodeChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeChange.Marshal(b, m, deterministic)
+}
+func (dst *NodeChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeChange.Merge(dst, src)
+}
+func (m *NodeChange) XXX_Size() int {
+	return xxx_messageInfo_NodeChange.Size(m)
+}
+func (m *NodeChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeChange proto.InternalMessageInfo
+
+func (m *NodeChange) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *NodeChange) GetConfig() *NodeConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type ChannelChange struct {
+	Name                 string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Config               *ChannelConfig `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ChannelChange) Reset()         { *m = ChannelChange{} }
+func (m *ChannelChange) String() string { return proto.CompactTextString(m) }
+func (*ChannelChange) ProtoMessage()    {}
+func (*ChannelChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{23}
+}
+func (m *ChannelChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChannelChange.Unmarshal(m, b)
+}
+func (m *ChannelChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChannelChange.Marshal(b, m, deterministic)
+}
+func (dst *ChannelChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChannelChange.Merge(dst, src)
+}
+func (m *ChannelChange) XXX_Size() int {
+	return xxx_messageInfo_ChannelChange.Size(m)
+}
+func (m *ChannelChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChannelChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChannelChange proto.InternalMessageInfo
+
+func (m *ChannelChange) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ChannelChange) GetConfig() *ChannelConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type GraphChange struct {
+	Version              uint64           `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Properties           *GraphProperties `protobuf:"bytes,2,opt,name=properties,proto3" json:"properties,omitempty"`
+	Nodes                []*NodeChange    `protobuf:"bytes,3,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Channels             []*ChannelChange `protobuf:"bytes,4,rep,name=channels,proto3" json:"channels,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *GraphChange) Reset()         { *m = GraphChange{} }
+func (m *GraphChange) String() string { return proto.CompactTextString(m) }
+func (*GraphChange) ProtoMessage()    {}
+func (*GraphChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{24}
+}
+func (m *GraphChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GraphChange.Unmarshal(m, b)
+}
+func (m *GraphChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GraphChange.Marshal(b, m, deterministic)
 }
-func (m *SetGraphPropertiesRequest) XXX_Size() int {
-	return xxx_messageInfo_SetGraphPropertiesRequest.Size(m)
+func (dst *GraphChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GraphChange.Merge(dst, src)
 }
-func (m *SetGraphPropertiesRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetGraphPropertiesRequest.DiscardUnknown(m)
+func (m *GraphChange) XXX_Size() int {
+	return xxx_messageInfo_GraphChange.Size(m)
+}
+func (m *GraphChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_GraphChange.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SetGraphPropertiesRequest proto.InternalMessageInfo
+var xxx_messageInfo_GraphChange proto.InternalMessageInfo
 
-func (m *SetGraphPropertiesRequest) GetGraph() string {
+func (m *GraphChange) GetVersion() uint64 {
 	if m != nil {
-		return m.Graph
+		return m.Version
 	}
-	return ""
+	return 0
 }
 
-func (m *SetGraphPropertiesRequest) GetName() string {
+func (m *GraphChange) GetProperties() *GraphProperties {
 	if m != nil {
-		return m.Name
+		return m.Properties
 	}
-	return ""
+	return nil
 }
 
-func (m *SetGraphPropertiesRequest) GetPackagePath() string {
+func (m *GraphChange) GetNodes() []*NodeChange {
 	if m != nil {
-		return m.PackagePath
+		return m.Nodes
 	}
-	return ""
+	return nil
 }
 
-func (m *SetGraphPropertiesRequest) GetIsCommand() bool {
+func (m *GraphChange) GetChannels() []*ChannelChange {
 	if m != nil {
-		return m.IsCommand
+		return m.Channels
 	}
-	return false
+	return nil
 }
 
-type SetNodeRequest struct {
-	Graph                string      `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
-	Node                 string      `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
-	Config               *NodeConfig `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+type EditResponse struct {
+	Version              uint64   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SetNodeRequest) Reset()         { *m = SetNodeRequest{} }
-func (m *SetNodeRequest) String() string { return proto.CompactTextString(m) }
-func (*SetNodeRequest) ProtoMessage()    {}
-func (*SetNodeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{10}
-}
-func (m *SetNodeRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SetNodeRequest.Unmarshal(m, b)
+func (m *EditResponse) Reset()         { *m = EditResponse{} }
+func (m *EditResponse) String() string { return proto.CompactTextString(m) }
+func (*EditResponse) ProtoMessage()    {}
+func (*EditResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{25}
 }
-func (m *SetNodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SetNodeRequest.Marshal(b, m, deterministic)
+func (m *EditResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EditResponse.Unmarshal(m, b)
 }
-func (dst *SetNodeRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetNodeRequest.Merge(dst, src)
+func (m *EditResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EditResponse.Marshal(b, m, deterministic)
 }
-func (m *SetNodeRequest) XXX_Size() int {
-	return xxx_messageInfo_SetNodeRequest.Size(m)
+func (dst *EditResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EditResponse.Merge(dst, src)
 }
-func (m *SetNodeRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetNodeRequest.DiscardUnknown(m)
+func (m *EditResponse) XXX_Size() int {
+	return xxx_messageInfo_EditResponse.Size(m)
 }
-
-var xxx_messageInfo_SetNodeRequest proto.InternalMessageInfo
-
-func (m *SetNodeRequest) GetGraph() string {
-	if m != nil {
-		return m.Graph
-	}
-	return ""
+func (m *EditResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EditResponse.DiscardUnknown(m)
 }
 
-func (m *SetNodeRequest) GetNode() string {
-	if m != nil {
-		return m.Node
-	}
-	return ""
-}
+var xxx_messageInfo_EditResponse proto.InternalMessageInfo
 
-func (m *SetNodeRequest) GetConfig() *NodeConfig {
+func (m *EditResponse) GetVersion() uint64 {
 	if m != nil {
-		return m.Config
+		return m.Version
 	}
-	return nil
+	return 0
 }
 
-type SetPositionRequest struct {
-	Graph                string   `protobuf:"bytes,1,opt,name=graph,proto3" json:"graph,omitempty"`
-	Node                 string   `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
-	X                    float64  `protobuf:"fixed64,3,opt,name=x,proto3" json:"x,omitempty"`
-	Y                    float64  `protobuf:"fixed64,4,opt,name=y,proto3" json:"y,omitempty"`
+type CompileError struct {
+	File                 string   `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	Line                 uint32   `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"`
+	Column               uint32   `protobuf:"varint,3,opt,name=column,proto3" json:"column,omitempty"`
+	Message              string   `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Node                 string   `protobuf:"bytes,5,opt,name=node,proto3" json:"node,omitempty"`
+	Section              string   `protobuf:"bytes,6,opt,name=section,proto3" json:"section,omitempty"`
+	SectionLine          uint32   `protobuf:"varint,7,opt,name=section_line,json=sectionLine,proto3" json:"section_line,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SetPositionRequest) Reset()         { *m = SetPositionRequest{} }
-func (m *SetPositionRequest) String() string { return proto.CompactTextString(m) }
-func (*SetPositionRequest) ProtoMessage()    {}
-func (*SetPositionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_shenzhen_go_017a25db36f90c92, []int{11}
+func (m *CompileError) Reset()         { *m = CompileError{} }
+func (m *CompileError) String() string { return proto.CompactTextString(m) }
+func (*CompileError) ProtoMessage()    {}
+func (*CompileError) Descriptor() ([]byte, []int) {
+	return fileDescriptor_shenzhen_go_f00bd7e6e7409d05, []int{26}
 }
-func (m *SetPositionRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SetPositionRequest.Unmarshal(m, b)
+func (m *CompileError) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompileError.Unmarshal(m, b)
 }
-func (m *SetPositionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SetPositionRequest.Marshal(b, m, deterministic)
+func (m *CompileError) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompileError.Marshal(b, m, deterministic)
 }
-func (dst *SetPositionRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetPositionRequest.Merge(dst, src)
+func (dst *CompileError) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompileError.Merge(dst, src)
 }
-func (m *SetPositionRequest) XXX_Size() int {
-	return xxx_messageInfo_SetPositionRequest.Size(m)
+func (m *CompileError) XXX_Size() int {
+	return xxx_messageInfo_CompileError.Size(m)
 }
-func (m *SetPositionRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetPositionRequest.DiscardUnknown(m)
+func (m *CompileError) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompileError.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SetPositionRequest proto.InternalMessageInfo
+var xxx_messageInfo_CompileError proto.InternalMessageInfo
 
-func (m *SetPositionRequest) GetGraph() string {
+func (m *CompileError) GetFile() string {
 	if m != nil {
-		return m.Graph
+		return m.File
 	}
 	return ""
 }
 
-func (m *SetPositionRequest) GetNode() string {
+func (m *CompileError) GetLine() uint32 {
+	if m != nil {
+		return m.Line
+	}
+	return 0
+}
+
+func (m *CompileError) GetColumn() uint32 {
+	if m != nil {
+		return m.Column
+	}
+	return 0
+}
+
+func (m *CompileError) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *CompileError) GetNode() string {
 	if m != nil {
 		return m.Node
 	}
 	return ""
 }
 
-func (m *SetPositionRequest) GetX() float64 {
+func (m *CompileError) GetSection() string {
 	if m != nil {
-		return m.X
+		return m.Section
 	}
-	return 0
+	return ""
 }
 
-func (m *SetPositionRequest) GetY() float64 {
+func (m *CompileError) GetSectionLine() uint32 {
 	if m != nil {
-		return m.Y
+		return m.SectionLine
 	}
 	return 0
 }
@@ -706,8 +1804,23 @@ func init() {
 	proto.RegisterType((*Output)(nil), "proto.Output")
 	proto.RegisterType((*SetChannelRequest)(nil), "proto.SetChannelRequest")
 	proto.RegisterType((*SetGraphPropertiesRequest)(nil), "proto.SetGraphPropertiesRequest")
+	proto.RegisterType((*Mutation)(nil), "proto.Mutation")
+	proto.RegisterType((*BatchRequest)(nil), "proto.BatchRequest")
+	proto.RegisterType((*ChannelTelemetry)(nil), "proto.ChannelTelemetry")
+	proto.RegisterType((*NodeTelemetry)(nil), "proto.NodeTelemetry")
+	proto.RegisterType((*Telemetry)(nil), "proto.Telemetry")
+	proto.RegisterType((*UndoRequest)(nil), "proto.UndoRequest")
+	proto.RegisterType((*WatchGraphRequest)(nil), "proto.WatchGraphRequest")
+	proto.RegisterType((*WatchTelemetryRequest)(nil), "proto.WatchTelemetryRequest")
+	proto.RegisterType((*RedoRequest)(nil), "proto.RedoRequest")
 	proto.RegisterType((*SetNodeRequest)(nil), "proto.SetNodeRequest")
 	proto.RegisterType((*SetPositionRequest)(nil), "proto.SetPositionRequest")
+	proto.RegisterType((*GraphProperties)(nil), "proto.GraphProperties")
+	proto.RegisterType((*NodeChange)(nil), "proto.NodeChange")
+	proto.RegisterType((*ChannelChange)(nil), "proto.ChannelChange")
+	proto.RegisterType((*GraphChange)(nil), "proto.GraphChange")
+	proto.RegisterType((*EditResponse)(nil), "proto.EditResponse")
+	proto.RegisterType((*CompileError)(nil), "proto.CompileError")
 	proto.RegisterEnum("proto.ActionRequest_Action", ActionRequest_Action_name, ActionRequest_Action_value)
 }
 
@@ -725,20 +1838,34 @@ const _ = grpc.SupportPackageIsVersion4
 type ShenzhenGoClient interface {
 	// Action performs an action (save, generate, install/build, etc).
 	Action(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (ShenzhenGo_ActionClient, error)
+	// Batch applies several mutations as one edit. Either all of them are
+	// applied, and the resulting graph passes type inference, or none are.
+	Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*EditResponse, error)
+	// Redo reapplies the most recently undone edit to the graph.
+	Redo(ctx context.Context, in *RedoRequest, opts ...grpc.CallOption) (*EditResponse, error)
 	// Run runs the program.
 	Run(ctx context.Context, opts ...grpc.CallOption) (ShenzhenGo_RunClient, error)
 	// SetNode either creates a new channel (name == "", config != nil)
 	// changes existing channel data such as name and attached pins (name is found, config != nil),
 	// or deletes a channel (name is found, config == nil).
-	SetChannel(ctx context.Context, in *SetChannelRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetChannel(ctx context.Context, in *SetChannelRequest, opts ...grpc.CallOption) (*EditResponse, error)
 	// SetGraphProperties changes metdata such as name and package path.
-	SetGraphProperties(ctx context.Context, in *SetGraphPropertiesRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetGraphProperties(ctx context.Context, in *SetGraphPropertiesRequest, opts ...grpc.CallOption) (*EditResponse, error)
 	// SetNode either creates a new node (name == "", config != nil)
 	// changes existing node such as name and multiplicity (name is found, config != nil),
 	// or deletes a node (name is found, config == nil).
-	SetNode(ctx context.Context, in *SetNodeRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetNode(ctx context.Context, in *SetNodeRequest, opts ...grpc.CallOption) (*EditResponse, error)
 	// SetPosition changes the node position in the diagram.
-	SetPosition(ctx context.Context, in *SetPositionRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetPosition(ctx context.Context, in *SetPositionRequest, opts ...grpc.CallOption) (*EditResponse, error)
+	// Undo reverts the most recent edit to the graph made with SetChannel,
+	// SetGraphProperties, SetNode, or SetPosition.
+	Undo(ctx context.Context, in *UndoRequest, opts ...grpc.CallOption) (*EditResponse, error)
+	// WatchGraph streams changes made to the graph after the given version,
+	// by any client.
+	WatchGraph(ctx context.Context, in *WatchGraphRequest, opts ...grpc.CallOption) (ShenzhenGo_WatchGraphClient, error)
+	// WatchTelemetry streams samples from the program started by Run, for
+	// graphs with telemetry enabled.
+	WatchTelemetry(ctx context.Context, in *WatchTelemetryRequest, opts ...grpc.CallOption) (ShenzhenGo_WatchTelemetryClient, error)
 }
 
 type shenzhenGoClient struct {
@@ -781,6 +1908,24 @@ func (x *shenzhenGoActionClient) Recv() (*ActionResponse, error) {
 	return m, nil
 }
 
+func (c *shenzhenGoClient) Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*EditResponse, error) {
+	out := new(EditResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShenzhenGo/Batch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shenzhenGoClient) Redo(ctx context.Context, in *RedoRequest, opts ...grpc.CallOption) (*EditResponse, error) {
+	out := new(EditResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShenzhenGo/Redo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *shenzhenGoClient) Run(ctx context.Context, opts ...grpc.CallOption) (ShenzhenGo_RunClient, error) {
 	stream, err := c.cc.NewStream(ctx, &_ShenzhenGo_serviceDesc.Streams[1], "/proto.ShenzhenGo/Run", opts...)
 	if err != nil {
@@ -812,8 +1957,8 @@ func (x *shenzhenGoRunClient) Recv() (*Output, error) {
 	return m, nil
 }
 
-func (c *shenzhenGoClient) SetChannel(ctx context.Context, in *SetChannelRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *shenzhenGoClient) SetChannel(ctx context.Context, in *SetChannelRequest, opts ...grpc.CallOption) (*EditResponse, error) {
+	out := new(EditResponse)
 	err := c.cc.Invoke(ctx, "/proto.ShenzhenGo/SetChannel", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -821,8 +1966,8 @@ func (c *shenzhenGoClient) SetChannel(ctx context.Context, in *SetChannelRequest
 	return out, nil
 }
 
-func (c *shenzhenGoClient) SetGraphProperties(ctx context.Context, in *SetGraphPropertiesRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *shenzhenGoClient) SetGraphProperties(ctx context.Context, in *SetGraphPropertiesRequest, opts ...grpc.CallOption) (*EditResponse, error) {
+	out := new(EditResponse)
 	err := c.cc.Invoke(ctx, "/proto.ShenzhenGo/SetGraphProperties", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -830,8 +1975,8 @@ func (c *shenzhenGoClient) SetGraphProperties(ctx context.Context, in *SetGraphP
 	return out, nil
 }
 
-func (c *shenzhenGoClient) SetNode(ctx context.Context, in *SetNodeRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *shenzhenGoClient) SetNode(ctx context.Context, in *SetNodeRequest, opts ...grpc.CallOption) (*EditResponse, error) {
+	out := new(EditResponse)
 	err := c.cc.Invoke(ctx, "/proto.ShenzhenGo/SetNode", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -839,8 +1984,8 @@ func (c *shenzhenGoClient) SetNode(ctx context.Context, in *SetNodeRequest, opts
 	return out, nil
 }
 
-func (c *shenzhenGoClient) SetPosition(ctx context.Context, in *SetPositionRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *shenzhenGoClient) SetPosition(ctx context.Context, in *SetPositionRequest, opts ...grpc.CallOption) (*EditResponse, error) {
+	out := new(EditResponse)
 	err := c.cc.Invoke(ctx, "/proto.ShenzhenGo/SetPosition", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -848,24 +1993,111 @@ func (c *shenzhenGoClient) SetPosition(ctx context.Context, in *SetPositionReque
 	return out, nil
 }
 
+func (c *shenzhenGoClient) Undo(ctx context.Context, in *UndoRequest, opts ...grpc.CallOption) (*EditResponse, error) {
+	out := new(EditResponse)
+	err := c.cc.Invoke(ctx, "/proto.ShenzhenGo/Undo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shenzhenGoClient) WatchGraph(ctx context.Context, in *WatchGraphRequest, opts ...grpc.CallOption) (ShenzhenGo_WatchGraphClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ShenzhenGo_serviceDesc.Streams[2], "/proto.ShenzhenGo/WatchGraph", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shenzhenGoWatchGraphClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ShenzhenGo_WatchGraphClient interface {
+	Recv() (*GraphChange, error)
+	grpc.ClientStream
+}
+
+type shenzhenGoWatchGraphClient struct {
+	grpc.ClientStream
+}
+
+func (x *shenzhenGoWatchGraphClient) Recv() (*GraphChange, error) {
+	m := new(GraphChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *shenzhenGoClient) WatchTelemetry(ctx context.Context, in *WatchTelemetryRequest, opts ...grpc.CallOption) (ShenzhenGo_WatchTelemetryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ShenzhenGo_serviceDesc.Streams[3], "/proto.ShenzhenGo/WatchTelemetry", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &shenzhenGoWatchTelemetryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ShenzhenGo_WatchTelemetryClient interface {
+	Recv() (*Telemetry, error)
+	grpc.ClientStream
+}
+
+type shenzhenGoWatchTelemetryClient struct {
+	grpc.ClientStream
+}
+
+func (x *shenzhenGoWatchTelemetryClient) Recv() (*Telemetry, error) {
+	m := new(Telemetry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ShenzhenGoServer is the server API for ShenzhenGo service.
 type ShenzhenGoServer interface {
 	// Action performs an action (save, generate, install/build, etc).
 	Action(*ActionRequest, ShenzhenGo_ActionServer) error
+	// Batch applies several mutations as one edit. Either all of them are
+	// applied, and the resulting graph passes type inference, or none are.
+	Batch(context.Context, *BatchRequest) (*EditResponse, error)
+	// Redo reapplies the most recently undone edit to the graph.
+	Redo(context.Context, *RedoRequest) (*EditResponse, error)
 	// Run runs the program.
 	Run(ShenzhenGo_RunServer) error
 	// SetNode either creates a new channel (name == "", config != nil)
 	// changes existing channel data such as name and attached pins (name is found, config != nil),
 	// or deletes a channel (name is found, config == nil).
-	SetChannel(context.Context, *SetChannelRequest) (*Empty, error)
+	SetChannel(context.Context, *SetChannelRequest) (*EditResponse, error)
 	// SetGraphProperties changes metdata such as name and package path.
-	SetGraphProperties(context.Context, *SetGraphPropertiesRequest) (*Empty, error)
+	SetGraphProperties(context.Context, *SetGraphPropertiesRequest) (*EditResponse, error)
 	// SetNode either creates a new node (name == "", config != nil)
 	// changes existing node such as name and multiplicity (name is found, config != nil),
 	// or deletes a node (name is found, config == nil).
-	SetNode(context.Context, *SetNodeRequest) (*Empty, error)
+	SetNode(context.Context, *SetNodeRequest) (*EditResponse, error)
 	// SetPosition changes the node position in the diagram.
-	SetPosition(context.Context, *SetPositionRequest) (*Empty, error)
+	SetPosition(context.Context, *SetPositionRequest) (*EditResponse, error)
+	// Undo reverts the most recent edit to the graph made with SetChannel,
+	// SetGraphProperties, SetNode, or SetPosition.
+	Undo(context.Context, *UndoRequest) (*EditResponse, error)
+	// WatchGraph streams changes made to the graph after the given version,
+	// by any client.
+	WatchGraph(*WatchGraphRequest, ShenzhenGo_WatchGraphServer) error
+	// WatchTelemetry streams samples from the program started by Run, for
+	// graphs with telemetry enabled.
+	WatchTelemetry(*WatchTelemetryRequest, ShenzhenGo_WatchTelemetryServer) error
 }
 
 func RegisterShenzhenGoServer(s *grpc.Server, srv ShenzhenGoServer) {
@@ -893,6 +2125,42 @@ func (x *shenzhenGoActionServer) Send(m *ActionResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _ShenzhenGo_Batch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShenzhenGoServer).Batch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShenzhenGo/Batch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShenzhenGoServer).Batch(ctx, req.(*BatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShenzhenGo_Redo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShenzhenGoServer).Redo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShenzhenGo/Redo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShenzhenGoServer).Redo(ctx, req.(*RedoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ShenzhenGo_Run_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(ShenzhenGoServer).Run(&shenzhenGoRunServer{stream})
 }
@@ -991,10 +2259,78 @@ func _ShenzhenGo_SetPosition_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShenzhenGo_Undo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShenzhenGoServer).Undo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.ShenzhenGo/Undo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShenzhenGoServer).Undo(ctx, req.(*UndoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShenzhenGo_WatchGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchGraphRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShenzhenGoServer).WatchGraph(m, &shenzhenGoWatchGraphServer{stream})
+}
+
+type ShenzhenGo_WatchGraphServer interface {
+	Send(*GraphChange) error
+	grpc.ServerStream
+}
+
+type shenzhenGoWatchGraphServer struct {
+	grpc.ServerStream
+}
+
+func (x *shenzhenGoWatchGraphServer) Send(m *GraphChange) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ShenzhenGo_WatchTelemetry_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTelemetryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShenzhenGoServer).WatchTelemetry(m, &shenzhenGoWatchTelemetryServer{stream})
+}
+
+type ShenzhenGo_WatchTelemetryServer interface {
+	Send(*Telemetry) error
+	grpc.ServerStream
+}
+
+type shenzhenGoWatchTelemetryServer struct {
+	grpc.ServerStream
+}
+
+func (x *shenzhenGoWatchTelemetryServer) Send(m *Telemetry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _ShenzhenGo_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.ShenzhenGo",
 	HandlerType: (*ShenzhenGoServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Batch",
+			Handler:    _ShenzhenGo_Batch_Handler,
+		},
+		{
+			MethodName: "Redo",
+			Handler:    _ShenzhenGo_Redo_Handler,
+		},
 		{
 			MethodName: "SetChannel",
 			Handler:    _ShenzhenGo_SetChannel_Handler,
@@ -1011,6 +2347,10 @@ var _ShenzhenGo_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SetPosition",
 			Handler:    _ShenzhenGo_SetPosition_Handler,
 		},
+		{
+			MethodName: "Undo",
+			Handler:    _ShenzhenGo_Undo_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1024,56 +2364,115 @@ var _ShenzhenGo_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "WatchGraph",
+			Handler:       _ShenzhenGo_WatchGraph_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchTelemetry",
+			Handler:       _ShenzhenGo_WatchTelemetry_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "shenzhen-go.proto",
 }
 
-func init() { proto.RegisterFile("shenzhen-go.proto", fileDescriptor_shenzhen_go_017a25db36f90c92) }
-
-var fileDescriptor_shenzhen_go_017a25db36f90c92 = []byte{
-	// 700 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x94, 0xdb, 0x4e, 0x1b, 0x3d,
-	0x10, 0xc7, 0xe3, 0x1c, 0x36, 0xc9, 0x24, 0x44, 0x61, 0x04, 0x9f, 0x16, 0xd0, 0x27, 0xa5, 0xbe,
-	0xda, 0x4a, 0x40, 0x51, 0x90, 0xaa, 0xf6, 0x32, 0x0d, 0x29, 0x42, 0x42, 0x34, 0xda, 0xa4, 0x48,
-	0xed, 0x0d, 0x5a, 0x12, 0x93, 0xac, 0x9a, 0xd8, 0x66, 0xd7, 0x51, 0x49, 0x5f, 0xa0, 0xaf, 0xd1,
-	0x17, 0xab, 0xd4, 0x47, 0xa9, 0xec, 0xf5, 0xe6, 0x04, 0x85, 0xab, 0xf5, 0x7f, 0x3c, 0xff, 0x19,
-	0x1f, 0x7e, 0x5e, 0xd8, 0x8e, 0xc7, 0x8c, 0xff, 0x18, 0x33, 0x7e, 0x34, 0x12, 0xc7, 0x32, 0x12,
-	0x4a, 0x60, 0xc1, 0x7c, 0x68, 0x11, 0x0a, 0x9d, 0xa9, 0x54, 0x73, 0xfa, 0x06, 0x8a, 0x57, 0x62,
-	0xc8, 0xba, 0x21, 0x47, 0x84, 0x3c, 0x17, 0x43, 0xe6, 0x92, 0x06, 0xf1, 0xca, 0xbe, 0x19, 0x63,
-	0x1d, 0x72, 0x32, 0xe4, 0x6e, 0xd6, 0x84, 0xf4, 0x90, 0x7e, 0x81, 0xad, 0xf6, 0x38, 0xe0, 0x9c,
-	0x4d, 0xda, 0x82, 0xdf, 0x85, 0x23, 0x63, 0x0b, 0xa6, 0x4b, 0x5b, 0x30, 0x35, 0xb6, 0x41, 0x20,
-	0x8d, 0x2d, 0xef, 0xeb, 0x21, 0x52, 0xc8, 0xcb, 0x90, 0xc7, 0x6e, 0xae, 0x91, 0xf3, 0x2a, 0xcd,
-	0x5a, 0xb2, 0x9a, 0x63, 0xdb, 0xda, 0x37, 0x73, 0xf4, 0x0f, 0x01, 0xd0, 0x91, 0x67, 0x0a, 0xbb,
-	0x50, 0x1c, 0x88, 0xe9, 0x94, 0x71, 0x65, 0xd7, 0x94, 0x4a, 0x3d, 0xc3, 0x78, 0x70, 0x3b, 0x61,
-	0x43, 0x37, 0xd7, 0x20, 0x5e, 0xc9, 0x4f, 0x25, 0x52, 0xa8, 0x4e, 0x67, 0x13, 0x15, 0xca, 0x49,
-	0x38, 0x08, 0xd5, 0xdc, 0xcd, 0x1b, 0xe3, 0x5a, 0x4c, 0xf7, 0xfa, 0x1e, 0x84, 0xca, 0x2d, 0x18,
-	0xab, 0x19, 0xe3, 0x1e, 0x94, 0x64, 0x10, 0xa9, 0x9b, 0xc1, 0xdd, 0xc8, 0x75, 0x1a, 0xc4, 0xab,
-	0xfa, 0x45, 0xad, 0xdb, 0x77, 0x23, 0x3c, 0x80, 0xb2, 0x99, 0x52, 0x73, 0xc9, 0xdc, 0xa2, 0xa9,
-	0x67, 0x72, 0xfb, 0x73, 0xc9, 0xb0, 0x0a, 0xe4, 0xc1, 0x2d, 0x35, 0x88, 0x47, 0x7c, 0xf2, 0xa0,
-	0xd5, 0xdc, 0x2d, 0x27, 0x6a, 0x4e, 0x7f, 0x11, 0xd8, 0x6a, 0x0d, 0x54, 0x28, 0xb8, 0xcf, 0xee,
-	0x67, 0x2c, 0x56, 0xb8, 0x03, 0x85, 0x51, 0x14, 0xc8, 0xb1, 0xdd, 0x66, 0x22, 0xf0, 0x14, 0x9c,
-	0xc0, 0xa4, 0x99, 0x6d, 0xd6, 0x9a, 0x07, 0xf6, 0xc0, 0xd6, 0xbc, 0xa9, 0xb2, 0xa9, 0xf4, 0x0c,
-	0x9c, 0x24, 0x82, 0x25, 0xc8, 0xf7, 0x5a, 0xd7, 0x9d, 0x7a, 0x06, 0x01, 0x1c, 0xbf, 0x73, 0xdd,
-	0xf1, 0xfb, 0x75, 0x82, 0x55, 0x28, 0x9d, 0x77, 0xae, 0x3a, 0x7e, 0xab, 0xdf, 0xa9, 0x67, 0xb1,
-	0x0c, 0x85, 0x0f, 0x9f, 0x2f, 0x2e, 0xcf, 0xea, 0x39, 0xac, 0x40, 0xf1, 0xe2, 0xaa, 0xd7, 0x6f,
-	0x5d, 0x5e, 0xd6, 0xf3, 0xd4, 0x83, 0x5a, 0xda, 0x25, 0x96, 0x82, 0xc7, 0x0c, 0xff, 0x03, 0x47,
-	0xcc, 0x94, 0x9c, 0x29, 0xbb, 0x46, 0xab, 0xe8, 0x11, 0x14, 0x2e, 0xb8, 0x9c, 0xfd, 0x6b, 0x0f,
-	0x35, 0xc8, 0x2e, 0xd0, 0xc9, 0x86, 0x9c, 0x1e, 0x82, 0xf3, 0xc9, 0x18, 0x35, 0x1e, 0x62, 0x51,
-	0x4d, 0x0f, 0x75, 0x84, 0x45, 0x51, 0xca, 0x19, 0x8b, 0x22, 0x7a, 0x0f, 0xdb, 0x3d, 0xa6, 0x2c,
-	0x6a, 0xcf, 0x1f, 0x96, 0x86, 0x22, 0xc9, 0x5b, 0x40, 0x91, 0x48, 0x3c, 0x04, 0x67, 0x60, 0x60,
-	0x32, 0x4c, 0x54, 0x9a, 0x3b, 0xf6, 0x18, 0xd7, 0x08, 0xf6, 0x6d, 0x0e, 0xfd, 0x49, 0x60, 0xaf,
-	0xc7, 0xd4, 0xb9, 0x2e, 0xda, 0x8d, 0x84, 0x64, 0x91, 0x0a, 0x59, 0xfc, 0x7c, 0xef, 0x14, 0xd2,
-	0xec, 0x0a, 0xa4, 0xaf, 0xa0, 0x2a, 0x83, 0xc1, 0xb7, 0x60, 0xc4, 0x6e, 0x64, 0xa0, 0xc6, 0xa6,
-	0x77, 0xd9, 0xaf, 0xd8, 0x58, 0x37, 0x50, 0x63, 0xfc, 0x1f, 0x20, 0x8c, 0x6f, 0x34, 0xbb, 0x01,
-	0x1f, 0x1a, 0x22, 0x4b, 0x7e, 0x39, 0x8c, 0xdb, 0x49, 0x80, 0x32, 0xa8, 0xf5, 0x98, 0xd2, 0x6f,
-	0xe1, 0xe5, 0xee, 0xfa, 0xc9, 0x66, 0x57, 0x9e, 0xec, 0xeb, 0x8d, 0x3d, 0x6f, 0xaf, 0xbc, 0xb5,
-	0x8d, 0x0d, 0x7f, 0x05, 0xec, 0x31, 0xd5, 0x15, 0x71, 0xf8, 0x32, 0x91, 0x4f, 0xb5, 0x32, 0xa4,
-	0xe7, 0xd6, 0x48, 0xcf, 0x5b, 0xd2, 0x9b, 0xbf, 0xb3, 0x00, 0x3d, 0xfb, 0xfb, 0x39, 0x17, 0xf8,
-	0x7e, 0xc1, 0xe6, 0xce, 0x53, 0x28, 0xef, 0xef, 0x6e, 0x44, 0x13, 0xf4, 0x68, 0xe6, 0x84, 0xa0,
-	0x07, 0x39, 0x7f, 0xc6, 0xb1, 0x6a, 0x33, 0x0c, 0x72, 0xfb, 0x5b, 0x56, 0x25, 0x44, 0xd1, 0x8c,
-	0x47, 0x4e, 0x08, 0xbe, 0x05, 0x58, 0x32, 0x83, 0xae, 0x4d, 0x79, 0x84, 0xd1, 0x7e, 0x5a, 0x2a,
-	0xf9, 0x05, 0x66, 0xf0, 0xa3, 0x39, 0x87, 0x8d, 0x7b, 0xc7, 0xc6, 0xd2, 0xff, 0x34, 0x12, 0x8f,
-	0xea, 0x9c, 0x40, 0xd1, 0x5e, 0x1b, 0xee, 0x2e, 0xcd, 0x2b, 0xd7, 0xf8, 0xc8, 0xf1, 0x0e, 0x2a,
-	0x2b, 0x37, 0x80, 0x7b, 0x4b, 0xd7, 0xc6, 0xad, 0x6c, 0x3a, 0x6f, 0x1d, 0x23, 0x4f, 0xff, 0x06,
-	0x00, 0x00, 0xff, 0xff, 0xdd, 0x1b, 0x48, 0xe6, 0xe4, 0x05, 0x00, 0x00,
+func init() { proto.RegisterFile("shenzhen-go.proto", fileDescriptor_shenzhen_go_f00bd7e6e7409d05) }
+
+var fileDescriptor_shenzhen_go_f00bd7e6e7409d05 = []byte{
+	// 1487 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x57, 0xdd, 0x6e, 0xdb, 0xc6,
+	0x12, 0x0e, 0x25, 0x52, 0xa2, 0x86, 0x92, 0x22, 0xef, 0x71, 0x72, 0x18, 0x27, 0x07, 0xc7, 0x61,
+	0x80, 0x13, 0x9d, 0x22, 0x4e, 0x5c, 0x07, 0x6d, 0x81, 0xa6, 0x2d, 0x60, 0x2b, 0x42, 0x12, 0xc4,
+	0x49, 0xdc, 0xb5, 0x93, 0xa2, 0x57, 0x02, 0x4d, 0xad, 0x25, 0xc2, 0xe2, 0x92, 0xe5, 0xae, 0x52,
+	0xbb, 0x37, 0xbd, 0xe9, 0x4d, 0xd1, 0x47, 0x28, 0xfa, 0x04, 0xed, 0x0b, 0xb4, 0x6f, 0xd3, 0x37,
+	0x29, 0xf6, 0x87, 0x7f, 0xb2, 0x2c, 0xb7, 0x57, 0xda, 0x99, 0xdd, 0xd9, 0x1d, 0xce, 0xcc, 0xf7,
+	0xcd, 0x08, 0xd6, 0xd8, 0x94, 0xd0, 0xef, 0xa6, 0x84, 0x6e, 0x4d, 0xe2, 0x87, 0x49, 0x1a, 0xf3,
+	0x18, 0x59, 0xf2, 0xc7, 0x6b, 0x82, 0x35, 0x8c, 0x12, 0x7e, 0xee, 0x3d, 0x82, 0xe6, 0xeb, 0x78,
+	0x4c, 0x0e, 0x42, 0x8a, 0x10, 0x98, 0x34, 0x1e, 0x13, 0xd7, 0xd8, 0x34, 0xfa, 0x2d, 0x2c, 0xd7,
+	0xa8, 0x07, 0xf5, 0x24, 0xa4, 0x6e, 0x4d, 0xaa, 0xc4, 0xd2, 0xfb, 0x1a, 0x3a, 0x83, 0xa9, 0x4f,
+	0x29, 0x99, 0x0d, 0x62, 0x7a, 0x12, 0x4e, 0xa4, 0x99, 0x1f, 0x15, 0x66, 0x7e, 0x24, 0xcd, 0x02,
+	0x3f, 0x91, 0x66, 0x26, 0x16, 0x4b, 0xe4, 0x81, 0x99, 0x84, 0x94, 0xb9, 0xf5, 0xcd, 0x7a, 0xdf,
+	0xd9, 0xe9, 0x2a, 0x6f, 0x1e, 0xea, 0xa7, 0xb1, 0xdc, 0xf3, 0x7e, 0xac, 0x03, 0x08, 0xcd, 0x8a,
+	0x8b, 0x5d, 0x68, 0x06, 0x71, 0x14, 0x11, 0xca, 0xb5, 0x4f, 0x99, 0x28, 0x76, 0x08, 0xf5, 0x8f,
+	0x67, 0x64, 0xec, 0xd6, 0x37, 0x8d, 0xbe, 0x8d, 0x33, 0x11, 0x79, 0xd0, 0x8e, 0xe6, 0x33, 0x1e,
+	0x26, 0xb3, 0x30, 0x08, 0xf9, 0xb9, 0x6b, 0x4a, 0xc3, 0x8a, 0x4e, 0xbc, 0xf5, 0xad, 0x1f, 0x72,
+	0xd7, 0x92, 0xa6, 0x72, 0x8d, 0x6e, 0x81, 0x9d, 0xf8, 0x29, 0x1f, 0x05, 0x27, 0x13, 0xb7, 0xb1,
+	0x69, 0xf4, 0xdb, 0xb8, 0x29, 0xe4, 0xc1, 0xc9, 0x04, 0xdd, 0x86, 0x96, 0xdc, 0xe2, 0xe7, 0x09,
+	0x71, 0x9b, 0xf2, 0x3e, 0x79, 0xf6, 0xe8, 0x3c, 0x21, 0xa8, 0x0d, 0xc6, 0x99, 0x6b, 0x6f, 0x1a,
+	0x7d, 0x03, 0x1b, 0x67, 0x42, 0x3a, 0x77, 0x5b, 0x4a, 0x3a, 0x17, 0x5e, 0xa6, 0x24, 0x88, 0xdf,
+	0x93, 0xd4, 0x05, 0xe5, 0xa5, 0x16, 0xd5, 0x0e, 0xe3, 0x7e, 0xca, 0x5d, 0x47, 0x7d, 0x99, 0x16,
+	0xd1, 0x5d, 0x68, 0x47, 0xfe, 0xd9, 0x48, 0x8b, 0xcc, 0x6d, 0x6f, 0x1a, 0xfd, 0x0e, 0x76, 0x22,
+	0xff, 0x0c, 0x6b, 0x15, 0xba, 0x0f, 0xd7, 0xf5, 0xf6, 0xe8, 0xd8, 0x0f, 0x4e, 0xe3, 0x93, 0x13,
+	0xb7, 0x23, 0x2f, 0xe9, 0x6a, 0xf5, 0x9e, 0xd2, 0x96, 0x0f, 0x46, 0x84, 0xa7, 0x61, 0xc0, 0xdc,
+	0xae, 0xf4, 0x23, 0x3b, 0xf8, 0x4a, 0x69, 0xbd, 0x5f, 0x0d, 0xe8, 0xec, 0x06, 0x3c, 0x8c, 0x29,
+	0x26, 0xdf, 0xcc, 0x09, 0xe3, 0x68, 0x1d, 0xac, 0x49, 0xea, 0x27, 0x53, 0x9d, 0x0f, 0x25, 0xa0,
+	0xc7, 0xd0, 0xf0, 0xe5, 0x31, 0x99, 0x8f, 0xee, 0xce, 0x6d, 0x9d, 0xd9, 0x8a, 0x6d, 0x26, 0xe9,
+	0xa3, 0xde, 0x1b, 0x68, 0x28, 0x0d, 0xb2, 0xc1, 0x3c, 0xdc, 0x7d, 0x37, 0xec, 0x5d, 0x43, 0x00,
+	0x0d, 0x3c, 0x7c, 0x37, 0xc4, 0x47, 0x3d, 0x03, 0xb5, 0xc1, 0x7e, 0x36, 0x7c, 0x3d, 0xc4, 0xbb,
+	0x47, 0xc3, 0x5e, 0x0d, 0xb5, 0xc0, 0xda, 0x7b, 0xfb, 0x62, 0xff, 0x69, 0xaf, 0x8e, 0x1c, 0x68,
+	0xbe, 0x78, 0x7d, 0x78, 0xb4, 0xbb, 0xbf, 0xdf, 0x33, 0x85, 0x7e, 0xf0, 0x7c, 0x38, 0x78, 0xd9,
+	0xb3, 0xbc, 0x31, 0x74, 0xb3, 0x07, 0x59, 0x12, 0x53, 0x46, 0xd0, 0x4d, 0x68, 0xc4, 0x73, 0x9e,
+	0xcc, 0xb9, 0x76, 0x57, 0x4b, 0xe8, 0x53, 0xe8, 0x06, 0x71, 0x94, 0x84, 0x33, 0x32, 0x22, 0x69,
+	0x1a, 0xa7, 0xcc, 0xad, 0xc9, 0x8a, 0xfc, 0x97, 0xf6, 0x7b, 0xa0, 0x36, 0x87, 0x62, 0x0f, 0x77,
+	0x82, 0x92, 0xc4, 0xbc, 0x2d, 0xb0, 0x5e, 0xd0, 0x64, 0x7e, 0x59, 0x28, 0xba, 0x50, 0xcb, 0xa1,
+	0x52, 0x0b, 0xa9, 0xf7, 0x00, 0x1a, 0x6f, 0xd4, 0xa3, 0x3d, 0xa8, 0xc7, 0xb9, 0x27, 0xf5, 0x58,
+	0x69, 0x48, 0x9a, 0x66, 0xb8, 0x22, 0x69, 0xea, 0xfd, 0x64, 0xc0, 0xda, 0x21, 0xe1, 0x1a, 0x5b,
+	0xab, 0x83, 0x2e, 0x50, 0xa0, 0xce, 0xe5, 0x28, 0x50, 0x22, 0x7a, 0x00, 0x8d, 0x40, 0xa2, 0x47,
+	0x82, 0xc0, 0xd9, 0x59, 0xcf, 0x3e, 0xab, 0x0c, 0x59, 0xac, 0xcf, 0x88, 0x7b, 0xde, 0x93, 0x94,
+	0x89, 0xec, 0x99, 0x12, 0xaa, 0x99, 0xe8, 0xfd, 0x5c, 0x83, 0x5b, 0x87, 0x84, 0x3f, 0x13, 0xcf,
+	0x1d, 0xa4, 0x71, 0x42, 0x52, 0x1e, 0x12, 0xb6, 0xda, 0xab, 0x0c, 0xaf, 0xb5, 0x12, 0x5e, 0xef,
+	0x42, 0x3b, 0xf1, 0x83, 0x53, 0x7f, 0x42, 0x46, 0x89, 0xcf, 0xa7, 0xd2, 0xab, 0x16, 0x76, 0xb4,
+	0xee, 0xc0, 0xe7, 0x53, 0xf4, 0x1f, 0x80, 0x90, 0x8d, 0x04, 0x8c, 0x7d, 0x3a, 0x96, 0x7e, 0xd8,
+	0xb8, 0x15, 0xb2, 0x81, 0x52, 0x88, 0x6d, 0x95, 0xba, 0xd1, 0x38, 0x4c, 0x25, 0x3e, 0x5b, 0xb8,
+	0xa5, 0x34, 0x4f, 0xc3, 0x14, 0xfd, 0x17, 0x9c, 0x39, 0x23, 0xa3, 0x20, 0xa6, 0x9c, 0x9c, 0x71,
+	0x89, 0x53, 0x1b, 0xc3, 0x9c, 0x91, 0x81, 0xd2, 0xa0, 0x3b, 0xd0, 0xe2, 0x64, 0x46, 0x44, 0xb5,
+	0x9f, 0x4b, 0xa8, 0xda, 0xb8, 0x50, 0x94, 0x23, 0x60, 0x57, 0x22, 0x80, 0x36, 0xc0, 0x9e, 0x10,
+	0x4a, 0x24, 0x44, 0x5a, 0xd2, 0x2c, 0x97, 0xbd, 0x5f, 0x6a, 0x60, 0xbf, 0x9a, 0x73, 0x5f, 0x96,
+	0xf0, 0x13, 0x70, 0x18, 0xe1, 0xa3, 0x2c, 0x21, 0x86, 0x8c, 0xbb, 0xab, 0xe3, 0x7e, 0x21, 0xa3,
+	0xcf, 0xaf, 0x61, 0x60, 0xb9, 0x12, 0x1d, 0xc1, 0xba, 0x30, 0x96, 0x01, 0x1c, 0x25, 0x79, 0xa0,
+	0x65, 0x0c, 0x9d, 0x9d, 0xcd, 0xe2, 0x96, 0xe5, 0x99, 0x78, 0x7e, 0x0d, 0x23, 0x76, 0x61, 0x13,
+	0xed, 0x80, 0x2d, 0x6e, 0x95, 0x6c, 0xae, 0xea, 0xe0, 0x46, 0x71, 0x93, 0x60, 0xd8, 0xc2, 0xbc,
+	0xc9, 0x94, 0x06, 0x7d, 0x01, 0x6d, 0x61, 0x93, 0xc4, 0x2c, 0xe4, 0x59, 0x41, 0x38, 0x3b, 0xb7,
+	0x0a, 0xbb, 0x03, 0xbd, 0x53, 0xd8, 0x3a, 0xac, 0xd0, 0xee, 0x01, 0xd8, 0x91, 0x0e, 0x89, 0x17,
+	0x41, 0x7b, 0xcf, 0xe7, 0xc1, 0x74, 0x75, 0xbd, 0x6c, 0x41, 0x2b, 0xb3, 0xc8, 0x50, 0x78, 0x5d,
+	0x3f, 0x97, 0x05, 0x17, 0x17, 0x27, 0xca, 0xa9, 0xaa, 0x57, 0x8b, 0xf5, 0x37, 0x03, 0x7a, 0x3a,
+	0xa0, 0x47, 0x79, 0x66, 0x97, 0x75, 0x0f, 0x04, 0x26, 0xcb, 0x5a, 0x87, 0x89, 0xe5, 0x5a, 0xe4,
+	0x39, 0x25, 0x01, 0x09, 0xdf, 0xeb, 0xc6, 0x61, 0xe2, 0x5c, 0x16, 0x28, 0x9d, 0x91, 0x0c, 0x1b,
+	0x62, 0x99, 0x35, 0x36, 0xab, 0x68, 0x6c, 0x2e, 0x34, 0x8f, 0x67, 0x71, 0x70, 0x4a, 0xc6, 0xba,
+	0xf8, 0x32, 0x51, 0x50, 0x50, 0x30, 0x8b, 0x19, 0x19, 0xeb, 0xb2, 0xd3, 0x92, 0x77, 0x0a, 0x1d,
+	0x11, 0xf1, 0xd5, 0xae, 0x8a, 0x76, 0x30, 0xa7, 0x34, 0xa4, 0x13, 0xb7, 0xa6, 0x1b, 0x85, 0x12,
+	0x85, 0xc3, 0x27, 0x21, 0x0d, 0xd9, 0x34, 0xef, 0x74, 0xb9, 0x9c, 0xd1, 0x8a, 0x59, 0xd0, 0xca,
+	0x0c, 0x5a, 0xc5, 0x43, 0x8f, 0xc1, 0xd6, 0x65, 0xca, 0x5c, 0x43, 0x06, 0xfc, 0xdf, 0x55, 0x7e,
+	0xc8, 0x8f, 0xe2, 0xfc, 0x20, 0xfa, 0x00, 0x2c, 0x51, 0x48, 0x59, 0x8a, 0xd6, 0x4b, 0xad, 0xbb,
+	0x38, 0xae, 0x8e, 0x78, 0xf7, 0xc0, 0x79, 0x4b, 0xc7, 0xf1, 0xca, 0xbc, 0x7b, 0x03, 0x58, 0xfb,
+	0x4a, 0x54, 0x87, 0xac, 0xda, 0x2b, 0x89, 0x2e, 0xcb, 0x79, 0xad, 0x9a, 0xf3, 0x2d, 0xb8, 0x21,
+	0x2f, 0x29, 0x5c, 0x58, 0xf9, 0xe6, 0x3d, 0x70, 0x30, 0xb9, 0xca, 0xb1, 0xef, 0xa1, 0x5b, 0xc5,
+	0xc7, 0x0a, 0xa2, 0x13, 0xd0, 0xaa, 0x95, 0x06, 0xa5, 0xff, 0x2f, 0x10, 0xef, 0x5a, 0x29, 0x4c,
+	0x7f, 0x9b, 0x75, 0x39, 0xa0, 0x8b, 0x40, 0xfb, 0x07, 0x4e, 0xc8, 0xc9, 0xa3, 0x5e, 0x99, 0x3c,
+	0xcc, 0xd2, 0xe4, 0x91, 0xbd, 0x6a, 0x55, 0x5f, 0xfd, 0xd3, 0x80, 0xeb, 0x8b, 0x0c, 0xb2, 0xac,
+	0x24, 0x17, 0xb9, 0xbc, 0x76, 0x15, 0x97, 0xd7, 0x57, 0x73, 0xb9, 0x79, 0x05, 0x97, 0x5b, 0xab,
+	0xb9, 0xbc, 0xb1, 0xc8, 0xe5, 0x65, 0xc6, 0x6e, 0x2e, 0x30, 0xf6, 0x4b, 0x3d, 0x59, 0x4e, 0x7d,
+	0x3a, 0x21, 0x4b, 0xbf, 0xae, 0x48, 0x60, 0xed, 0x8a, 0x04, 0x7a, 0x5f, 0x16, 0x23, 0xf0, 0xe5,
+	0xf7, 0x3d, 0x58, 0xb8, 0x6f, 0x65, 0x27, 0xf6, 0xfe, 0x30, 0xc0, 0x91, 0x39, 0xd0, 0x37, 0x96,
+	0xb2, 0x65, 0x54, 0xfb, 0xd2, 0xc7, 0x00, 0x17, 0xfa, 0xc4, 0x4d, 0x7d, 0xf7, 0x62, 0x93, 0x28,
+	0x9d, 0x44, 0xf7, 0x33, 0x18, 0xab, 0x09, 0xbc, 0xf2, 0x79, 0xf2, 0x4d, 0x8d, 0x61, 0xb4, 0x5d,
+	0x22, 0x09, 0xb3, 0x02, 0xf9, 0xca, 0x47, 0x17, 0x0c, 0xe1, 0xf5, 0xa1, 0x3d, 0x1c, 0x87, 0x3c,
+	0x9f, 0xbd, 0x2e, 0x75, 0xde, 0xfb, 0xdd, 0x80, 0x76, 0x79, 0xc2, 0x12, 0x91, 0x3b, 0x09, 0x67,
+	0x79, 0xe4, 0xc4, 0x5a, 0xe8, 0x66, 0x21, 0x55, 0x95, 0xdd, 0xc1, 0x72, 0x2d, 0xb9, 0x34, 0x9e,
+	0xcd, 0x23, 0xc5, 0xfd, 0x1d, 0xac, 0x25, 0xf1, 0x54, 0x44, 0x18, 0xf3, 0x27, 0x44, 0x97, 0x53,
+	0x26, 0xe6, 0xf8, 0xb0, 0x4a, 0xf8, 0x70, 0xa1, 0xc9, 0x88, 0x9a, 0x56, 0x1b, 0xea, 0xb4, 0x16,
+	0x45, 0x6d, 0xeb, 0xe5, 0x48, 0xbe, 0xdd, 0x54, 0x33, 0xb6, 0xd6, 0xed, 0x87, 0x94, 0xec, 0xfc,
+	0x60, 0x01, 0x1c, 0xea, 0xff, 0x53, 0xcf, 0x62, 0xf4, 0x49, 0x3e, 0xc3, 0xae, 0x2f, 0x1b, 0x79,
+	0x37, 0x6e, 0x2c, 0x68, 0x55, 0x6c, 0xb6, 0x0d, 0xf4, 0x21, 0x58, 0xb2, 0x39, 0xa2, 0x6c, 0xe4,
+	0x2c, 0xb7, 0xca, 0x8d, 0x4c, 0x59, 0x09, 0xe8, 0x23, 0x30, 0x05, 0x7b, 0x21, 0xa4, 0x37, 0x4b,
+	0x54, 0xb6, 0xdc, 0xe0, 0x7f, 0x50, 0xc7, 0x73, 0x8a, 0xda, 0x7a, 0x4f, 0x4e, 0xad, 0x1b, 0x1d,
+	0x2d, 0xa9, 0xa1, 0xb4, 0x6f, 0x6c, 0x1b, 0xe8, 0x09, 0x40, 0x31, 0xa1, 0xa0, 0x4b, 0x87, 0x96,
+	0xe5, 0x8f, 0xbc, 0x94, 0x6c, 0xb5, 0xc8, 0x1c, 0x57, 0xce, 0x2c, 0xcb, 0x2f, 0xfb, 0x08, 0x9a,
+	0x9a, 0x7b, 0xd1, 0xf2, 0x59, 0x65, 0xb9, 0xd9, 0xe7, 0xe0, 0x94, 0x18, 0x13, 0x5d, 0x3e, 0xae,
+	0x5c, 0x1a, 0xd8, 0xb7, 0xb4, 0x14, 0xd8, 0x52, 0xf3, 0x5a, 0x6e, 0xf0, 0x19, 0x40, 0xd1, 0xbb,
+	0xf2, 0x80, 0x5d, 0x68, 0x67, 0x1b, 0xa8, 0x8c, 0x48, 0x05, 0x98, 0x6d, 0x03, 0xed, 0x41, 0xb7,
+	0xda, 0xb4, 0xd0, 0x9d, 0xf2, 0x0d, 0x8b, 0xbd, 0x6c, 0xa3, 0xa7, 0x77, 0xf3, 0x8d, 0x6d, 0xe3,
+	0xb8, 0x21, 0x55, 0x8f, 0xff, 0x1a, 0x00, 0xa2, 0xea, 0xcf, 0x16, 0xdc, 0x0f, 0x00, 0x00,
 }
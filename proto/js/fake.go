@@ -31,27 +31,52 @@ func (UnimplementedShenzhenGoClient) Action(ctx context.Context, in *ActionReque
 	return nil, nil
 }
 
+// Batch does nothing and returns nil, nil.
+func (UnimplementedShenzhenGoClient) Batch(ctx context.Context, in *BatchRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
+	return nil, nil
+}
+
+// Redo does nothing and returns nil, nil.
+func (UnimplementedShenzhenGoClient) Redo(ctx context.Context, in *RedoRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
+	return nil, nil
+}
+
 // Run does nothing and returns nil, nil.
 func (UnimplementedShenzhenGoClient) Run(ctx context.Context, opts ...grpcweb.CallOption) (ShenzhenGo_RunClient, error) {
 	return nil, nil
 }
 
 // SetChannel does nothing and returns nil, nil.
-func (UnimplementedShenzhenGoClient) SetChannel(ctx context.Context, in *SetChannelRequest, opts ...grpcweb.CallOption) (*Empty, error) {
+func (UnimplementedShenzhenGoClient) SetChannel(ctx context.Context, in *SetChannelRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
 	return nil, nil
 }
 
 // SetGraphProperties does nothing and returns nil, nil.
-func (UnimplementedShenzhenGoClient) SetGraphProperties(ctx context.Context, in *SetGraphPropertiesRequest, opts ...grpcweb.CallOption) (*Empty, error) {
+func (UnimplementedShenzhenGoClient) SetGraphProperties(ctx context.Context, in *SetGraphPropertiesRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
 	return nil, nil
 }
 
 // SetNode does nothing and returns nil, nil.
-func (UnimplementedShenzhenGoClient) SetNode(ctx context.Context, in *SetNodeRequest, opts ...grpcweb.CallOption) (*Empty, error) {
+func (UnimplementedShenzhenGoClient) SetNode(ctx context.Context, in *SetNodeRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
 	return nil, nil
 }
 
 // SetPosition does nothing and returns nil, nil.
-func (UnimplementedShenzhenGoClient) SetPosition(ctx context.Context, in *SetPositionRequest, opts ...grpcweb.CallOption) (*Empty, error) {
+func (UnimplementedShenzhenGoClient) SetPosition(ctx context.Context, in *SetPositionRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
+	return nil, nil
+}
+
+// Undo does nothing and returns nil, nil.
+func (UnimplementedShenzhenGoClient) Undo(ctx context.Context, in *UndoRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
+	return nil, nil
+}
+
+// WatchGraph does nothing and returns nil, nil.
+func (UnimplementedShenzhenGoClient) WatchGraph(ctx context.Context, in *WatchGraphRequest, opts ...grpcweb.CallOption) (ShenzhenGo_WatchGraphClient, error) {
+	return nil, nil
+}
+
+// WatchTelemetry does nothing and returns nil, nil.
+func (UnimplementedShenzhenGoClient) WatchTelemetry(ctx context.Context, in *WatchTelemetryRequest, opts ...grpcweb.CallOption) (ShenzhenGo_WatchTelemetryClient, error) {
 	return nil, nil
 }
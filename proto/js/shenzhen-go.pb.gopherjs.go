@@ -18,8 +18,23 @@
 		Output
 		SetChannelRequest
 		SetGraphPropertiesRequest
+		Mutation
+		BatchRequest
+		ChannelTelemetry
+		NodeTelemetry
+		Telemetry
+		UndoRequest
+		WatchGraphRequest
+		WatchTelemetryRequest
+		RedoRequest
 		SetNodeRequest
 		SetPositionRequest
+		GraphProperties
+		NodeChange
+		ChannelChange
+		GraphChange
+		EditResponse
+		CompileError
 */
 package proto
 
@@ -43,6 +58,7 @@ const (
 	ActionRequest_GENERATE ActionRequest_Action = 2
 	ActionRequest_BUILD    ActionRequest_Action = 3
 	ActionRequest_INSTALL  ActionRequest_Action = 4
+	ActionRequest_CHECK    ActionRequest_Action = 5
 )
 
 var ActionRequest_Action_name = map[int]string{
@@ -51,6 +67,7 @@ var ActionRequest_Action_name = map[int]string{
 	2: "GENERATE",
 	3: "BUILD",
 	4: "INSTALL",
+	5: "CHECK",
 }
 var ActionRequest_Action_value = map[string]int{
 	"SAVE":     0,
@@ -58,6 +75,7 @@ var ActionRequest_Action_value = map[string]int{
 	"GENERATE": 2,
 	"BUILD":    3,
 	"INSTALL":  4,
+	"CHECK":    5,
 }
 
 func (x ActionRequest_Action) String() string {
@@ -288,15 +306,20 @@ func (m *ChannelConfig) Unmarshal(rawBytes []byte) (*ChannelConfig, error) {
 }
 
 type NodeConfig struct {
-	Name         string
-	Comment      string
-	Enabled      bool
-	Multiplicity string
-	Wait         bool
-	PartCfg      []byte
-	PartType     string
-	X            float64
-	Y            float64
+	Name           string
+	Comment        string
+	Enabled        bool
+	Multiplicity   string
+	Wait           bool
+	PartCfg        []byte
+	PartType       string
+	X              float64
+	Y              float64
+	Recover        bool
+	Restart        string
+	MaxRestarts    uint32
+	RestartBackoff string
+	RestartMetrics bool
 }
 
 // GetName gets the Name of the NodeConfig.
@@ -371,6 +394,46 @@ func (m *NodeConfig) GetY() (x float64) {
 	return m.Y
 }
 
+// GetRecover gets the Recover of the NodeConfig.
+func (m *NodeConfig) GetRecover() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.Recover
+}
+
+// GetRestart gets the Restart of the NodeConfig.
+func (m *NodeConfig) GetRestart() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Restart
+}
+
+// GetMaxRestarts gets the MaxRestarts of the NodeConfig.
+func (m *NodeConfig) GetMaxRestarts() (x uint32) {
+	if m == nil {
+		return x
+	}
+	return m.MaxRestarts
+}
+
+// GetRestartBackoff gets the RestartBackoff of the NodeConfig.
+func (m *NodeConfig) GetRestartBackoff() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.RestartBackoff
+}
+
+// GetRestartMetrics gets the RestartMetrics of the NodeConfig.
+func (m *NodeConfig) GetRestartMetrics() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.RestartMetrics
+}
+
 // MarshalToWriter marshals NodeConfig to the provided writer.
 func (m *NodeConfig) MarshalToWriter(writer jspb.Writer) {
 	if m == nil {
@@ -413,6 +476,26 @@ func (m *NodeConfig) MarshalToWriter(writer jspb.Writer) {
 		writer.WriteFloat64(9, m.Y)
 	}
 
+	if m.Recover {
+		writer.WriteBool(10, m.Recover)
+	}
+
+	if len(m.Restart) > 0 {
+		writer.WriteString(11, m.Restart)
+	}
+
+	if m.MaxRestarts != 0 {
+		writer.WriteUint32(12, m.MaxRestarts)
+	}
+
+	if len(m.RestartBackoff) > 0 {
+		writer.WriteString(13, m.RestartBackoff)
+	}
+
+	if m.RestartMetrics {
+		writer.WriteBool(14, m.RestartMetrics)
+	}
+
 	return
 }
 
@@ -449,6 +532,16 @@ func (m *NodeConfig) UnmarshalFromReader(reader jspb.Reader) *NodeConfig {
 			m.X = reader.ReadFloat64()
 		case 9:
 			m.Y = reader.ReadFloat64()
+		case 10:
+			m.Recover = reader.ReadBool()
+		case 11:
+			m.Restart = reader.ReadString()
+		case 12:
+			m.MaxRestarts = reader.ReadUint32()
+		case 13:
+			m.RestartBackoff = reader.ReadString()
+		case 14:
+			m.RestartMetrics = reader.ReadBool()
 		default:
 			reader.SkipField()
 		}
@@ -549,7 +642,8 @@ func (m *ActionRequest) Unmarshal(rawBytes []byte) (*ActionRequest, error) {
 }
 
 type ActionResponse struct {
-	Output string
+	Output        string
+	CompileErrors []*CompileError
 }
 
 // GetOutput gets the Output of the ActionResponse.
@@ -560,6 +654,14 @@ func (m *ActionResponse) GetOutput() (x string) {
 	return m.Output
 }
 
+// GetCompileErrors gets the CompileErrors of the ActionResponse.
+func (m *ActionResponse) GetCompileErrors() (x []*CompileError) {
+	if m == nil {
+		return x
+	}
+	return m.CompileErrors
+}
+
 // MarshalToWriter marshals ActionResponse to the provided writer.
 func (m *ActionResponse) MarshalToWriter(writer jspb.Writer) {
 	if m == nil {
@@ -570,6 +672,12 @@ func (m *ActionResponse) MarshalToWriter(writer jspb.Writer) {
 		writer.WriteString(1, m.Output)
 	}
 
+	for _, msg := range m.CompileErrors {
+		writer.WriteMessage(2, func() {
+			msg.MarshalToWriter(writer)
+		})
+	}
+
 	return
 }
 
@@ -590,6 +698,10 @@ func (m *ActionResponse) UnmarshalFromReader(reader jspb.Reader) *ActionResponse
 		switch reader.GetFieldNumber() {
 		case 1:
 			m.Output = reader.ReadString()
+		case 2:
+			reader.ReadMessage(func() {
+				m.CompileErrors = append(m.CompileErrors, new(CompileError).UnmarshalFromReader(reader))
+			})
 		default:
 			reader.SkipField()
 		}
@@ -771,6 +883,7 @@ type SetChannelRequest struct {
 	Graph   string
 	Channel string
 	Config  *ChannelConfig
+	Version uint64
 }
 
 // GetGraph gets the Graph of the SetChannelRequest.
@@ -797,6 +910,14 @@ func (m *SetChannelRequest) GetConfig() (x *ChannelConfig) {
 	return m.Config
 }
 
+// GetVersion gets the Version of the SetChannelRequest.
+func (m *SetChannelRequest) GetVersion() (x uint64) {
+	if m == nil {
+		return x
+	}
+	return m.Version
+}
+
 // MarshalToWriter marshals SetChannelRequest to the provided writer.
 func (m *SetChannelRequest) MarshalToWriter(writer jspb.Writer) {
 	if m == nil {
@@ -817,6 +938,10 @@ func (m *SetChannelRequest) MarshalToWriter(writer jspb.Writer) {
 		})
 	}
 
+	if m.Version != 0 {
+		writer.WriteUint64(4, m.Version)
+	}
+
 	return
 }
 
@@ -843,6 +968,8 @@ func (m *SetChannelRequest) UnmarshalFromReader(reader jspb.Reader) *SetChannelR
 			reader.ReadMessage(func() {
 				m.Config = m.Config.UnmarshalFromReader(reader)
 			})
+		case 4:
+			m.Version = reader.ReadUint64()
 		default:
 			reader.SkipField()
 		}
@@ -869,6 +996,11 @@ type SetGraphPropertiesRequest struct {
 	Name        string
 	PackagePath string
 	IsCommand   bool
+	OutputDir   string
+	UseContext  bool
+	Telemetry   bool
+	Version     uint64
+	Generics    bool
 }
 
 // GetGraph gets the Graph of the SetGraphPropertiesRequest.
@@ -903,6 +1035,46 @@ func (m *SetGraphPropertiesRequest) GetIsCommand() (x bool) {
 	return m.IsCommand
 }
 
+// GetOutputDir gets the OutputDir of the SetGraphPropertiesRequest.
+func (m *SetGraphPropertiesRequest) GetOutputDir() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.OutputDir
+}
+
+// GetUseContext gets the UseContext of the SetGraphPropertiesRequest.
+func (m *SetGraphPropertiesRequest) GetUseContext() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.UseContext
+}
+
+// GetTelemetry gets the Telemetry of the SetGraphPropertiesRequest.
+func (m *SetGraphPropertiesRequest) GetTelemetry() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.Telemetry
+}
+
+// GetVersion gets the Version of the SetGraphPropertiesRequest.
+func (m *SetGraphPropertiesRequest) GetVersion() (x uint64) {
+	if m == nil {
+		return x
+	}
+	return m.Version
+}
+
+// GetGenerics gets the Generics of the SetGraphPropertiesRequest.
+func (m *SetGraphPropertiesRequest) GetGenerics() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.Generics
+}
+
 // MarshalToWriter marshals SetGraphPropertiesRequest to the provided writer.
 func (m *SetGraphPropertiesRequest) MarshalToWriter(writer jspb.Writer) {
 	if m == nil {
@@ -925,6 +1097,26 @@ func (m *SetGraphPropertiesRequest) MarshalToWriter(writer jspb.Writer) {
 		writer.WriteBool(4, m.IsCommand)
 	}
 
+	if len(m.OutputDir) > 0 {
+		writer.WriteString(5, m.OutputDir)
+	}
+
+	if m.UseContext {
+		writer.WriteBool(6, m.UseContext)
+	}
+
+	if m.Telemetry {
+		writer.WriteBool(7, m.Telemetry)
+	}
+
+	if m.Version != 0 {
+		writer.WriteUint64(8, m.Version)
+	}
+
+	if m.Generics {
+		writer.WriteBool(9, m.Generics)
+	}
+
 	return
 }
 
@@ -951,6 +1143,16 @@ func (m *SetGraphPropertiesRequest) UnmarshalFromReader(reader jspb.Reader) *Set
 			m.PackagePath = reader.ReadString()
 		case 4:
 			m.IsCommand = reader.ReadBool()
+		case 5:
+			m.OutputDir = reader.ReadString()
+		case 6:
+			m.UseContext = reader.ReadBool()
+		case 7:
+			m.Telemetry = reader.ReadBool()
+		case 8:
+			m.Version = reader.ReadUint64()
+		case 9:
+			m.Generics = reader.ReadBool()
 		default:
 			reader.SkipField()
 		}
@@ -972,81 +1174,157 @@ func (m *SetGraphPropertiesRequest) Unmarshal(rawBytes []byte) (*SetGraphPropert
 	return m, nil
 }
 
-type SetNodeRequest struct {
-	Graph  string
-	Node   string
-	Config *NodeConfig
+type Mutation struct {
+	// Types that are valid to be assigned to Mutation:
+	//	*Mutation_SetChannel
+	//	*Mutation_SetGraphProperties
+	//	*Mutation_SetNode
+	//	*Mutation_SetPosition
+	Mutation isMutation_Mutation
 }
 
-// GetGraph gets the Graph of the SetNodeRequest.
-func (m *SetNodeRequest) GetGraph() (x string) {
+// isMutation_Mutation is used to distinguish types assignable to Mutation
+type isMutation_Mutation interface{ isMutation_Mutation() }
+
+// Mutation_SetChannel is assignable to Mutation
+type Mutation_SetChannel struct {
+	SetChannel *SetChannelRequest
+}
+
+// Mutation_SetGraphProperties is assignable to Mutation
+type Mutation_SetGraphProperties struct {
+	SetGraphProperties *SetGraphPropertiesRequest
+}
+
+// Mutation_SetNode is assignable to Mutation
+type Mutation_SetNode struct {
+	SetNode *SetNodeRequest
+}
+
+// Mutation_SetPosition is assignable to Mutation
+type Mutation_SetPosition struct {
+	SetPosition *SetPositionRequest
+}
+
+func (*Mutation_SetChannel) isMutation_Mutation()         {}
+func (*Mutation_SetGraphProperties) isMutation_Mutation() {}
+func (*Mutation_SetNode) isMutation_Mutation()            {}
+func (*Mutation_SetPosition) isMutation_Mutation()        {}
+
+// GetMutation gets the Mutation of the Mutation.
+func (m *Mutation) GetMutation() (x isMutation_Mutation) {
 	if m == nil {
 		return x
 	}
-	return m.Graph
+	return m.Mutation
 }
 
-// GetNode gets the Node of the SetNodeRequest.
-func (m *SetNodeRequest) GetNode() (x string) {
-	if m == nil {
-		return x
+// GetSetChannel gets the SetChannel of the Mutation.
+func (m *Mutation) GetSetChannel() (x *SetChannelRequest) {
+	if v, ok := m.GetMutation().(*Mutation_SetChannel); ok {
+		return v.SetChannel
 	}
-	return m.Node
+	return x
 }
 
-// GetConfig gets the Config of the SetNodeRequest.
-func (m *SetNodeRequest) GetConfig() (x *NodeConfig) {
-	if m == nil {
-		return x
+// GetSetGraphProperties gets the SetGraphProperties of the Mutation.
+func (m *Mutation) GetSetGraphProperties() (x *SetGraphPropertiesRequest) {
+	if v, ok := m.GetMutation().(*Mutation_SetGraphProperties); ok {
+		return v.SetGraphProperties
 	}
-	return m.Config
+	return x
 }
 
-// MarshalToWriter marshals SetNodeRequest to the provided writer.
-func (m *SetNodeRequest) MarshalToWriter(writer jspb.Writer) {
-	if m == nil {
-		return
+// GetSetNode gets the SetNode of the Mutation.
+func (m *Mutation) GetSetNode() (x *SetNodeRequest) {
+	if v, ok := m.GetMutation().(*Mutation_SetNode); ok {
+		return v.SetNode
 	}
+	return x
+}
 
-	if len(m.Graph) > 0 {
-		writer.WriteString(1, m.Graph)
+// GetSetPosition gets the SetPosition of the Mutation.
+func (m *Mutation) GetSetPosition() (x *SetPositionRequest) {
+	if v, ok := m.GetMutation().(*Mutation_SetPosition); ok {
+		return v.SetPosition
 	}
+	return x
+}
 
-	if len(m.Node) > 0 {
-		writer.WriteString(2, m.Node)
+// MarshalToWriter marshals Mutation to the provided writer.
+func (m *Mutation) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
 	}
 
-	if m.Config != nil {
-		writer.WriteMessage(3, func() {
-			m.Config.MarshalToWriter(writer)
-		})
+	switch t := m.Mutation.(type) {
+	case *Mutation_SetChannel:
+		if t.SetChannel != nil {
+			writer.WriteMessage(1, func() {
+				t.SetChannel.MarshalToWriter(writer)
+			})
+		}
+	case *Mutation_SetGraphProperties:
+		if t.SetGraphProperties != nil {
+			writer.WriteMessage(2, func() {
+				t.SetGraphProperties.MarshalToWriter(writer)
+			})
+		}
+	case *Mutation_SetNode:
+		if t.SetNode != nil {
+			writer.WriteMessage(3, func() {
+				t.SetNode.MarshalToWriter(writer)
+			})
+		}
+	case *Mutation_SetPosition:
+		if t.SetPosition != nil {
+			writer.WriteMessage(4, func() {
+				t.SetPosition.MarshalToWriter(writer)
+			})
+		}
 	}
 
 	return
 }
 
-// Marshal marshals SetNodeRequest to a slice of bytes.
-func (m *SetNodeRequest) Marshal() []byte {
+// Marshal marshals Mutation to a slice of bytes.
+func (m *Mutation) Marshal() []byte {
 	writer := jspb.NewWriter()
 	m.MarshalToWriter(writer)
 	return writer.GetResult()
 }
 
-// UnmarshalFromReader unmarshals a SetNodeRequest from the provided reader.
-func (m *SetNodeRequest) UnmarshalFromReader(reader jspb.Reader) *SetNodeRequest {
+// UnmarshalFromReader unmarshals a Mutation from the provided reader.
+func (m *Mutation) UnmarshalFromReader(reader jspb.Reader) *Mutation {
 	for reader.Next() {
 		if m == nil {
-			m = &SetNodeRequest{}
+			m = &Mutation{}
 		}
 
 		switch reader.GetFieldNumber() {
 		case 1:
-			m.Graph = reader.ReadString()
+			reader.ReadMessage(func() {
+				m.Mutation = &Mutation_SetChannel{
+					SetChannel: new(SetChannelRequest).UnmarshalFromReader(reader),
+				}
+			})
 		case 2:
-			m.Node = reader.ReadString()
+			reader.ReadMessage(func() {
+				m.Mutation = &Mutation_SetGraphProperties{
+					SetGraphProperties: new(SetGraphPropertiesRequest).UnmarshalFromReader(reader),
+				}
+			})
 		case 3:
 			reader.ReadMessage(func() {
-				m.Config = m.Config.UnmarshalFromReader(reader)
+				m.Mutation = &Mutation_SetNode{
+					SetNode: new(SetNodeRequest).UnmarshalFromReader(reader),
+				}
+			})
+		case 4:
+			reader.ReadMessage(func() {
+				m.Mutation = &Mutation_SetPosition{
+					SetPosition: new(SetPositionRequest).UnmarshalFromReader(reader),
+				}
 			})
 		default:
 			reader.SkipField()
@@ -1056,8 +1334,8 @@ func (m *SetNodeRequest) UnmarshalFromReader(reader jspb.Reader) *SetNodeRequest
 	return m
 }
 
-// Unmarshal unmarshals a SetNodeRequest from a slice of bytes.
-func (m *SetNodeRequest) Unmarshal(rawBytes []byte) (*SetNodeRequest, error) {
+// Unmarshal unmarshals a Mutation from a slice of bytes.
+func (m *Mutation) Unmarshal(rawBytes []byte) (*Mutation, error) {
 	reader := jspb.NewReader(rawBytes)
 
 	m = m.UnmarshalFromReader(reader)
@@ -1069,47 +1347,38 @@ func (m *SetNodeRequest) Unmarshal(rawBytes []byte) (*SetNodeRequest, error) {
 	return m, nil
 }
 
-type SetPositionRequest struct {
-	Graph string
-	Node  string
-	X     float64
-	Y     float64
+type BatchRequest struct {
+	Graph     string
+	Mutations []*Mutation
+	Version   uint64
 }
 
-// GetGraph gets the Graph of the SetPositionRequest.
-func (m *SetPositionRequest) GetGraph() (x string) {
+// GetGraph gets the Graph of the BatchRequest.
+func (m *BatchRequest) GetGraph() (x string) {
 	if m == nil {
 		return x
 	}
 	return m.Graph
 }
 
-// GetNode gets the Node of the SetPositionRequest.
-func (m *SetPositionRequest) GetNode() (x string) {
-	if m == nil {
-		return x
-	}
-	return m.Node
-}
-
-// GetX gets the X of the SetPositionRequest.
-func (m *SetPositionRequest) GetX() (x float64) {
+// GetMutations gets the Mutations of the BatchRequest.
+func (m *BatchRequest) GetMutations() (x []*Mutation) {
 	if m == nil {
 		return x
 	}
-	return m.X
+	return m.Mutations
 }
 
-// GetY gets the Y of the SetPositionRequest.
-func (m *SetPositionRequest) GetY() (x float64) {
+// GetVersion gets the Version of the BatchRequest.
+func (m *BatchRequest) GetVersion() (x uint64) {
 	if m == nil {
 		return x
 	}
-	return m.Y
+	return m.Version
 }
 
-// MarshalToWriter marshals SetPositionRequest to the provided writer.
-func (m *SetPositionRequest) MarshalToWriter(writer jspb.Writer) {
+// MarshalToWriter marshals BatchRequest to the provided writer.
+func (m *BatchRequest) MarshalToWriter(writer jspb.Writer) {
 	if m == nil {
 		return
 	}
@@ -1118,44 +1387,42 @@ func (m *SetPositionRequest) MarshalToWriter(writer jspb.Writer) {
 		writer.WriteString(1, m.Graph)
 	}
 
-	if len(m.Node) > 0 {
-		writer.WriteString(2, m.Node)
-	}
-
-	if m.X != 0 {
-		writer.WriteFloat64(3, m.X)
+	for _, msg := range m.Mutations {
+		writer.WriteMessage(2, func() {
+			msg.MarshalToWriter(writer)
+		})
 	}
 
-	if m.Y != 0 {
-		writer.WriteFloat64(4, m.Y)
+	if m.Version != 0 {
+		writer.WriteUint64(3, m.Version)
 	}
 
 	return
 }
 
-// Marshal marshals SetPositionRequest to a slice of bytes.
-func (m *SetPositionRequest) Marshal() []byte {
+// Marshal marshals BatchRequest to a slice of bytes.
+func (m *BatchRequest) Marshal() []byte {
 	writer := jspb.NewWriter()
 	m.MarshalToWriter(writer)
 	return writer.GetResult()
 }
 
-// UnmarshalFromReader unmarshals a SetPositionRequest from the provided reader.
-func (m *SetPositionRequest) UnmarshalFromReader(reader jspb.Reader) *SetPositionRequest {
+// UnmarshalFromReader unmarshals a BatchRequest from the provided reader.
+func (m *BatchRequest) UnmarshalFromReader(reader jspb.Reader) *BatchRequest {
 	for reader.Next() {
 		if m == nil {
-			m = &SetPositionRequest{}
+			m = &BatchRequest{}
 		}
 
 		switch reader.GetFieldNumber() {
 		case 1:
 			m.Graph = reader.ReadString()
 		case 2:
-			m.Node = reader.ReadString()
+			reader.ReadMessage(func() {
+				m.Mutations = append(m.Mutations, new(Mutation).UnmarshalFromReader(reader))
+			})
 		case 3:
-			m.X = reader.ReadFloat64()
-		case 4:
-			m.Y = reader.ReadFloat64()
+			m.Version = reader.ReadUint64()
 		default:
 			reader.SkipField()
 		}
@@ -1164,8 +1431,8 @@ func (m *SetPositionRequest) UnmarshalFromReader(reader jspb.Reader) *SetPositio
 	return m
 }
 
-// Unmarshal unmarshals a SetPositionRequest from a slice of bytes.
-func (m *SetPositionRequest) Unmarshal(rawBytes []byte) (*SetPositionRequest, error) {
+// Unmarshal unmarshals a BatchRequest from a slice of bytes.
+func (m *BatchRequest) Unmarshal(rawBytes []byte) (*BatchRequest, error) {
 	reader := jspb.NewReader(rawBytes)
 
 	m = m.UnmarshalFromReader(reader)
@@ -1177,62 +1444,1578 @@ func (m *SetPositionRequest) Unmarshal(rawBytes []byte) (*SetPositionRequest, er
 	return m, nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpcweb.Client
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpcweb package it is being compiled against.
-const _ = grpcweb.GrpcWebPackageIsVersion3
-
-// Client API for ShenzhenGo service
-
-type ShenzhenGoClient interface {
-	// Action performs an action (save, generate, install/build, etc).
-	Action(ctx context.Context, in *ActionRequest, opts ...grpcweb.CallOption) (ShenzhenGo_ActionClient, error)
-	// Run runs the program.
-	Run(ctx context.Context, opts ...grpcweb.CallOption) (ShenzhenGo_RunClient, error)
-	// SetNode either creates a new channel (name == "", config != nil)
-	// changes existing channel data such as name and attached pins (name is found, config != nil),
-	// or deletes a channel (name is found, config == nil).
-	SetChannel(ctx context.Context, in *SetChannelRequest, opts ...grpcweb.CallOption) (*Empty, error)
-	// SetGraphProperties changes metdata such as name and package path.
-	SetGraphProperties(ctx context.Context, in *SetGraphPropertiesRequest, opts ...grpcweb.CallOption) (*Empty, error)
-	// SetNode either creates a new node (name == "", config != nil)
-	// changes existing node such as name and multiplicity (name is found, config != nil),
-	// or deletes a node (name is found, config == nil).
-	SetNode(ctx context.Context, in *SetNodeRequest, opts ...grpcweb.CallOption) (*Empty, error)
-	// SetPosition changes the node position in the diagram.
-	SetPosition(ctx context.Context, in *SetPositionRequest, opts ...grpcweb.CallOption) (*Empty, error)
+type ChannelTelemetry struct {
+	Name     string
+	Sent     uint64
+	Received uint64
+	Len      uint64
+	Cap      uint64
+	Blocked  bool
+	Closed   bool
 }
 
-type shenzhenGoClient struct {
-	client *grpcweb.Client
+// GetName gets the Name of the ChannelTelemetry.
+func (m *ChannelTelemetry) GetName() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Name
 }
 
-// NewShenzhenGoClient creates a new gRPC-Web client.
-func NewShenzhenGoClient(hostname string, opts ...grpcweb.DialOption) ShenzhenGoClient {
-	return &shenzhenGoClient{
-		client: grpcweb.NewClient(hostname, "proto.ShenzhenGo", opts...),
+// GetSent gets the Sent of the ChannelTelemetry.
+func (m *ChannelTelemetry) GetSent() (x uint64) {
+	if m == nil {
+		return x
 	}
+	return m.Sent
 }
 
-func (c *shenzhenGoClient) Action(ctx context.Context, in *ActionRequest, opts ...grpcweb.CallOption) (ShenzhenGo_ActionClient, error) {
-	srv, err := c.client.NewClientStream(ctx, false, true, "Action", opts...)
-	if err != nil {
-		return nil, err
+// GetReceived gets the Received of the ChannelTelemetry.
+func (m *ChannelTelemetry) GetReceived() (x uint64) {
+	if m == nil {
+		return x
 	}
+	return m.Received
+}
 
-	err = srv.SendMsg(in.Marshal())
-	if err != nil {
-		return nil, err
+// GetLen gets the Len of the ChannelTelemetry.
+func (m *ChannelTelemetry) GetLen() (x uint64) {
+	if m == nil {
+		return x
 	}
-
-	return &shenzhenGoActionClient{srv}, nil
+	return m.Len
 }
 
-type ShenzhenGo_ActionClient interface {
-	Recv() (*ActionResponse, error)
+// GetCap gets the Cap of the ChannelTelemetry.
+func (m *ChannelTelemetry) GetCap() (x uint64) {
+	if m == nil {
+		return x
+	}
+	return m.Cap
+}
+
+// GetBlocked gets the Blocked of the ChannelTelemetry.
+func (m *ChannelTelemetry) GetBlocked() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.Blocked
+}
+
+// GetClosed gets the Closed of the ChannelTelemetry.
+func (m *ChannelTelemetry) GetClosed() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.Closed
+}
+
+// MarshalToWriter marshals ChannelTelemetry to the provided writer.
+func (m *ChannelTelemetry) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if len(m.Name) > 0 {
+		writer.WriteString(1, m.Name)
+	}
+
+	if m.Sent != 0 {
+		writer.WriteUint64(2, m.Sent)
+	}
+
+	if m.Received != 0 {
+		writer.WriteUint64(3, m.Received)
+	}
+
+	if m.Len != 0 {
+		writer.WriteUint64(4, m.Len)
+	}
+
+	if m.Cap != 0 {
+		writer.WriteUint64(5, m.Cap)
+	}
+
+	if m.Blocked {
+		writer.WriteBool(6, m.Blocked)
+	}
+
+	if m.Closed {
+		writer.WriteBool(7, m.Closed)
+	}
+
+	return
+}
+
+// Marshal marshals ChannelTelemetry to a slice of bytes.
+func (m *ChannelTelemetry) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a ChannelTelemetry from the provided reader.
+func (m *ChannelTelemetry) UnmarshalFromReader(reader jspb.Reader) *ChannelTelemetry {
+	for reader.Next() {
+		if m == nil {
+			m = &ChannelTelemetry{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Name = reader.ReadString()
+		case 2:
+			m.Sent = reader.ReadUint64()
+		case 3:
+			m.Received = reader.ReadUint64()
+		case 4:
+			m.Len = reader.ReadUint64()
+		case 5:
+			m.Cap = reader.ReadUint64()
+		case 6:
+			m.Blocked = reader.ReadBool()
+		case 7:
+			m.Closed = reader.ReadBool()
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a ChannelTelemetry from a slice of bytes.
+func (m *ChannelTelemetry) Unmarshal(rawBytes []byte) (*ChannelTelemetry, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type NodeTelemetry struct {
+	Name     string
+	Running  bool
+	Finished bool
+	Err      string
+}
+
+// GetName gets the Name of the NodeTelemetry.
+func (m *NodeTelemetry) GetName() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Name
+}
+
+// GetRunning gets the Running of the NodeTelemetry.
+func (m *NodeTelemetry) GetRunning() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.Running
+}
+
+// GetFinished gets the Finished of the NodeTelemetry.
+func (m *NodeTelemetry) GetFinished() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.Finished
+}
+
+// GetErr gets the Err of the NodeTelemetry.
+func (m *NodeTelemetry) GetErr() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Err
+}
+
+// MarshalToWriter marshals NodeTelemetry to the provided writer.
+func (m *NodeTelemetry) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if len(m.Name) > 0 {
+		writer.WriteString(1, m.Name)
+	}
+
+	if m.Running {
+		writer.WriteBool(2, m.Running)
+	}
+
+	if m.Finished {
+		writer.WriteBool(3, m.Finished)
+	}
+
+	if len(m.Err) > 0 {
+		writer.WriteString(4, m.Err)
+	}
+
+	return
+}
+
+// Marshal marshals NodeTelemetry to a slice of bytes.
+func (m *NodeTelemetry) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a NodeTelemetry from the provided reader.
+func (m *NodeTelemetry) UnmarshalFromReader(reader jspb.Reader) *NodeTelemetry {
+	for reader.Next() {
+		if m == nil {
+			m = &NodeTelemetry{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Name = reader.ReadString()
+		case 2:
+			m.Running = reader.ReadBool()
+		case 3:
+			m.Finished = reader.ReadBool()
+		case 4:
+			m.Err = reader.ReadString()
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a NodeTelemetry from a slice of bytes.
+func (m *NodeTelemetry) Unmarshal(rawBytes []byte) (*NodeTelemetry, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type Telemetry struct {
+	Channels []*ChannelTelemetry
+	Nodes    []*NodeTelemetry
+}
+
+// GetChannels gets the Channels of the Telemetry.
+func (m *Telemetry) GetChannels() (x []*ChannelTelemetry) {
+	if m == nil {
+		return x
+	}
+	return m.Channels
+}
+
+// GetNodes gets the Nodes of the Telemetry.
+func (m *Telemetry) GetNodes() (x []*NodeTelemetry) {
+	if m == nil {
+		return x
+	}
+	return m.Nodes
+}
+
+// MarshalToWriter marshals Telemetry to the provided writer.
+func (m *Telemetry) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	for _, msg := range m.Channels {
+		writer.WriteMessage(1, func() {
+			msg.MarshalToWriter(writer)
+		})
+	}
+
+	for _, msg := range m.Nodes {
+		writer.WriteMessage(2, func() {
+			msg.MarshalToWriter(writer)
+		})
+	}
+
+	return
+}
+
+// Marshal marshals Telemetry to a slice of bytes.
+func (m *Telemetry) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a Telemetry from the provided reader.
+func (m *Telemetry) UnmarshalFromReader(reader jspb.Reader) *Telemetry {
+	for reader.Next() {
+		if m == nil {
+			m = &Telemetry{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			reader.ReadMessage(func() {
+				m.Channels = append(m.Channels, new(ChannelTelemetry).UnmarshalFromReader(reader))
+			})
+		case 2:
+			reader.ReadMessage(func() {
+				m.Nodes = append(m.Nodes, new(NodeTelemetry).UnmarshalFromReader(reader))
+			})
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a Telemetry from a slice of bytes.
+func (m *Telemetry) Unmarshal(rawBytes []byte) (*Telemetry, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type UndoRequest struct {
+	Graph string
+}
+
+// GetGraph gets the Graph of the UndoRequest.
+func (m *UndoRequest) GetGraph() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Graph
+}
+
+// MarshalToWriter marshals UndoRequest to the provided writer.
+func (m *UndoRequest) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if len(m.Graph) > 0 {
+		writer.WriteString(1, m.Graph)
+	}
+
+	return
+}
+
+// Marshal marshals UndoRequest to a slice of bytes.
+func (m *UndoRequest) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a UndoRequest from the provided reader.
+func (m *UndoRequest) UnmarshalFromReader(reader jspb.Reader) *UndoRequest {
+	for reader.Next() {
+		if m == nil {
+			m = &UndoRequest{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Graph = reader.ReadString()
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a UndoRequest from a slice of bytes.
+func (m *UndoRequest) Unmarshal(rawBytes []byte) (*UndoRequest, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type WatchGraphRequest struct {
+	Graph   string
+	Version uint64
+}
+
+// GetGraph gets the Graph of the WatchGraphRequest.
+func (m *WatchGraphRequest) GetGraph() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Graph
+}
+
+// GetVersion gets the Version of the WatchGraphRequest.
+func (m *WatchGraphRequest) GetVersion() (x uint64) {
+	if m == nil {
+		return x
+	}
+	return m.Version
+}
+
+// MarshalToWriter marshals WatchGraphRequest to the provided writer.
+func (m *WatchGraphRequest) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if len(m.Graph) > 0 {
+		writer.WriteString(1, m.Graph)
+	}
+
+	if m.Version != 0 {
+		writer.WriteUint64(2, m.Version)
+	}
+
+	return
+}
+
+// Marshal marshals WatchGraphRequest to a slice of bytes.
+func (m *WatchGraphRequest) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a WatchGraphRequest from the provided reader.
+func (m *WatchGraphRequest) UnmarshalFromReader(reader jspb.Reader) *WatchGraphRequest {
+	for reader.Next() {
+		if m == nil {
+			m = &WatchGraphRequest{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Graph = reader.ReadString()
+		case 2:
+			m.Version = reader.ReadUint64()
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a WatchGraphRequest from a slice of bytes.
+func (m *WatchGraphRequest) Unmarshal(rawBytes []byte) (*WatchGraphRequest, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type WatchTelemetryRequest struct {
+	Graph string
+}
+
+// GetGraph gets the Graph of the WatchTelemetryRequest.
+func (m *WatchTelemetryRequest) GetGraph() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Graph
+}
+
+// MarshalToWriter marshals WatchTelemetryRequest to the provided writer.
+func (m *WatchTelemetryRequest) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if len(m.Graph) > 0 {
+		writer.WriteString(1, m.Graph)
+	}
+
+	return
+}
+
+// Marshal marshals WatchTelemetryRequest to a slice of bytes.
+func (m *WatchTelemetryRequest) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a WatchTelemetryRequest from the provided reader.
+func (m *WatchTelemetryRequest) UnmarshalFromReader(reader jspb.Reader) *WatchTelemetryRequest {
+	for reader.Next() {
+		if m == nil {
+			m = &WatchTelemetryRequest{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Graph = reader.ReadString()
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a WatchTelemetryRequest from a slice of bytes.
+func (m *WatchTelemetryRequest) Unmarshal(rawBytes []byte) (*WatchTelemetryRequest, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type RedoRequest struct {
+	Graph string
+}
+
+// GetGraph gets the Graph of the RedoRequest.
+func (m *RedoRequest) GetGraph() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Graph
+}
+
+// MarshalToWriter marshals RedoRequest to the provided writer.
+func (m *RedoRequest) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if len(m.Graph) > 0 {
+		writer.WriteString(1, m.Graph)
+	}
+
+	return
+}
+
+// Marshal marshals RedoRequest to a slice of bytes.
+func (m *RedoRequest) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a RedoRequest from the provided reader.
+func (m *RedoRequest) UnmarshalFromReader(reader jspb.Reader) *RedoRequest {
+	for reader.Next() {
+		if m == nil {
+			m = &RedoRequest{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Graph = reader.ReadString()
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a RedoRequest from a slice of bytes.
+func (m *RedoRequest) Unmarshal(rawBytes []byte) (*RedoRequest, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type SetNodeRequest struct {
+	Graph   string
+	Node    string
+	Config  *NodeConfig
+	Version uint64
+}
+
+// GetGraph gets the Graph of the SetNodeRequest.
+func (m *SetNodeRequest) GetGraph() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Graph
+}
+
+// GetNode gets the Node of the SetNodeRequest.
+func (m *SetNodeRequest) GetNode() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Node
+}
+
+// GetConfig gets the Config of the SetNodeRequest.
+func (m *SetNodeRequest) GetConfig() (x *NodeConfig) {
+	if m == nil {
+		return x
+	}
+	return m.Config
+}
+
+// GetVersion gets the Version of the SetNodeRequest.
+func (m *SetNodeRequest) GetVersion() (x uint64) {
+	if m == nil {
+		return x
+	}
+	return m.Version
+}
+
+// MarshalToWriter marshals SetNodeRequest to the provided writer.
+func (m *SetNodeRequest) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if len(m.Graph) > 0 {
+		writer.WriteString(1, m.Graph)
+	}
+
+	if len(m.Node) > 0 {
+		writer.WriteString(2, m.Node)
+	}
+
+	if m.Config != nil {
+		writer.WriteMessage(3, func() {
+			m.Config.MarshalToWriter(writer)
+		})
+	}
+
+	if m.Version != 0 {
+		writer.WriteUint64(4, m.Version)
+	}
+
+	return
+}
+
+// Marshal marshals SetNodeRequest to a slice of bytes.
+func (m *SetNodeRequest) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a SetNodeRequest from the provided reader.
+func (m *SetNodeRequest) UnmarshalFromReader(reader jspb.Reader) *SetNodeRequest {
+	for reader.Next() {
+		if m == nil {
+			m = &SetNodeRequest{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Graph = reader.ReadString()
+		case 2:
+			m.Node = reader.ReadString()
+		case 3:
+			reader.ReadMessage(func() {
+				m.Config = m.Config.UnmarshalFromReader(reader)
+			})
+		case 4:
+			m.Version = reader.ReadUint64()
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a SetNodeRequest from a slice of bytes.
+func (m *SetNodeRequest) Unmarshal(rawBytes []byte) (*SetNodeRequest, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type SetPositionRequest struct {
+	Graph   string
+	Node    string
+	X       float64
+	Y       float64
+	Version uint64
+}
+
+// GetGraph gets the Graph of the SetPositionRequest.
+func (m *SetPositionRequest) GetGraph() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Graph
+}
+
+// GetNode gets the Node of the SetPositionRequest.
+func (m *SetPositionRequest) GetNode() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Node
+}
+
+// GetX gets the X of the SetPositionRequest.
+func (m *SetPositionRequest) GetX() (x float64) {
+	if m == nil {
+		return x
+	}
+	return m.X
+}
+
+// GetY gets the Y of the SetPositionRequest.
+func (m *SetPositionRequest) GetY() (x float64) {
+	if m == nil {
+		return x
+	}
+	return m.Y
+}
+
+// GetVersion gets the Version of the SetPositionRequest.
+func (m *SetPositionRequest) GetVersion() (x uint64) {
+	if m == nil {
+		return x
+	}
+	return m.Version
+}
+
+// MarshalToWriter marshals SetPositionRequest to the provided writer.
+func (m *SetPositionRequest) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if len(m.Graph) > 0 {
+		writer.WriteString(1, m.Graph)
+	}
+
+	if len(m.Node) > 0 {
+		writer.WriteString(2, m.Node)
+	}
+
+	if m.X != 0 {
+		writer.WriteFloat64(3, m.X)
+	}
+
+	if m.Y != 0 {
+		writer.WriteFloat64(4, m.Y)
+	}
+
+	if m.Version != 0 {
+		writer.WriteUint64(5, m.Version)
+	}
+
+	return
+}
+
+// Marshal marshals SetPositionRequest to a slice of bytes.
+func (m *SetPositionRequest) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a SetPositionRequest from the provided reader.
+func (m *SetPositionRequest) UnmarshalFromReader(reader jspb.Reader) *SetPositionRequest {
+	for reader.Next() {
+		if m == nil {
+			m = &SetPositionRequest{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Graph = reader.ReadString()
+		case 2:
+			m.Node = reader.ReadString()
+		case 3:
+			m.X = reader.ReadFloat64()
+		case 4:
+			m.Y = reader.ReadFloat64()
+		case 5:
+			m.Version = reader.ReadUint64()
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a SetPositionRequest from a slice of bytes.
+func (m *SetPositionRequest) Unmarshal(rawBytes []byte) (*SetPositionRequest, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type GraphProperties struct {
+	Name        string
+	PackagePath string
+	IsCommand   bool
+	OutputDir   string
+	UseContext  bool
+	Telemetry   bool
+	Generics    bool
+}
+
+// GetName gets the Name of the GraphProperties.
+func (m *GraphProperties) GetName() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Name
+}
+
+// GetPackagePath gets the PackagePath of the GraphProperties.
+func (m *GraphProperties) GetPackagePath() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.PackagePath
+}
+
+// GetIsCommand gets the IsCommand of the GraphProperties.
+func (m *GraphProperties) GetIsCommand() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.IsCommand
+}
+
+// GetOutputDir gets the OutputDir of the GraphProperties.
+func (m *GraphProperties) GetOutputDir() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.OutputDir
+}
+
+// GetUseContext gets the UseContext of the GraphProperties.
+func (m *GraphProperties) GetUseContext() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.UseContext
+}
+
+// GetTelemetry gets the Telemetry of the GraphProperties.
+func (m *GraphProperties) GetTelemetry() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.Telemetry
+}
+
+// GetGenerics gets the Generics of the GraphProperties.
+func (m *GraphProperties) GetGenerics() (x bool) {
+	if m == nil {
+		return x
+	}
+	return m.Generics
+}
+
+// MarshalToWriter marshals GraphProperties to the provided writer.
+func (m *GraphProperties) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if len(m.Name) > 0 {
+		writer.WriteString(1, m.Name)
+	}
+
+	if len(m.PackagePath) > 0 {
+		writer.WriteString(2, m.PackagePath)
+	}
+
+	if m.IsCommand {
+		writer.WriteBool(3, m.IsCommand)
+	}
+
+	if len(m.OutputDir) > 0 {
+		writer.WriteString(4, m.OutputDir)
+	}
+
+	if m.UseContext {
+		writer.WriteBool(5, m.UseContext)
+	}
+
+	if m.Telemetry {
+		writer.WriteBool(6, m.Telemetry)
+	}
+
+	if m.Generics {
+		writer.WriteBool(7, m.Generics)
+	}
+
+	return
+}
+
+// Marshal marshals GraphProperties to a slice of bytes.
+func (m *GraphProperties) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a GraphProperties from the provided reader.
+func (m *GraphProperties) UnmarshalFromReader(reader jspb.Reader) *GraphProperties {
+	for reader.Next() {
+		if m == nil {
+			m = &GraphProperties{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Name = reader.ReadString()
+		case 2:
+			m.PackagePath = reader.ReadString()
+		case 3:
+			m.IsCommand = reader.ReadBool()
+		case 4:
+			m.OutputDir = reader.ReadString()
+		case 5:
+			m.UseContext = reader.ReadBool()
+		case 6:
+			m.Telemetry = reader.ReadBool()
+		case 7:
+			m.Generics = reader.ReadBool()
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a GraphProperties from a slice of bytes.
+func (m *GraphProperties) Unmarshal(rawBytes []byte) (*GraphProperties, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type NodeChange struct {
+	Name   string
+	Config *NodeConfig
+}
+
+// GetName gets the Name of the NodeChange.
+func (m *NodeChange) GetName() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Name
+}
+
+// GetConfig gets the Config of the NodeChange.
+func (m *NodeChange) GetConfig() (x *NodeConfig) {
+	if m == nil {
+		return x
+	}
+	return m.Config
+}
+
+// MarshalToWriter marshals NodeChange to the provided writer.
+func (m *NodeChange) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if len(m.Name) > 0 {
+		writer.WriteString(1, m.Name)
+	}
+
+	if m.Config != nil {
+		writer.WriteMessage(2, func() {
+			m.Config.MarshalToWriter(writer)
+		})
+	}
+
+	return
+}
+
+// Marshal marshals NodeChange to a slice of bytes.
+func (m *NodeChange) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a NodeChange from the provided reader.
+func (m *NodeChange) UnmarshalFromReader(reader jspb.Reader) *NodeChange {
+	for reader.Next() {
+		if m == nil {
+			m = &NodeChange{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Name = reader.ReadString()
+		case 2:
+			reader.ReadMessage(func() {
+				m.Config = m.Config.UnmarshalFromReader(reader)
+			})
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a NodeChange from a slice of bytes.
+func (m *NodeChange) Unmarshal(rawBytes []byte) (*NodeChange, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type ChannelChange struct {
+	Name   string
+	Config *ChannelConfig
+}
+
+// GetName gets the Name of the ChannelChange.
+func (m *ChannelChange) GetName() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Name
+}
+
+// GetConfig gets the Config of the ChannelChange.
+func (m *ChannelChange) GetConfig() (x *ChannelConfig) {
+	if m == nil {
+		return x
+	}
+	return m.Config
+}
+
+// MarshalToWriter marshals ChannelChange to the provided writer.
+func (m *ChannelChange) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if len(m.Name) > 0 {
+		writer.WriteString(1, m.Name)
+	}
+
+	if m.Config != nil {
+		writer.WriteMessage(2, func() {
+			m.Config.MarshalToWriter(writer)
+		})
+	}
+
+	return
+}
+
+// Marshal marshals ChannelChange to a slice of bytes.
+func (m *ChannelChange) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a ChannelChange from the provided reader.
+func (m *ChannelChange) UnmarshalFromReader(reader jspb.Reader) *ChannelChange {
+	for reader.Next() {
+		if m == nil {
+			m = &ChannelChange{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Name = reader.ReadString()
+		case 2:
+			reader.ReadMessage(func() {
+				m.Config = m.Config.UnmarshalFromReader(reader)
+			})
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a ChannelChange from a slice of bytes.
+func (m *ChannelChange) Unmarshal(rawBytes []byte) (*ChannelChange, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type GraphChange struct {
+	Version    uint64
+	Properties *GraphProperties
+	Nodes      []*NodeChange
+	Channels   []*ChannelChange
+}
+
+// GetVersion gets the Version of the GraphChange.
+func (m *GraphChange) GetVersion() (x uint64) {
+	if m == nil {
+		return x
+	}
+	return m.Version
+}
+
+// GetProperties gets the Properties of the GraphChange.
+func (m *GraphChange) GetProperties() (x *GraphProperties) {
+	if m == nil {
+		return x
+	}
+	return m.Properties
+}
+
+// GetNodes gets the Nodes of the GraphChange.
+func (m *GraphChange) GetNodes() (x []*NodeChange) {
+	if m == nil {
+		return x
+	}
+	return m.Nodes
+}
+
+// GetChannels gets the Channels of the GraphChange.
+func (m *GraphChange) GetChannels() (x []*ChannelChange) {
+	if m == nil {
+		return x
+	}
+	return m.Channels
+}
+
+// MarshalToWriter marshals GraphChange to the provided writer.
+func (m *GraphChange) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if m.Version != 0 {
+		writer.WriteUint64(1, m.Version)
+	}
+
+	if m.Properties != nil {
+		writer.WriteMessage(2, func() {
+			m.Properties.MarshalToWriter(writer)
+		})
+	}
+
+	for _, msg := range m.Nodes {
+		writer.WriteMessage(3, func() {
+			msg.MarshalToWriter(writer)
+		})
+	}
+
+	for _, msg := range m.Channels {
+		writer.WriteMessage(4, func() {
+			msg.MarshalToWriter(writer)
+		})
+	}
+
+	return
+}
+
+// Marshal marshals GraphChange to a slice of bytes.
+func (m *GraphChange) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a GraphChange from the provided reader.
+func (m *GraphChange) UnmarshalFromReader(reader jspb.Reader) *GraphChange {
+	for reader.Next() {
+		if m == nil {
+			m = &GraphChange{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Version = reader.ReadUint64()
+		case 2:
+			reader.ReadMessage(func() {
+				m.Properties = m.Properties.UnmarshalFromReader(reader)
+			})
+		case 3:
+			reader.ReadMessage(func() {
+				m.Nodes = append(m.Nodes, new(NodeChange).UnmarshalFromReader(reader))
+			})
+		case 4:
+			reader.ReadMessage(func() {
+				m.Channels = append(m.Channels, new(ChannelChange).UnmarshalFromReader(reader))
+			})
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a GraphChange from a slice of bytes.
+func (m *GraphChange) Unmarshal(rawBytes []byte) (*GraphChange, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type EditResponse struct {
+	Version uint64
+}
+
+// GetVersion gets the Version of the EditResponse.
+func (m *EditResponse) GetVersion() (x uint64) {
+	if m == nil {
+		return x
+	}
+	return m.Version
+}
+
+// MarshalToWriter marshals EditResponse to the provided writer.
+func (m *EditResponse) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if m.Version != 0 {
+		writer.WriteUint64(1, m.Version)
+	}
+
+	return
+}
+
+// Marshal marshals EditResponse to a slice of bytes.
+func (m *EditResponse) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a EditResponse from the provided reader.
+func (m *EditResponse) UnmarshalFromReader(reader jspb.Reader) *EditResponse {
+	for reader.Next() {
+		if m == nil {
+			m = &EditResponse{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.Version = reader.ReadUint64()
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a EditResponse from a slice of bytes.
+func (m *EditResponse) Unmarshal(rawBytes []byte) (*EditResponse, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type CompileError struct {
+	File        string
+	Line        uint32
+	Column      uint32
+	Message     string
+	Node        string
+	Section     string
+	SectionLine uint32
+}
+
+// GetFile gets the File of the CompileError.
+func (m *CompileError) GetFile() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.File
+}
+
+// GetLine gets the Line of the CompileError.
+func (m *CompileError) GetLine() (x uint32) {
+	if m == nil {
+		return x
+	}
+	return m.Line
+}
+
+// GetColumn gets the Column of the CompileError.
+func (m *CompileError) GetColumn() (x uint32) {
+	if m == nil {
+		return x
+	}
+	return m.Column
+}
+
+// GetMessage gets the Message of the CompileError.
+func (m *CompileError) GetMessage() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Message
+}
+
+// GetNode gets the Node of the CompileError.
+func (m *CompileError) GetNode() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Node
+}
+
+// GetSection gets the Section of the CompileError.
+func (m *CompileError) GetSection() (x string) {
+	if m == nil {
+		return x
+	}
+	return m.Section
+}
+
+// GetSectionLine gets the SectionLine of the CompileError.
+func (m *CompileError) GetSectionLine() (x uint32) {
+	if m == nil {
+		return x
+	}
+	return m.SectionLine
+}
+
+// MarshalToWriter marshals CompileError to the provided writer.
+func (m *CompileError) MarshalToWriter(writer jspb.Writer) {
+	if m == nil {
+		return
+	}
+
+	if len(m.File) > 0 {
+		writer.WriteString(1, m.File)
+	}
+
+	if m.Line != 0 {
+		writer.WriteUint32(2, m.Line)
+	}
+
+	if m.Column != 0 {
+		writer.WriteUint32(3, m.Column)
+	}
+
+	if len(m.Message) > 0 {
+		writer.WriteString(4, m.Message)
+	}
+
+	if len(m.Node) > 0 {
+		writer.WriteString(5, m.Node)
+	}
+
+	if len(m.Section) > 0 {
+		writer.WriteString(6, m.Section)
+	}
+
+	if m.SectionLine != 0 {
+		writer.WriteUint32(7, m.SectionLine)
+	}
+
+	return
+}
+
+// Marshal marshals CompileError to a slice of bytes.
+func (m *CompileError) Marshal() []byte {
+	writer := jspb.NewWriter()
+	m.MarshalToWriter(writer)
+	return writer.GetResult()
+}
+
+// UnmarshalFromReader unmarshals a CompileError from the provided reader.
+func (m *CompileError) UnmarshalFromReader(reader jspb.Reader) *CompileError {
+	for reader.Next() {
+		if m == nil {
+			m = &CompileError{}
+		}
+
+		switch reader.GetFieldNumber() {
+		case 1:
+			m.File = reader.ReadString()
+		case 2:
+			m.Line = reader.ReadUint32()
+		case 3:
+			m.Column = reader.ReadUint32()
+		case 4:
+			m.Message = reader.ReadString()
+		case 5:
+			m.Node = reader.ReadString()
+		case 6:
+			m.Section = reader.ReadString()
+		case 7:
+			m.SectionLine = reader.ReadUint32()
+		default:
+			reader.SkipField()
+		}
+	}
+
+	return m
+}
+
+// Unmarshal unmarshals a CompileError from a slice of bytes.
+func (m *CompileError) Unmarshal(rawBytes []byte) (*CompileError, error) {
+	reader := jspb.NewReader(rawBytes)
+
+	m = m.UnmarshalFromReader(reader)
+
+	if err := reader.Err(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpcweb.Client
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpcweb package it is being compiled against.
+const _ = grpcweb.GrpcWebPackageIsVersion3
+
+// Client API for ShenzhenGo service
+
+type ShenzhenGoClient interface {
+	// Action performs an action (save, generate, install/build, etc).
+	Action(ctx context.Context, in *ActionRequest, opts ...grpcweb.CallOption) (ShenzhenGo_ActionClient, error)
+	// Batch applies several mutations as one edit. Either all of them are
+	// applied, and the resulting graph passes type inference, or none are.
+	Batch(ctx context.Context, in *BatchRequest, opts ...grpcweb.CallOption) (*EditResponse, error)
+	// Redo reapplies the most recently undone edit to the graph.
+	Redo(ctx context.Context, in *RedoRequest, opts ...grpcweb.CallOption) (*EditResponse, error)
+	// Run runs the program.
+	Run(ctx context.Context, opts ...grpcweb.CallOption) (ShenzhenGo_RunClient, error)
+	// SetNode either creates a new channel (name == "", config != nil)
+	// changes existing channel data such as name and attached pins (name is found, config != nil),
+	// or deletes a channel (name is found, config == nil).
+	SetChannel(ctx context.Context, in *SetChannelRequest, opts ...grpcweb.CallOption) (*EditResponse, error)
+	// SetGraphProperties changes metdata such as name and package path.
+	SetGraphProperties(ctx context.Context, in *SetGraphPropertiesRequest, opts ...grpcweb.CallOption) (*EditResponse, error)
+	// SetNode either creates a new node (name == "", config != nil)
+	// changes existing node such as name and multiplicity (name is found, config != nil),
+	// or deletes a node (name is found, config == nil).
+	SetNode(ctx context.Context, in *SetNodeRequest, opts ...grpcweb.CallOption) (*EditResponse, error)
+	// SetPosition changes the node position in the diagram.
+	SetPosition(ctx context.Context, in *SetPositionRequest, opts ...grpcweb.CallOption) (*EditResponse, error)
+	// Undo reverts the most recent edit to the graph made with SetChannel,
+	// SetGraphProperties, SetNode, or SetPosition.
+	Undo(ctx context.Context, in *UndoRequest, opts ...grpcweb.CallOption) (*EditResponse, error)
+	// WatchGraph streams changes made to the graph after the given version,
+	// by any client.
+	WatchGraph(ctx context.Context, in *WatchGraphRequest, opts ...grpcweb.CallOption) (ShenzhenGo_WatchGraphClient, error)
+	// WatchTelemetry streams samples from the program started by Run, for
+	// graphs with telemetry enabled.
+	WatchTelemetry(ctx context.Context, in *WatchTelemetryRequest, opts ...grpcweb.CallOption) (ShenzhenGo_WatchTelemetryClient, error)
+}
+
+type shenzhenGoClient struct {
+	client *grpcweb.Client
+}
+
+// NewShenzhenGoClient creates a new gRPC-Web client.
+func NewShenzhenGoClient(hostname string, opts ...grpcweb.DialOption) ShenzhenGoClient {
+	return &shenzhenGoClient{
+		client: grpcweb.NewClient(hostname, "proto.ShenzhenGo", opts...),
+	}
+}
+
+func (c *shenzhenGoClient) Action(ctx context.Context, in *ActionRequest, opts ...grpcweb.CallOption) (ShenzhenGo_ActionClient, error) {
+	srv, err := c.client.NewClientStream(ctx, false, true, "Action", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = srv.SendMsg(in.Marshal())
+	if err != nil {
+		return nil, err
+	}
+
+	return &shenzhenGoActionClient{srv}, nil
+}
+
+type ShenzhenGo_ActionClient interface {
+	Recv() (*ActionResponse, error)
 	grpcweb.ClientStream
 }
 
@@ -1249,6 +3032,24 @@ func (x *shenzhenGoActionClient) Recv() (*ActionResponse, error) {
 	return new(ActionResponse).Unmarshal(resp)
 }
 
+func (c *shenzhenGoClient) Batch(ctx context.Context, in *BatchRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
+	resp, err := c.client.RPCCall(ctx, "Batch", in.Marshal(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(EditResponse).Unmarshal(resp)
+}
+
+func (c *shenzhenGoClient) Redo(ctx context.Context, in *RedoRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
+	resp, err := c.client.RPCCall(ctx, "Redo", in.Marshal(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(EditResponse).Unmarshal(resp)
+}
+
 func (c *shenzhenGoClient) Run(ctx context.Context, opts ...grpcweb.CallOption) (ShenzhenGo_RunClient, error) {
 	srv, err := c.client.NewClientStream(ctx, true, true, "Run", opts...)
 	if err != nil {
@@ -1281,38 +3082,111 @@ func (x *shenzhenGoRunClient) Recv() (*Output, error) {
 	return new(Output).Unmarshal(resp)
 }
 
-func (c *shenzhenGoClient) SetChannel(ctx context.Context, in *SetChannelRequest, opts ...grpcweb.CallOption) (*Empty, error) {
+func (c *shenzhenGoClient) SetChannel(ctx context.Context, in *SetChannelRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
 	resp, err := c.client.RPCCall(ctx, "SetChannel", in.Marshal(), opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return new(Empty).Unmarshal(resp)
+	return new(EditResponse).Unmarshal(resp)
 }
 
-func (c *shenzhenGoClient) SetGraphProperties(ctx context.Context, in *SetGraphPropertiesRequest, opts ...grpcweb.CallOption) (*Empty, error) {
+func (c *shenzhenGoClient) SetGraphProperties(ctx context.Context, in *SetGraphPropertiesRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
 	resp, err := c.client.RPCCall(ctx, "SetGraphProperties", in.Marshal(), opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return new(Empty).Unmarshal(resp)
+	return new(EditResponse).Unmarshal(resp)
 }
 
-func (c *shenzhenGoClient) SetNode(ctx context.Context, in *SetNodeRequest, opts ...grpcweb.CallOption) (*Empty, error) {
+func (c *shenzhenGoClient) SetNode(ctx context.Context, in *SetNodeRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
 	resp, err := c.client.RPCCall(ctx, "SetNode", in.Marshal(), opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return new(Empty).Unmarshal(resp)
+	return new(EditResponse).Unmarshal(resp)
 }
 
-func (c *shenzhenGoClient) SetPosition(ctx context.Context, in *SetPositionRequest, opts ...grpcweb.CallOption) (*Empty, error) {
+func (c *shenzhenGoClient) SetPosition(ctx context.Context, in *SetPositionRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
 	resp, err := c.client.RPCCall(ctx, "SetPosition", in.Marshal(), opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return new(Empty).Unmarshal(resp)
+	return new(EditResponse).Unmarshal(resp)
+}
+
+func (c *shenzhenGoClient) Undo(ctx context.Context, in *UndoRequest, opts ...grpcweb.CallOption) (*EditResponse, error) {
+	resp, err := c.client.RPCCall(ctx, "Undo", in.Marshal(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(EditResponse).Unmarshal(resp)
+}
+
+func (c *shenzhenGoClient) WatchGraph(ctx context.Context, in *WatchGraphRequest, opts ...grpcweb.CallOption) (ShenzhenGo_WatchGraphClient, error) {
+	srv, err := c.client.NewClientStream(ctx, false, true, "WatchGraph", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = srv.SendMsg(in.Marshal())
+	if err != nil {
+		return nil, err
+	}
+
+	return &shenzhenGoWatchGraphClient{srv}, nil
+}
+
+type ShenzhenGo_WatchGraphClient interface {
+	Recv() (*GraphChange, error)
+	grpcweb.ClientStream
+}
+
+type shenzhenGoWatchGraphClient struct {
+	grpcweb.ClientStream
+}
+
+func (x *shenzhenGoWatchGraphClient) Recv() (*GraphChange, error) {
+	resp, err := x.RecvMsg()
+	if err != nil {
+		return nil, err
+	}
+
+	return new(GraphChange).Unmarshal(resp)
+}
+
+func (c *shenzhenGoClient) WatchTelemetry(ctx context.Context, in *WatchTelemetryRequest, opts ...grpcweb.CallOption) (ShenzhenGo_WatchTelemetryClient, error) {
+	srv, err := c.client.NewClientStream(ctx, false, true, "WatchTelemetry", opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = srv.SendMsg(in.Marshal())
+	if err != nil {
+		return nil, err
+	}
+
+	return &shenzhenGoWatchTelemetryClient{srv}, nil
+}
+
+type ShenzhenGo_WatchTelemetryClient interface {
+	Recv() (*Telemetry, error)
+	grpcweb.ClientStream
+}
+
+type shenzhenGoWatchTelemetryClient struct {
+	grpcweb.ClientStream
+}
+
+func (x *shenzhenGoWatchTelemetryClient) Recv() (*Telemetry, error) {
+	resp, err := x.RecvMsg()
+	if err != nil {
+		return nil, err
+	}
+
+	return new(Telemetry).Unmarshal(resp)
 }
@@ -18,14 +18,14 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os/exec"
+	"net"
 
 	"github.com/golang/protobuf/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
-	"github.com/google/shenzhen-go/model"
 	pb "github.com/google/shenzhen-go/proto/go"
+	"github.com/google/shenzhen-go/telemetry"
 )
 
 type actionStreamWriter struct {
@@ -53,11 +53,19 @@ func (c *server) Action(req *pb.ActionRequest, stream pb.ShenzhenGo_ActionServer
 		return SaveJSONFile(g.Graph)
 	case pb.ActionRequest_REVERT:
 		return g.reload()
+	case pb.ActionRequest_CHECK:
+		return Check(actionStreamWriter{stream}, g.Graph)
 	case pb.ActionRequest_GENERATE:
 		_, err := GeneratePackage(actionStreamWriter{stream}, g.Graph)
 		return err
 	case pb.ActionRequest_BUILD:
-		return Build(actionStreamWriter{stream}, g.Graph)
+		ces, err := buildPackage(actionStreamWriter{stream}, g.Graph)
+		if len(ces) > 0 {
+			if err := stream.Send(&pb.ActionResponse{CompileErrors: compileErrorsProto(ces)}); err != nil {
+				return err
+			}
+		}
+		return err
 	case pb.ActionRequest_INSTALL:
 		return Install(actionStreamWriter{stream}, g.Graph)
 	default:
@@ -77,6 +85,33 @@ func (w *runSvrWriter) Write(b []byte) (int, error) {
 	return len(b), nil
 }
 
+func (c *server) Batch(ctx context.Context, req *pb.BatchRequest) (*pb.EditResponse, error) {
+	log.Printf("api: Batch(%s)", proto.MarshalTextString(req))
+	g, err := c.lookupGraph(req.Graph)
+	if err != nil {
+		return &pb.EditResponse{}, err
+	}
+	var scope editScope
+	for _, m := range req.Mutations {
+		scope.add(m)
+	}
+	return g.edit(req.Version, scope, func() error { return g.batch(req.Mutations) })
+}
+
+func (c *server) Redo(ctx context.Context, req *pb.RedoRequest) (*pb.EditResponse, error) {
+	log.Printf("api: Redo(%s)", proto.MarshalTextString(req))
+	g, err := c.lookupGraph(req.Graph)
+	if err != nil {
+		return &pb.EditResponse{}, err
+	}
+	g.Lock()
+	defer g.Unlock()
+	if !g.redo() {
+		return &pb.EditResponse{}, status.Error(codes.FailedPrecondition, "nothing to redo")
+	}
+	return &pb.EditResponse{Version: g.version()}, nil
+}
+
 func (c *server) Run(svr pb.ShenzhenGo_RunServer) error {
 	log.Print("api: Run()")
 
@@ -93,13 +128,23 @@ func (c *server) Run(svr pb.ShenzhenGo_RunServer) error {
 	stderr := &runSvrWriter{svr, func(b []byte) *pb.Output { return &pb.Output{Err: string(b)} }}
 
 	g.Lock()
-	gp, err := GenerateRunner(stderr, g.Graph)
+	cmd, cleanup, err := GenerateRunner(svr.Context(), stderr, g.Graph)
+	tele := g.Telemetry
 	g.Unlock()
 	if err != nil {
 		return err
 	}
-
-	cmd := exec.CommandContext(svr.Context(), "go", "run", gp)
+	defer cleanup()
+	if tele {
+		// The program connects back to send telemetry.
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return status.Errorf(codes.Internal, "listening for telemetry: %v", err)
+		}
+		defer l.Close()
+		go g.telemetry.serve(l)
+		cmd.Env = append(cmd.Env, telemetry.EnvVar+"="+l.Addr().String())
+	}
 	fmt.Fprintf(stderr, "%v\n", cmd.Args)
 
 	// A pipe is better for input; managing a buffer is fiddly, and cmd.Wait
@@ -138,172 +183,114 @@ func (c *server) Run(svr pb.ShenzhenGo_RunServer) error {
 	return nil
 }
 
-func (c *server) SetChannel(ctx context.Context, req *pb.SetChannelRequest) (*pb.Empty, error) {
+func (c *server) SetChannel(ctx context.Context, req *pb.SetChannelRequest) (*pb.EditResponse, error) {
 	log.Printf("api: SetChannel(%s)", proto.MarshalTextString(req))
-
-	if req.Channel == "" && req.Config == nil {
-		return &pb.Empty{}, status.Error(codes.InvalidArgument, "must provide existing channel or new config")
-	}
-
 	g, err := c.lookupGraph(req.Graph)
 	if err != nil {
-		return &pb.Empty{}, err
-	}
-	g.Lock()
-	defer g.Unlock()
-
-	var nps map[model.NodePin]struct{}
-
-	if req.Config != nil {
-		// TODO: More validation (name, type, etc)
-		if req.Config.Name == "nil" {
-			return &pb.Empty{}, status.Errorf(codes.InvalidArgument, "channels may not be named %q", req.Config.Name)
-		}
-
-		if req.Channel != req.Config.Name {
-			// Check that the new name is available...
-			if _, found := g.Channels[req.Config.Name]; found {
-				return &pb.Empty{}, status.Errorf(codes.AlreadyExists, "target name %q already exists", req.Config.Name)
-			}
-		}
-
-		// Convert the []pb.NodePin into a set of model.NodePin, and validate
-		// that the pins exist at the same time.
-		nps = make(map[model.NodePin]struct{}, len(req.Config.Pins))
-		for _, np := range req.Config.Pins {
-			n, err := g.lookupNode(np.Node)
-			if err != nil {
-				return &pb.Empty{}, err
-			}
-			if _, found := n.Connections[np.Pin]; !found {
-				return &pb.Empty{}, status.Errorf(codes.NotFound, "node %q pin %q does not exist", np.Node, np.Pin)
-			}
-			nps[model.NodePin{Node: np.Node, Pin: np.Pin}] = struct{}{}
-		}
-	}
-
-	if req.Channel != "" {
-		old, err := g.lookupChannel(req.Channel)
-		if err != nil {
-			return &pb.Empty{}, err
-		}
-
-		// Update existing channel data by deleting the old one from the map
-		// and any connections, then setting the new one below.
-		g.DeleteChannel(old)
-
-		if req.Config == nil {
-			// Deletion was intended, job complete.
-			return &pb.Empty{}, nil
-		}
+		return &pb.EditResponse{}, err
 	}
-
-	// Set entry in map, update connections on node side.
-	g.Channels[req.Config.Name] = &model.Channel{
-		Name:     req.Config.Name,
-		Capacity: int(req.Config.Cap),
-		Pins:     nps,
-	}
-	for np := range nps {
-		g.Nodes[np.Node].Connections[np.Pin] = req.Config.Name
-	}
-	return &pb.Empty{}, nil
+	scope := editScope{channels: []string{req.Channel, req.Config.GetName()}}
+	return g.edit(req.Version, scope, func() error { return g.setChannel(req) })
 }
 
-func (c *server) SetGraphProperties(ctx context.Context, req *pb.SetGraphPropertiesRequest) (*pb.Empty, error) {
+func (c *server) SetGraphProperties(ctx context.Context, req *pb.SetGraphPropertiesRequest) (*pb.EditResponse, error) {
 	log.Printf("api: SetGraphProperties(%s)", proto.MarshalTextString(req))
 	g, err := c.lookupGraph(req.Graph)
 	if err != nil {
-		return &pb.Empty{}, err
+		return &pb.EditResponse{}, err
 	}
-	g.Name = req.Name
-	g.PackagePath = req.PackagePath
-	g.IsCommand = req.IsCommand
-	return &pb.Empty{}, nil
+	return g.edit(req.Version, editScope{props: true}, func() error {
+		g.setProperties(req)
+		return nil
+	})
 }
 
-func (c *server) SetNode(ctx context.Context, req *pb.SetNodeRequest) (*pb.Empty, error) {
+func (c *server) SetNode(ctx context.Context, req *pb.SetNodeRequest) (*pb.EditResponse, error) {
 	log.Printf("api: SetNode(%s)", proto.MarshalTextString(req))
+	g, err := c.lookupGraph(req.Graph)
+	if err != nil {
+		return &pb.EditResponse{}, err
+	}
+	scope := editScope{nodes: []string{req.Node, req.Config.GetName()}}
+	return g.edit(req.Version, scope, func() error { return g.setNode(req) })
+}
 
-	if req.Node == "" && req.Config == nil {
-		return &pb.Empty{}, status.Error(codes.InvalidArgument, "must provide existing node or new config")
+func (c *server) SetPosition(ctx context.Context, req *pb.SetPositionRequest) (*pb.EditResponse, error) {
+	log.Printf("api: SetPosition(%s)", proto.MarshalTextString(req))
+	g, err := c.lookupGraph(req.Graph)
+	if err != nil {
+		return &pb.EditResponse{}, err
 	}
+	return g.edit(req.Version, editScope{nodes: []string{req.Node}}, func() error { return g.setPosition(req) })
+}
 
+func (c *server) Undo(ctx context.Context, req *pb.UndoRequest) (*pb.EditResponse, error) {
+	log.Printf("api: Undo(%s)", proto.MarshalTextString(req))
 	g, err := c.lookupGraph(req.Graph)
 	if err != nil {
-		return &pb.Empty{}, err
+		return &pb.EditResponse{}, err
 	}
 	g.Lock()
 	defer g.Unlock()
-
-	var part model.Part
-	if req.Config != nil {
-		if req.Node != req.Config.Name {
-			// Check the new name is available...
-			if _, exists := g.Nodes[req.Config.Name]; exists {
-				return &pb.Empty{}, status.Errorf(codes.AlreadyExists, "node %q already exists", req.Config.Name)
-			}
-		}
-
-		p, err := (&model.PartJSON{
-			Part: req.Config.PartCfg,
-			Type: req.Config.PartType,
-		}).Unmarshal()
-		if err != nil {
-			return &pb.Empty{}, status.Errorf(codes.FailedPrecondition, "part unmarshal: %v", err)
-		}
-		part = p
+	if !g.undo() {
+		return &pb.EditResponse{}, status.Error(codes.FailedPrecondition, "nothing to undo")
 	}
+	return &pb.EditResponse{Version: g.version()}, nil
+}
 
-	var conns map[string]string
-	if req.Node != "" {
-		old, err := g.lookupNode(req.Node)
-		if err != nil {
-			return &pb.Empty{}, err
-		}
-
-		// Delete old node, only clean up channels if deleting this node
-		// is the intention.
-		g.DeleteNode(old, req.Config == nil)
-
-		if req.Config == nil {
-			// Deletion was intended, job complete.
-			return &pb.Empty{}, nil
+func (c *server) WatchGraph(req *pb.WatchGraphRequest, stream pb.ShenzhenGo_WatchGraphServer) error {
+	log.Printf("api: WatchGraph(%s)", proto.MarshalTextString(req))
+	g, err := c.lookupGraph(req.Graph)
+	if err != nil {
+		return err
+	}
+	g.Lock()
+	backlog, ch, err := g.watch(req.Version)
+	g.Unlock()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		g.Lock()
+		g.unwatch(ch)
+		g.Unlock()
+	}()
+	for _, gc := range backlog {
+		if err := stream.Send(gc); err != nil {
+			return err
 		}
-
-		conns = old.Connections
-		log.Printf("old.Connections = %v", conns)
 	}
-
-	n := &model.Node{
-		Name:         req.Config.Name,
-		Comment:      req.Config.Comment,
-		Multiplicity: req.Config.Multiplicity,
-		Enabled:      req.Config.Enabled,
-		Wait:         req.Config.Wait,
-		Part:         part,
-		X:            req.Config.X,
-		Y:            req.Config.Y,
-		Connections:  conns,
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case gc, ok := <-ch:
+			if !ok {
+				return status.Error(codes.Aborted, "watcher fell behind; reload the graph")
+			}
+			if err := stream.Send(gc); err != nil {
+				return err
+			}
+		}
 	}
-	g.Nodes[req.Config.Name] = n
-	n.RefreshConnections()
-	g.RefreshChannelsPins() // Changing the part might have changed available pins.
-	return &pb.Empty{}, nil
 }
 
-func (c *server) SetPosition(ctx context.Context, req *pb.SetPositionRequest) (*pb.Empty, error) {
-	log.Printf("api: SetPosition(%s)", proto.MarshalTextString(req))
+func (c *server) WatchTelemetry(req *pb.WatchTelemetryRequest, stream pb.ShenzhenGo_WatchTelemetryServer) error {
+	log.Printf("api: WatchTelemetry(%s)", proto.MarshalTextString(req))
 	g, err := c.lookupGraph(req.Graph)
 	if err != nil {
-		return &pb.Empty{}, err
+		return err
 	}
-	g.Lock()
-	defer g.Unlock()
-	n, err := g.lookupNode(req.Node)
-	if err != nil {
-		return &pb.Empty{}, err
+	ch, stop := g.telemetry.watch()
+	defer stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case t := <-ch:
+			if err := stream.Send(t); err != nil {
+				return err
+			}
+		}
 	}
-	n.X, n.Y = req.X, req.Y
-	return &pb.Empty{}, nil
 }
@@ -425,6 +425,8 @@ func TestSetGraphProperties(t *testing.T) {
 				Name:        "name",
 				PackagePath: "package/path",
 				IsCommand:   true,
+				UseContext:  true,
+				Telemetry:   true,
 			},
 			code: codes.OK,
 		},
@@ -446,6 +448,12 @@ func TestSetGraphProperties(t *testing.T) {
 	if got, want := foo.IsCommand, true; got != want {
 		t.Errorf("foo.IsCommand = %t, want %t", got, want)
 	}
+	if got, want := foo.UseContext, true; got != want {
+		t.Errorf("foo.UseContext = %t, want %t", got, want)
+	}
+	if got, want := foo.Telemetry, true; got != want {
+		t.Errorf("foo.Telemetry = %t, want %t", got, want)
+	}
 }
 
 func TestSetNode(t *testing.T) {
@@ -616,3 +624,109 @@ func TestSetPosition(t *testing.T) {
 		t.Errorf("bar.Y = %f, want %f", got, want)
 	}
 }
+
+func TestBatch(t *testing.T) {
+	codeNode := func(name, typ string, dir pin.Direction) *pb.NodeConfig {
+		pj, err := model.MarshalPart(parts.NewCode(nil, "", "", "", pin.Map{
+			"p": &pin.Definition{Name: "p", Type: typ, Direction: dir},
+		}))
+		if err != nil {
+			t.Fatalf("MarshalPart = %v", err)
+		}
+		return &pb.NodeConfig{
+			Name:         name,
+			Multiplicity: "1",
+			PartCfg:      pj.Part,
+			PartType:     pj.Type,
+		}
+	}
+	setNode := func(cfg *pb.NodeConfig) *pb.Mutation {
+		return &pb.Mutation{Mutation: &pb.Mutation_SetNode{SetNode: &pb.SetNodeRequest{Config: cfg}}}
+	}
+	setChannel := func(existing string, pins ...*pb.NodePin) *pb.Mutation {
+		return &pb.Mutation{Mutation: &pb.Mutation_SetChannel{SetChannel: &pb.SetChannelRequest{
+			Channel: existing,
+			Config:  &pb.ChannelConfig{Name: "c", Pins: pins},
+		}}}
+	}
+	c := &server{
+		loadedGraphs: map[string]*serveGraph{"foo": {Graph: &model.Graph{
+			Name:     "foo",
+			Nodes:    map[string]*model.Node{},
+			Channels: map[string]*model.Channel{},
+		}}},
+	}
+	g := c.loadedGraphs["foo"]
+	ctx := context.Background()
+	src := &pb.NodePin{Node: "src", Pin: "p"}
+	dst := &pb.NodePin{Node: "dst", Pin: "p"}
+	bad := &pb.NodePin{Node: "bad", Pin: "p"}
+
+	// Creating and connecting two nodes is one edit.
+	resp, err := c.Batch(ctx, &pb.BatchRequest{
+		Graph: "foo",
+		Mutations: []*pb.Mutation{
+			setNode(codeNode("src", "int", pin.Output)),
+			setNode(codeNode("dst", "int", pin.Input)),
+			setChannel("", src, dst),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Batch(create and connect) = %v, want nil", err)
+	}
+	if got, want := resp.Version, uint64(2); got != want {
+		t.Errorf("Batch(create and connect) version = %d, want %d", got, want)
+	}
+	if ch := g.Channels["c"]; ch == nil || !ch.HasPin("src", "p") || !ch.HasPin("dst", "p") {
+		t.Fatalf("channel c = %+v, want it connecting src.p and dst.p", ch)
+	}
+
+	tests := []struct {
+		name      string
+		mutations []*pb.Mutation
+		code      codes.Code
+	}{
+		{
+			name: "type mismatch",
+			mutations: []*pb.Mutation{
+				setNode(codeNode("bad", "string", pin.Input)),
+				setChannel("c", src, dst, bad),
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "failing mutation",
+			mutations: []*pb.Mutation{
+				setNode(codeNode("bad", "int", pin.Input)),
+				{Mutation: &pb.Mutation_SetPosition{SetPosition: &pb.SetPositionRequest{Node: "nope"}}},
+			},
+			code: codes.NotFound,
+		},
+		{
+			name:      "empty mutation",
+			mutations: []*pb.Mutation{{}},
+			code:      codes.InvalidArgument,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := c.Batch(ctx, &pb.BatchRequest{Graph: "foo", Mutations: test.mutations})
+			if got := code(err); got != test.code {
+				t.Errorf("Batch code = %v (%v), want %v", got, err, test.code)
+			}
+			// Nothing was changed.
+			if g.Nodes["bad"] != nil {
+				t.Error("node bad exists after failed batch")
+			}
+			if ch := g.Channels["c"]; ch == nil || len(ch.Pins) != 2 {
+				t.Errorf("channel c = %+v, want it connecting two pins", ch)
+			}
+			if got, want := g.Nodes["src"].Connections["p"], "c"; got != want {
+				t.Errorf("src.Connections[p] = %q, want %q", got, want)
+			}
+			if got, want := g.version(), uint64(2); got != want {
+				t.Errorf("graph version = %d, want %d", got, want)
+			}
+		})
+	}
+}
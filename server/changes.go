@@ -0,0 +1,247 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"log"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/google/shenzhen-go/model"
+	pb "github.com/google/shenzhen-go/proto/go"
+)
+
+const (
+	// maxChanges is the number of recent changes kept, for watchers that
+	// are catching up and for edits based on older versions.
+	maxChanges = 100
+
+	// watchBuffer is the number of changes a watcher can fall behind by
+	// before it is dropped.
+	watchBuffer = 16
+)
+
+// changeLog records recent changes to a graph and passes them to watchers.
+// It is guarded by the serveGraph mutex.
+type changeLog struct {
+	published uint64
+	recent    []*pb.GraphChange
+	watchers  map[chan *pb.GraphChange]struct{}
+}
+
+// version returns the current version of the graph. Versions start at 1,
+// so that 0 can mean "any version".
+func (sg *serveGraph) version() uint64 {
+	return sg.changes.published + 1
+}
+
+// publish assigns the next version to the edit, records it in the change
+// log, and sends it to watchers. It returns the new version. The graph
+// must be locked.
+func (sg *serveGraph) publish(e *edit) uint64 {
+	l := &sg.changes
+	l.published++
+	c := graphChange(e)
+	c.Version = sg.version()
+	l.recent = append(l.recent, c)
+	if len(l.recent) > maxChanges {
+		l.recent = l.recent[len(l.recent)-maxChanges:]
+	}
+	for ch := range l.watchers {
+		select {
+		case ch <- c:
+		default:
+			// The watcher fell behind; closing the channel tells it so.
+			close(ch)
+			delete(l.watchers, ch)
+		}
+	}
+	return c.Version
+}
+
+// watch returns the changes made after version, and a channel that receives
+// later changes. The channel is closed if the watcher falls behind. The
+// graph must be locked.
+func (sg *serveGraph) watch(version uint64) ([]*pb.GraphChange, chan *pb.GraphChange, error) {
+	if err := sg.checkBase(version); err != nil {
+		return nil, nil, err
+	}
+	l := &sg.changes
+	if l.watchers == nil {
+		l.watchers = make(map[chan *pb.GraphChange]struct{})
+	}
+	ch := make(chan *pb.GraphChange, watchBuffer)
+	l.watchers[ch] = struct{}{}
+	return sg.changesSince(version), ch, nil
+}
+
+// unwatch stops sending changes to a channel returned by watch. The graph
+// must be locked.
+func (sg *serveGraph) unwatch(ch chan *pb.GraphChange) {
+	delete(sg.changes.watchers, ch)
+}
+
+// checkBase checks that version is one that changes can be found after.
+func (sg *serveGraph) checkBase(version uint64) error {
+	cur := sg.version()
+	if version > cur {
+		return status.Errorf(codes.InvalidArgument, "version %d is newer than the graph (version %d)", version, cur)
+	}
+	if r := sg.changes.recent; version < cur && (len(r) == 0 || version < r[0].Version-1) {
+		return status.Errorf(codes.Aborted, "version %d is too old; reload the graph", version)
+	}
+	return nil
+}
+
+// changesSince returns the recorded changes made after version.
+func (sg *serveGraph) changesSince(version uint64) []*pb.GraphChange {
+	r := sg.changes.recent
+	i := sort.Search(len(r), func(i int) bool { return r[i].Version > version })
+	return r[i:]
+}
+
+// checkVersion checks that an edit based on version base doesn't conflict
+// with changes made since. Edits conflict if they touch the same nodes or
+// channels, or both change the graph properties. An edit based on version
+// 0 never conflicts. The graph must be locked.
+func (sg *serveGraph) checkVersion(base uint64, scope editScope) error {
+	if base == 0 {
+		return nil
+	}
+	if err := sg.checkBase(base); err != nil {
+		return err
+	}
+	for _, c := range sg.changesSince(base) {
+		if scope.props && c.Properties != nil {
+			return status.Errorf(codes.Aborted, "graph properties were changed in version %d", c.Version)
+		}
+		for _, nc := range c.Nodes {
+			for _, n := range scope.nodes {
+				if n != "" && nc.Name == n {
+					return status.Errorf(codes.Aborted, "node %q was changed in version %d", n, c.Version)
+				}
+			}
+		}
+		for _, cc := range c.Channels {
+			for _, ch := range scope.channels {
+				if ch != "" && cc.Name == ch {
+					return status.Errorf(codes.Aborted, "channel %q was changed in version %d", ch, c.Version)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// graphChange converts the after state of an edit into a change message.
+func graphChange(e *edit) *pb.GraphChange {
+	c := new(pb.GraphChange)
+	if p := e.after.props; p != e.before.props {
+		c.Properties = &pb.GraphProperties{
+			Name:        p.Name,
+			PackagePath: p.PackagePath,
+			IsCommand:   p.IsCommand,
+			OutputDir:   p.OutputDir,
+			UseContext:  p.UseContext,
+			Telemetry:   p.Telemetry,
+			Generics:    p.Generics,
+		}
+	}
+	for nn, n := range e.after.nodes {
+		nc := &pb.NodeChange{Name: nn}
+		if n != nil {
+			nc.Config = nodeConfig(n)
+		}
+		c.Nodes = append(c.Nodes, nc)
+	}
+	sort.Slice(c.Nodes, func(i, j int) bool { return c.Nodes[i].Name < c.Nodes[j].Name })
+	for cn, ch := range e.after.channels {
+		cc := &pb.ChannelChange{Name: cn}
+		if ch != nil {
+			cc.Config = channelConfig(ch)
+		}
+		c.Channels = append(c.Channels, cc)
+	}
+	sort.Slice(c.Channels, func(i, j int) bool { return c.Channels[i].Name < c.Channels[j].Name })
+	return c
+}
+
+func nodeConfig(n *model.Node) *pb.NodeConfig {
+	cfg := &pb.NodeConfig{
+		Name:           n.Name,
+		Comment:        n.Comment,
+		Enabled:        n.Enabled,
+		Multiplicity:   n.Multiplicity,
+		Wait:           n.Wait,
+		X:              n.X,
+		Y:              n.Y,
+		Recover:        n.Recover,
+		Restart:        string(n.Restart),
+		MaxRestarts:    uint32(n.MaxRestarts),
+		RestartBackoff: n.RestartBackoff,
+		RestartMetrics: n.RestartMetrics,
+	}
+	if n.Part == nil {
+		return cfg
+	}
+	pj, err := model.MarshalPart(n.Part)
+	if err != nil {
+		log.Printf("Couldn't marshal part of node %q: %v", n.Name, err)
+		return cfg
+	}
+	cfg.PartCfg, cfg.PartType = pj.Part, pj.Type
+	return cfg
+}
+
+func channelConfig(c *model.Channel) *pb.ChannelConfig {
+	cfg := &pb.ChannelConfig{
+		Name: c.Name,
+		Cap:  uint64(c.Capacity),
+		Pins: make([]*pb.NodePin, 0, len(c.Pins)),
+	}
+	for np := range c.Pins {
+		cfg.Pins = append(cfg.Pins, &pb.NodePin{Node: np.Node, Pin: np.Pin})
+	}
+	sort.Slice(cfg.Pins, func(i, j int) bool {
+		a, b := cfg.Pins[i], cfg.Pins[j]
+		if a.Node == b.Node {
+			return a.Pin < b.Pin
+		}
+		return a.Node < b.Node
+	})
+	return cfg
+}
+
+// partsEqual reports whether two parts have the same type and configuration.
+func partsEqual(a, b model.Part) bool {
+	if a == b {
+		return true
+	}
+	if a == nil || b == nil {
+		return false
+	}
+	aj, err := model.MarshalPart(a)
+	if err != nil {
+		return false
+	}
+	bj, err := model.MarshalPart(b)
+	if err != nil {
+		return false
+	}
+	return aj.Type == bj.Type && bytes.Equal(aj.Part, bj.Part)
+}
@@ -0,0 +1,177 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/google/shenzhen-go/model"
+	"github.com/google/shenzhen-go/parts"
+	pb "github.com/google/shenzhen-go/proto/go"
+)
+
+func twoNodeServer() *server {
+	node := func(name string) *model.Node {
+		return &model.Node{
+			Name:        name,
+			Part:        parts.NewCode(nil, "", "", "", nil),
+			Connections: map[string]string{},
+		}
+	}
+	return &server{
+		loadedGraphs: map[string]*serveGraph{
+			"foo": {Graph: &model.Graph{
+				Name: "foo",
+				Nodes: map[string]*model.Node{
+					"baz":  node("baz"),
+					"quux": node("quux"),
+				},
+				Channels: map[string]*model.Channel{},
+			}},
+		},
+	}
+}
+
+func TestEditVersions(t *testing.T) {
+	c := twoNodeServer()
+	ctx := context.Background()
+	setPos := func(node string, x float64, version uint64) (uint64, error) {
+		resp, err := c.SetPosition(ctx, &pb.SetPositionRequest{Graph: "foo", Node: node, X: x, Version: version})
+		return resp.Version, err
+	}
+
+	tests := []struct {
+		desc        string
+		node        string
+		x           float64
+		base        uint64
+		wantVersion uint64
+		wantCode    codes.Code
+	}{
+		{desc: "any version", node: "baz", x: 1, base: 0, wantVersion: 2},
+		{desc: "current version", node: "baz", x: 2, base: 2, wantVersion: 3},
+		{desc: "unchanged", node: "baz", x: 2, base: 3, wantVersion: 3},
+		{desc: "rebased", node: "quux", x: 1, base: 1, wantVersion: 4},
+		{desc: "conflict", node: "baz", x: 3, base: 2, wantCode: codes.Aborted},
+		{desc: "future version", node: "baz", x: 3, base: 99, wantCode: codes.InvalidArgument},
+	}
+	for _, test := range tests {
+		got, err := setPos(test.node, test.x, test.base)
+		if gc := code(err); gc != test.wantCode {
+			t.Errorf("%s: SetPosition code = %v, want %v", test.desc, gc, test.wantCode)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got != test.wantVersion {
+			t.Errorf("%s: SetPosition version = %d, want %d", test.desc, got, test.wantVersion)
+		}
+	}
+	if got, want := c.loadedGraphs["foo"].Nodes["baz"].X, 2.0; got != want {
+		t.Errorf("baz.X = %v, want %v", got, want)
+	}
+
+	// Graph properties conflict with graph properties, but not nodes.
+	_, err := c.SetGraphProperties(ctx, &pb.SetGraphPropertiesRequest{Graph: "foo", Name: "bar", Version: 1})
+	if err != nil {
+		t.Errorf("SetGraphProperties based on version 1 = %v, want nil", err)
+	}
+	_, err = c.SetGraphProperties(ctx, &pb.SetGraphPropertiesRequest{Graph: "foo", Name: "baz", Version: 4})
+	if got, want := code(err), codes.Aborted; got != want {
+		t.Errorf("SetGraphProperties based on version 4: code = %v, want %v", got, want)
+	}
+
+	// Undo is a change like any other.
+	resp, err := c.Undo(ctx, &pb.UndoRequest{Graph: "foo"})
+	if err != nil {
+		t.Fatalf("Undo = %v, want nil", err)
+	}
+	if got, want := resp.Version, uint64(6); got != want {
+		t.Errorf("Undo version = %d, want %d", got, want)
+	}
+}
+
+type fakeWatchGraphServer struct {
+	grpc.ServerStream
+	ctx     context.Context
+	changes chan *pb.GraphChange
+}
+
+func (s *fakeWatchGraphServer) Context() context.Context { return s.ctx }
+
+func (s *fakeWatchGraphServer) Send(c *pb.GraphChange) error {
+	s.changes <- c
+	return nil
+}
+
+func TestWatchGraph(t *testing.T) {
+	c := twoNodeServer()
+	ctx := context.Background()
+	if _, err := c.SetPosition(ctx, &pb.SetPositionRequest{Graph: "foo", Node: "baz", X: 1}); err != nil {
+		t.Fatalf("SetPosition = %v, want nil", err)
+	}
+
+	wctx, cancel := context.WithCancel(ctx)
+	stream := &fakeWatchGraphServer{
+		ctx:     wctx,
+		changes: make(chan *pb.GraphChange, 10),
+	}
+	done := make(chan error)
+	go func() {
+		done <- c.WatchGraph(&pb.WatchGraphRequest{Graph: "foo", Version: 1}, stream)
+	}()
+	next := func() *pb.GraphChange {
+		t.Helper()
+		select {
+		case gc := <-stream.changes:
+			return gc
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a change")
+			return nil
+		}
+	}
+
+	// The change made before watching is sent first.
+	gc := next()
+	if gc.Version != 2 || len(gc.Nodes) != 1 || gc.Nodes[0].Name != "baz" || gc.Nodes[0].Config.X != 1 {
+		t.Errorf("first change = %v, want baz moved to x = 1 in version 2", gc)
+	}
+
+	// Then changes as they happen.
+	if _, err := c.SetNode(ctx, &pb.SetNodeRequest{Graph: "foo", Node: "quux"}); err != nil {
+		t.Fatalf("SetNode = %v, want nil", err)
+	}
+	gc = next()
+	if gc.Version != 3 || len(gc.Nodes) != 1 || gc.Nodes[0].Name != "quux" || gc.Nodes[0].Config != nil {
+		t.Errorf("second change = %v, want quux deleted in version 3", gc)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("WatchGraph = %v, want nil", err)
+	}
+
+	// Versions that are too new can't be watched.
+	err := c.WatchGraph(&pb.WatchGraphRequest{Graph: "foo", Version: 99}, stream)
+	if got, want := code(err), codes.InvalidArgument; got != want {
+		t.Errorf("WatchGraph(version 99) code = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/shenzhen-go/model"
+	pb "github.com/google/shenzhen-go/proto/go"
+)
+
+// CompileError is an error reported by the Go compiler in a generated file.
+type CompileError struct {
+	File    string // name of the generated file
+	Line    int
+	Column  int // 0 if not reported
+	Message string
+
+	// Code is where the line came from, if it came from a node.
+	Code *model.CodePosition
+}
+
+func (e *CompileError) String() string {
+	pos := fmt.Sprintf("%s:%d", e.File, e.Line)
+	if e.Column > 0 {
+		pos += fmt.Sprintf(":%d", e.Column)
+	}
+	if e.Code == nil {
+		return fmt.Sprintf("%s: %s", pos, e.Message)
+	}
+	return fmt.Sprintf("%v: %s (%s)", e.Code, e.Message, pos)
+}
+
+// compileErrorRE matches errors printed by the go tool, such as
+// "./generated.go:12:2: undefined: foo".
+var compileErrorRE = regexp.MustCompile(`^(.+\.go):(\d+)(?::(\d+))?: (.+)$`)
+
+// compileErrors finds errors in the files in dir, in the output of a go
+// command run in cmdDir (or the current directory, if cmdDir is empty). The
+// errors are mapped back to the code of nodes with lm.
+func compileErrors(output, cmdDir, dir string, lm *model.LineMap) []*CompileError {
+	var ces []*CompileError
+	for _, l := range strings.Split(output, "\n") {
+		m := compileErrorRE.FindStringSubmatch(strings.TrimSpace(l))
+		if m == nil {
+			continue
+		}
+		p := filepath.FromSlash(m[1])
+		if !filepath.IsAbs(p) {
+			var err error
+			if p, err = filepath.Abs(filepath.Join(cmdDir, p)); err != nil {
+				continue
+			}
+		}
+		if filepath.Dir(p) != filepath.Clean(dir) {
+			// Maybe in a subgraph or other dependency.
+			continue
+		}
+		ce := &CompileError{
+			File:    filepath.Base(p),
+			Message: m[4],
+		}
+		ce.Line, _ = strconv.Atoi(m[2])
+		ce.Column, _ = strconv.Atoi(m[3])
+		if cp, ok := lm.Lookup(ce.File, ce.Line); ok {
+			ce.Code = &cp
+		}
+		ces = append(ces, ce)
+	}
+	return ces
+}
+
+// writeCompileErrors writes the compile errors that are in nodes to out.
+func writeCompileErrors(out io.Writer, ces []*CompileError) {
+	header := false
+	for _, ce := range ces {
+		if ce.Code == nil {
+			continue
+		}
+		if !header {
+			fmt.Fprintln(out, "[Compile errors in nodes]")
+			header = true
+		}
+		fmt.Fprintln(out, ce)
+	}
+}
+
+// compileErrorsProto converts compile errors for sending to the client.
+func compileErrorsProto(ces []*CompileError) []*pb.CompileError {
+	pces := make([]*pb.CompileError, 0, len(ces))
+	for _, ce := range ces {
+		pce := &pb.CompileError{
+			File:    ce.File,
+			Line:    uint32(ce.Line),
+			Column:  uint32(ce.Column),
+			Message: ce.Message,
+		}
+		if ce.Code != nil {
+			pce.Node = ce.Code.Node
+			pce.Section = string(ce.Code.Section)
+			pce.SectionLine = uint32(ce.Code.Line)
+		}
+		pces = append(pces, pce)
+	}
+	return pces
+}
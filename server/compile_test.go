@@ -0,0 +1,90 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/shenzhen-go/model"
+	"github.com/google/shenzhen-go/model/pin"
+	"gopkg.in/d4l3k/messagediff.v1"
+)
+
+func TestCompileErrors(t *testing.T) {
+	g := &model.Graph{
+		Name:        "broken",
+		PackagePath: "example.com/broken",
+		Nodes: map[string]*model.Node{
+			"Broken": {
+				Part: &model.FakePart{
+					Body: "x := 1\nfoo(x)",
+					Pns:  pin.NewMap(),
+				},
+				Name:         "Broken",
+				Enabled:      true,
+				Multiplicity: "1",
+				Connections:  map[string]string{},
+			},
+		},
+		Channels: map[string]*model.Channel{},
+	}
+	files, lm, err := g.GoFilesWithLineMap()
+	if err != nil {
+		t.Fatalf("GoFilesWithLineMap() = error %v", err)
+	}
+	const file = "generated.Broken.node.go"
+	line := 0
+	for i, l := range strings.Split(string(files[file]), "\n") {
+		if strings.TrimSpace(l) == "foo(x)" {
+			line = i + 1
+		}
+	}
+	if line == 0 {
+		t.Fatalf("%s has no line foo(x):\n%s", file, files[file])
+	}
+
+	dir := filepath.Join(string(filepath.Separator), "src", "broken")
+	output := fmt.Sprintf(`# example.com/broken
+./%s:%d:2: undefined: foo
+./generated.go:3:1: something else
+../other/other.go:1:1: not ours
+(process exit status 2)
+`, file, line)
+	got := compileErrors(output, dir, dir, lm)
+	want := []*CompileError{
+		{
+			File:    file,
+			Line:    line,
+			Column:  2,
+			Message: "undefined: foo",
+			Code:    &model.CodePosition{Node: "Broken", Section: model.BodySection, Line: 2},
+		},
+		{
+			File:    model.MainFileName,
+			Line:    3,
+			Column:  1,
+			Message: "something else",
+		},
+	}
+	if diff, equal := messagediff.PrettyDiff(got, want); !equal {
+		t.Errorf("compileErrors(...) diff (got -> want):\n%s", diff)
+	}
+	if got, want := got[0].String(), `node "Broken" body line 2: undefined: foo (`+file+fmt.Sprintf(":%d:2)", line); got != want {
+		t.Errorf("CompileError.String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"log"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/google/shenzhen-go/model"
+	pb "github.com/google/shenzhen-go/proto/go"
+)
+
+// editScope is what an edit might change, for finding conflicting edits.
+type editScope struct {
+	nodes, channels []string
+	props           bool
+}
+
+// add widens the scope to include whatever the mutation might change.
+func (s *editScope) add(m *pb.Mutation) {
+	switch m := m.Mutation.(type) {
+	case *pb.Mutation_SetChannel:
+		s.channels = append(s.channels, m.SetChannel.Channel, m.SetChannel.Config.GetName())
+	case *pb.Mutation_SetGraphProperties:
+		s.props = true
+	case *pb.Mutation_SetNode:
+		s.nodes = append(s.nodes, m.SetNode.Node, m.SetNode.Config.GetName())
+	case *pb.Mutation_SetPosition:
+		s.nodes = append(s.nodes, m.SetPosition.Node)
+	}
+}
+
+// edit applies changes to the graph as a single edit based on version base,
+// which is recorded and published. If apply returns an error, the graph is
+// restored to how it was before.
+func (sg *serveGraph) edit(base uint64, scope editScope, apply func() error) (*pb.EditResponse, error) {
+	sg.Lock()
+	defer sg.Unlock()
+	if err := sg.checkVersion(base, scope); err != nil {
+		return &pb.EditResponse{}, err
+	}
+	before := sg.snapshot()
+	if err := apply(); err != nil {
+		if e := diffStates(before, sg.snapshot()); e != nil {
+			sg.restore(&e.before)
+		}
+		return &pb.EditResponse{}, err
+	}
+	return &pb.EditResponse{Version: sg.recordEdit(before)}, nil
+}
+
+// mutate applies a single mutation. The graph must be locked.
+func (sg *serveGraph) mutate(m *pb.Mutation) error {
+	switch m := m.Mutation.(type) {
+	case *pb.Mutation_SetChannel:
+		return sg.setChannel(m.SetChannel)
+	case *pb.Mutation_SetGraphProperties:
+		sg.setProperties(m.SetGraphProperties)
+		return nil
+	case *pb.Mutation_SetNode:
+		return sg.setNode(m.SetNode)
+	case *pb.Mutation_SetPosition:
+		return sg.setPosition(m.SetPosition)
+	}
+	return status.Error(codes.InvalidArgument, "empty mutation")
+}
+
+// batch applies mutations in order, and then checks the graph with type
+// inference. The graph must be locked.
+func (sg *serveGraph) batch(ms []*pb.Mutation) error {
+	for i, m := range ms {
+		if err := sg.mutate(m); err != nil {
+			s := status.Convert(err)
+			return status.Errorf(s.Code(), "mutation %d: %s", i, s.Message())
+		}
+	}
+	if err := sg.InferTypes(); err != nil {
+		return status.Errorf(codes.FailedPrecondition, "type inference: %v", err)
+	}
+	return nil
+}
+
+// setChannel creates, changes, or deletes a channel. The graph must be
+// locked.
+func (sg *serveGraph) setChannel(req *pb.SetChannelRequest) error {
+	if req.Channel == "" && req.Config == nil {
+		return status.Error(codes.InvalidArgument, "must provide existing channel or new config")
+	}
+
+	var nps map[model.NodePin]struct{}
+
+	if req.Config != nil {
+		// TODO: More validation (name, type, etc)
+		if req.Config.Name == "nil" {
+			return status.Errorf(codes.InvalidArgument, "channels may not be named %q", req.Config.Name)
+		}
+
+		if req.Channel != req.Config.Name {
+			// Check that the new name is available...
+			if _, found := sg.Channels[req.Config.Name]; found {
+				return status.Errorf(codes.AlreadyExists, "target name %q already exists", req.Config.Name)
+			}
+		}
+
+		// Convert the []pb.NodePin into a set of model.NodePin, and validate
+		// that the pins exist at the same time.
+		nps = make(map[model.NodePin]struct{}, len(req.Config.Pins))
+		for _, np := range req.Config.Pins {
+			n, err := sg.lookupNode(np.Node)
+			if err != nil {
+				return err
+			}
+			if _, found := n.Connections[np.Pin]; !found {
+				return status.Errorf(codes.NotFound, "node %q pin %q does not exist", np.Node, np.Pin)
+			}
+			nps[model.NodePin{Node: np.Node, Pin: np.Pin}] = struct{}{}
+		}
+	}
+
+	if req.Channel != "" {
+		old, err := sg.lookupChannel(req.Channel)
+		if err != nil {
+			return err
+		}
+
+		// Update existing channel data by deleting the old one from the map
+		// and any connections, then setting the new one below.
+		sg.DeleteChannel(old)
+
+		if req.Config == nil {
+			// Deletion was intended, job complete.
+			return nil
+		}
+	}
+
+	// Set entry in map, update connections on node side.
+	sg.Channels[req.Config.Name] = &model.Channel{
+		Name:     req.Config.Name,
+		Capacity: int(req.Config.Cap),
+		Pins:     nps,
+	}
+	for np := range nps {
+		sg.Nodes[np.Node].Connections[np.Pin] = req.Config.Name
+	}
+	return nil
+}
+
+// setProperties changes the graph properties. The graph must be locked.
+func (sg *serveGraph) setProperties(req *pb.SetGraphPropertiesRequest) {
+	sg.Name = req.Name
+	sg.PackagePath = req.PackagePath
+	sg.IsCommand = req.IsCommand
+	sg.OutputDir = req.OutputDir
+	sg.UseContext = req.UseContext
+	sg.Telemetry = req.Telemetry
+	sg.Generics = req.Generics
+}
+
+// setNode creates, changes, or deletes a node. The graph must be locked.
+func (sg *serveGraph) setNode(req *pb.SetNodeRequest) error {
+	if req.Node == "" && req.Config == nil {
+		return status.Error(codes.InvalidArgument, "must provide existing node or new config")
+	}
+
+	var part model.Part
+	if req.Config != nil {
+		if req.Node != req.Config.Name {
+			// Check the new name is available...
+			if _, exists := sg.Nodes[req.Config.Name]; exists {
+				return status.Errorf(codes.AlreadyExists, "node %q already exists", req.Config.Name)
+			}
+		}
+
+		p, err := (&model.PartJSON{
+			Part: req.Config.PartCfg,
+			Type: req.Config.PartType,
+		}).Unmarshal()
+		if err != nil {
+			return status.Errorf(codes.FailedPrecondition, "part unmarshal: %v", err)
+		}
+		part = p
+	}
+
+	var conns map[string]string
+	if req.Node != "" {
+		old, err := sg.lookupNode(req.Node)
+		if err != nil {
+			return err
+		}
+
+		// Delete old node, only clean up channels if deleting this node
+		// is the intention.
+		sg.DeleteNode(old, req.Config == nil)
+
+		if req.Config == nil {
+			// Deletion was intended, job complete.
+			return nil
+		}
+
+		conns = old.Connections
+		log.Printf("old.Connections = %v", conns)
+	}
+
+	n := &model.Node{
+		Name:           req.Config.Name,
+		Comment:        req.Config.Comment,
+		Multiplicity:   req.Config.Multiplicity,
+		Enabled:        req.Config.Enabled,
+		Wait:           req.Config.Wait,
+		Recover:        req.Config.Recover,
+		Restart:        model.RestartPolicy(req.Config.Restart),
+		MaxRestarts:    uint(req.Config.MaxRestarts),
+		RestartBackoff: req.Config.RestartBackoff,
+		RestartMetrics: req.Config.RestartMetrics,
+		Part:           part,
+		X:              req.Config.X,
+		Y:              req.Config.Y,
+		Connections:    conns,
+	}
+	sg.Nodes[req.Config.Name] = n
+	n.RefreshConnections()
+	if err := sg.LinkNode(n); err != nil {
+		// Not fatal; the file might not have been written yet.
+		log.Printf("Couldn't link node %q: %v", n.Name, err)
+	}
+	sg.RefreshChannelsPins() // Changing the part might have changed available pins.
+	return nil
+}
+
+// setPosition moves a node. The graph must be locked.
+func (sg *serveGraph) setPosition(req *pb.SetPositionRequest) error {
+	n, err := sg.lookupNode(req.Node)
+	if err != nil {
+		return err
+	}
+	n.X, n.Y = req.X, req.Y
+	return nil
+}
@@ -15,6 +15,9 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -22,8 +25,10 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/google/shenzhen-go/model"
@@ -34,12 +39,19 @@ import (
 var identifierRE = regexp.MustCompile(`^[_a-zA-Z][_a-zA-Z0-9]*$`)
 
 // GuessPackagePath attempts to find a sensible package path.
+// If srcPath is inside a module, the package path is derived from the
+// module path and srcPath (without extension), so each graph gets its own
+// subdirectory. Otherwise it is srcPath (without extension) relative to
+// ${GOPATH}/src.
 func GuessPackagePath(srcPath string) (string, error) {
-	gp, err := source.GoPath()
+	abs, err := filepath.Abs(srcPath)
 	if err != nil {
 		return "", err
 	}
-	abs, err := filepath.Abs(srcPath)
+	if ip, err := moduleImportPath(strings.TrimSuffix(abs, filepath.Ext(abs))); err != source.ErrNoModule {
+		return ip, err
+	}
+	gp, err := source.GoPath()
 	if err != nil {
 		return "", err
 	}
@@ -50,6 +62,85 @@ func GuessPackagePath(srcPath string) (string, error) {
 	return strings.TrimSuffix(rel, filepath.Ext(rel)), nil
 }
 
+// moduleImportPath returns the import path of the package in dir, if dir is
+// inside a module, or source.ErrNoModule.
+func moduleImportPath(dir string) (string, error) {
+	modDir, modPath, err := source.FindModule(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(modDir, dir)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(modPath, filepath.ToSlash(rel)), nil
+}
+
+// pkgLocation says where the Go package for a graph lives.
+type pkgLocation struct {
+	Dir        string // where generated.go goes
+	ImportPath string // import path for the package
+	Module     bool   // true if Dir is inside a module
+}
+
+// locatePackage figures out where to put the generated package. If the
+// graph has an OutputDir, it is used (relative to the directory containing
+// the graph file). Otherwise, graph files inside a module are generated into
+// a directory next to the graph file, named after it (as GuessPackagePath
+// assumes), and all others into ${GOPATH}/src/${g.PackagePath}. Inside a
+// module, the import path of the directory must be g.PackagePath, since that
+// is how other graphs import it.
+func locatePackage(g *model.Graph) (*pkgLocation, error) {
+	abs, err := filepath.Abs(g.FilePath)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(abs)
+	if g.OutputDir != "" {
+		dir = filepath.Join(dir, filepath.FromSlash(g.OutputDir))
+	}
+	_, _, err = source.FindModule(dir)
+	switch {
+	case err == nil:
+		if g.OutputDir == "" {
+			dir = strings.TrimSuffix(abs, filepath.Ext(abs))
+		}
+		ip, err := moduleImportPath(dir)
+		if err != nil {
+			return nil, err
+		}
+		if ip != g.PackagePath {
+			return nil, fmt.Errorf("output directory %s has import path %q, but the package path is %q; change the package path or set an output directory", dir, ip, g.PackagePath)
+		}
+		return &pkgLocation{Dir: dir, ImportPath: ip, Module: true}, nil
+	case err != source.ErrNoModule:
+		return nil, err
+	case g.OutputDir != "":
+		return &pkgLocation{Dir: dir, ImportPath: g.PackagePath}, nil
+	}
+	gp, err := source.GoPath()
+	if err != nil {
+		return nil, err
+	}
+	return &pkgLocation{
+		Dir:        filepath.Join(gp, "src", filepath.FromSlash(g.PackagePath)),
+		ImportPath: g.PackagePath,
+	}, nil
+}
+
+// goCmd returns a command for running the go tool on the package at loc,
+// in module mode if the package is in a module, and GOPATH mode otherwise.
+func (loc *pkgLocation) goCmd(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	mode := "GO111MODULE=off"
+	if loc.Module {
+		mode = "GO111MODULE=on"
+		cmd.Dir = loc.Dir
+	}
+	cmd.Env = append(os.Environ(), mode)
+	return cmd
+}
+
 // SaveJSONFile saves the JSON-encoded Graph to the SourcePath.
 func SaveJSONFile(g *model.Graph) error {
 	f, err := ioutil.TempFile(filepath.Dir(g.FilePath), filepath.Base(g.FilePath))
@@ -66,59 +157,184 @@ func SaveJSONFile(g *model.Graph) error {
 	return os.Rename(f.Name(), g.FilePath)
 }
 
-// GeneratePackage writes the Go view of the graph to a file called generated.go in
-// ${GOPATH}/src/${g.PackagePath}/, returning the full path.
-// Messages from the generation process will be written to out.
+// Check checks the graph for problems, writing any diagnostics to out.
+// It returns an error if any of the diagnostics are errors.
+func Check(out io.Writer, g *model.Graph) error {
+	fmt.Fprintln(out, "[Check]")
+	ds := g.Check()
+	for _, d := range ds {
+		fmt.Fprintln(out, d)
+	}
+	if err := ds.Err(); err != nil {
+		fmt.Fprintln(out, "(Check failed)")
+		return err
+	}
+	fmt.Fprintln(out, "(Check succeeded)")
+	return nil
+}
+
+// GeneratePackage checks the graph and then writes the Go view of the graph
+// to the package directory, as a file called generated.go containing main
+// or Run, and a file for each node. It returns the full path of
+// generated.go. Files for nodes that no longer exist are removed. Each file
+// records the graph it came from, and a package directory containing files
+// from another graph is an error, rather than being overwritten. For graphs
+// inside a module, the package directory is next to the graph file, named
+// after it; otherwise it is ${GOPATH}/src/${g.PackagePath}/. Either can be
+// overridden with g.OutputDir. Messages from the generation process will be
+// written to out.
 func GeneratePackage(out io.Writer, g *model.Graph) (string, error) {
-	fmt.Fprintln(out, "[GeneratePackage]")
-	gp, err := source.GoPath()
+	loc, _, err := generatePackage(out, g)
 	if err != nil {
-		fmt.Fprintf(out, "source.GoPath() = %v\n(GeneratePackage failed)\n", err)
 		return "", err
 	}
-	pp := filepath.Join(gp, "src", g.PackagePath)
-	if err := os.MkdirAll(pp, os.FileMode(0755)); err != nil {
-		fmt.Fprintf(out, "os.MkdirAll(pp, 0755) = %v)\n", err)
-		return "", err
+	return filepath.Join(loc.Dir, model.MainFileName), nil
+}
+
+// generatePackage is GeneratePackage, but returns where the package is,
+// and a map from lines of the generated files to the code of nodes.
+func generatePackage(out io.Writer, g *model.Graph) (*pkgLocation, *model.LineMap, error) {
+	if err := Check(out, g); err != nil {
+		return nil, nil, err
 	}
-	mp := filepath.Join(pp, "generated.go")
-	f, err := os.Create(mp)
+	if err := generateSubgraphs(out, g, make(map[*model.Graph]bool)); err != nil {
+		return nil, nil, err
+	}
+	return writePackage(out, g)
+}
+
+// generateSubgraphs checks and generates the packages of all subgraphs of g,
+// and their subgraphs, in turn. Check (and so, Link) must have succeeded on g.
+func generateSubgraphs(out io.Writer, g *model.Graph, done map[*model.Graph]bool) error {
+	for _, n := range g.Nodes {
+		r, ok := n.Part.(model.GraphRef)
+		if !ok || r.Subgraph() == nil || done[r.Subgraph()] {
+			continue
+		}
+		sub := r.Subgraph()
+		done[sub] = true
+		fmt.Fprintf(out, "[Subgraph %s]\n", sub.FilePath)
+		if err := Check(out, sub); err != nil {
+			return err
+		}
+		if err := generateSubgraphs(out, sub, done); err != nil {
+			return err
+		}
+		loc, _, err := writePackage(out, sub)
+		if err != nil {
+			return err
+		}
+		if loc.ImportPath != sub.PackagePath {
+			err := fmt.Errorf("subgraph %s is generated with import path %q, but its package path is %q", sub.FilePath, loc.ImportPath, sub.PackagePath)
+			fmt.Fprintf(out, "%v\n(GeneratePackage failed)\n", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// writePackage writes the Go view of the graph to generated.go in the
+// package directory, returning where it is and the LineMap of the files.
+func writePackage(out io.Writer, g *model.Graph) (*pkgLocation, *model.LineMap, error) {
+	fmt.Fprintln(out, "[GeneratePackage]")
+	loc, err := locatePackage(g)
 	if err != nil {
-		fmt.Fprintf(out, "os.Create(mp) = %v\n(GeneratePackage failed)\n", err)
-		return "", err
+		fmt.Fprintf(out, "locatePackage(g) = %v\n(GeneratePackage failed)\n", err)
+		return nil, nil, err
 	}
-	defer f.Close()
-	if err := g.WriteGoTo(f); err != nil {
-		fmt.Fprintf(out, "g.WriteGoTo(f) = %v\n(GeneratePackage failed)\n", err)
-		return "", err
+	if err := os.MkdirAll(loc.Dir, os.FileMode(0755)); err != nil {
+		fmt.Fprintf(out, "os.MkdirAll(loc.Dir, 0755) = %v)\n", err)
+		return nil, nil, err
 	}
-	if err := f.Close(); err != nil {
-		fmt.Fprintf(out, "f.Close() = %v\n(GeneratePackage failed)\n", err)
-		return "", err
+	files, lm, err := g.GoFilesWithLineMap()
+	if err != nil {
+		fmt.Fprintf(out, "g.GoFilesWithLineMap() = %v\n(GeneratePackage failed)\n", err)
+		return nil, nil, err
+	}
+	// Another graph generated into the same directory would have its files
+	// overwritten or removed.
+	if sn, ok := generatedFrom(filepath.Join(loc.Dir, model.MainFileName)); ok && sn != g.SourceName() {
+		err := fmt.Errorf("%s is the package of graph %q; change the package path or output directory", loc.Dir, sn)
+		fmt.Fprintf(out, "%v\n(GeneratePackage failed)\n", err)
+		return nil, nil, err
+	}
+	// Remove files for nodes of this graph that no longer exist.
+	infos, err := ioutil.ReadDir(loc.Dir)
+	if err != nil {
+		fmt.Fprintf(out, "ioutil.ReadDir(loc.Dir) = %v\n(GeneratePackage failed)\n", err)
+		return nil, nil, err
+	}
+	for _, fi := range infos {
+		if _, keep := files[fi.Name()]; keep || !model.IsNodeFileName(fi.Name()) {
+			continue
+		}
+		p := filepath.Join(loc.Dir, fi.Name())
+		if sn, ok := generatedFrom(p); ok && sn != g.SourceName() {
+			continue
+		}
+		if err := os.Remove(p); err != nil {
+			fmt.Fprintf(out, "os.Remove(p) = %v\n(GeneratePackage failed)\n", err)
+			return nil, nil, err
+		}
+		fmt.Fprintf(out, "removed %s\n", p)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := filepath.Join(loc.Dir, name)
+		if err := ioutil.WriteFile(p, files[name], os.FileMode(0644)); err != nil {
+			fmt.Fprintf(out, "ioutil.WriteFile(p) = %v\n(GeneratePackage failed)\n", err)
+			return nil, nil, err
+		}
+		fmt.Fprintf(out, "wrote %s\n", p)
 	}
 	fmt.Fprintln(out, "(GeneratePackage succeeded)")
-	return mp, nil
+	return loc, lm, nil
+}
+
+// generatedFrom reads the file to find the graph it was generated from.
+func generatedFrom(path string) (string, bool) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return model.GeneratedFrom(src)
 }
 
 // GenerateRunner generates a `go run`-able; either the output package itself,
-// or the package together with a temporary runner, returning the full path to
-// the runnable path. Messages from the generation process will be written to out.
-func GenerateRunner(out io.Writer, g *model.Graph) (string, error) {
-	gp, err := GeneratePackage(out, g)
+// or the package together with a temporary runner, returning a `go run`
+// command for the runnable that is bound to ctx. Messages from the generation
+// process will be written to out. The returned cleanup func removes the
+// temporary runner, if any, and should be called once the command is done.
+func GenerateRunner(ctx context.Context, out io.Writer, g *model.Graph) (cmd *exec.Cmd, cleanup func(), err error) {
+	loc, _, err := generatePackage(out, g)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 	if g.IsCommand {
-		return gp, nil
+		return loc.goCmd(ctx, "run", loc.Dir), func() {}, nil
 	}
 	fmt.Fprintln(out, "[GenerateRunner]")
-	path, err := writeTempRunner(g)
+	if len(g.Ports()) > 0 {
+		err := errors.New("graph has ports, so it must be run from other Go code")
+		fmt.Fprintf(out, "%v\n(GenerateRunner failed)\n", err)
+		return nil, nil, err
+	}
+	dir, path, err := writeTempRunner(g, loc)
 	if err != nil {
-		fmt.Fprintf(out, "writeTempRunner(g) = %v\n(GenerateRunner failed)\n", err)
-		return "", err
+		fmt.Fprintf(out, "writeTempRunner(g, loc) = %v\n(GenerateRunner failed)\n", err)
+		return nil, nil, err
 	}
 	fmt.Fprintln(out, "(GenerateRunner succeeded)")
-	return path, nil
+	cleanup = func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Printf("Couldn't remove temporary runner: %v", err)
+		}
+	}
+	return loc.goCmd(ctx, "run", path), cleanup, nil
 }
 
 func runCmd(out io.Writer, cmd *exec.Cmd) error {
@@ -134,37 +350,132 @@ func runCmd(out io.Writer, cmd *exec.Cmd) error {
 }
 
 // Build saves the graph as Go source code and tries to "go build" it.
-// Console output from the command (*not* the compiled program) is written to out.
+// Console output from the command (*not* the compiled program) is written to out,
+// followed by the nodes containing any compile errors.
 func Build(out io.Writer, g *model.Graph) error {
-	if _, err := GeneratePackage(out, g); err != nil {
-		return err
+	_, err := buildPackage(out, g)
+	return err
+}
+
+// buildPackage is Build, but also returns the compile errors in the package.
+func buildPackage(out io.Writer, g *model.Graph) ([]*CompileError, error) {
+	loc, lm, err := generatePackage(out, g)
+	if err != nil {
+		return nil, err
+	}
+	cmd := loc.goCmd(context.Background(), `build`, loc.ImportPath)
+	output := &bytes.Buffer{}
+	if err := runCmd(io.MultiWriter(out, output), cmd); err != nil {
+		ces := compileErrors(output.String(), cmd.Dir, loc.Dir, lm)
+		writeCompileErrors(out, ces)
+		return ces, err
 	}
-	return runCmd(out, exec.Command(`go`, `build`, g.PackagePath))
+	return nil, nil
 }
 
 // Install saves the graph as Go source code and tries to "go install" it.
 // Console output from the command (*not* the compiled program) is written to out.
 func Install(out io.Writer, g *model.Graph) error {
-	if _, err := GeneratePackage(out, g); err != nil {
+	loc, _, err := generatePackage(out, g)
+	if err != nil {
+		return err
+	}
+	return runCmd(out, loc.goCmd(context.Background(), `install`, loc.ImportPath))
+}
+
+// Test saves the graph as Go source code, together with the test harness
+// (and a test scaffold, if there are no tests yet), and tries to "go test" it.
+// Console output from the command is written to out, followed by the nodes
+// containing any compile errors.
+func Test(out io.Writer, g *model.Graph) error {
+	loc, lm, err := generatePackage(out, g)
+	if err != nil {
+		return err
+	}
+	if err := writeTests(out, g, loc); err != nil {
+		return err
+	}
+	cmd := loc.goCmd(context.Background(), `test`, loc.ImportPath)
+	output := &bytes.Buffer{}
+	if err := runCmd(io.MultiWriter(out, output), cmd); err != nil {
+		writeCompileErrors(out, compileErrors(output.String(), cmd.Dir, loc.Dir, lm))
 		return err
 	}
-	return runCmd(out, exec.Command(`go`, `install`, g.PackagePath))
+	return nil
 }
 
-func writeTempRunner(g *model.Graph) (string, error) {
-	fn := filepath.Join(os.TempDir(), fmt.Sprintf("shenzhen-go-runner.%s.go", g.PackageName()))
+// writeTests writes the test harness to the package directory, and the test
+// scaffold if it doesn't exist already.
+func writeTests(out io.Writer, g *model.Graph, loc *pkgLocation) error {
+	fmt.Fprintln(out, "[GenerateTests]")
+	harness, err := g.TestHarness()
+	if err != nil {
+		fmt.Fprintf(out, "g.TestHarness() = %v\n(GenerateTests failed)\n", err)
+		return err
+	}
+	p := filepath.Join(loc.Dir, model.TestHarnessFileName)
+	if err := ioutil.WriteFile(p, harness, os.FileMode(0644)); err != nil {
+		fmt.Fprintf(out, "ioutil.WriteFile(p) = %v\n(GenerateTests failed)\n", err)
+		return err
+	}
+	fmt.Fprintf(out, "wrote %s\n", p)
+
+	p = filepath.Join(loc.Dir, model.TestScaffoldFileName)
+	if _, err := os.Stat(p); err == nil {
+		fmt.Fprintf(out, "kept %s\n", p)
+		fmt.Fprintln(out, "(GenerateTests succeeded)")
+		return nil
+	}
+	scaffold, err := g.TestScaffold()
+	if err != nil {
+		fmt.Fprintf(out, "g.TestScaffold() = %v\n(GenerateTests failed)\n", err)
+		return err
+	}
+	if err := ioutil.WriteFile(p, scaffold, os.FileMode(0644)); err != nil {
+		fmt.Fprintf(out, "ioutil.WriteFile(p) = %v\n(GenerateTests failed)\n", err)
+		return err
+	}
+	fmt.Fprintf(out, "wrote %s\n", p)
+	fmt.Fprintln(out, "(GenerateTests succeeded)")
+	return nil
+}
+
+// writeTempRunner writes a main package that imports and runs the package,
+// in a new temporary directory, returning the directory and the file.
+// In GOPATH mode it can go in the temp dir, but in module mode it must be
+// inside the module, so it goes in a hidden directory in the package dir.
+func writeTempRunner(g *model.Graph, loc *pkgLocation) (dir, fn string, err error) {
+	parent := ""
+	if loc.Module {
+		parent = loc.Dir
+	}
+	dir, err = ioutil.TempDir(parent, ".shenzhen-go-runner")
+	if err != nil {
+		return "", "", err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dir)
+		}
+	}()
+	fn = filepath.Join(dir, fmt.Sprintf("shenzhen-go-runner.%s.go", g.PackageName()))
 	f, err := os.Create(fn)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	defer f.Close()
-	if err := goRunnerTemplate.Execute(f, g); err != nil {
-		return "", err
+	err = goRunnerTemplate.Execute(f, struct {
+		ImportPath, PackageName string
+		UseContext              bool
+		HasParameters           bool
+	}{loc.ImportPath, g.PackageName(), g.UseContext, len(g.Parameters) > 0})
+	if err != nil {
+		return "", "", err
 	}
 	if err := f.Close(); err != nil {
-		return "", err
+		return "", "", err
 	}
-	return fn, nil
+	return dir, fn, nil
 }
 
 // Graph handles displaying/editing a graph.
@@ -187,5 +498,13 @@ func renderGraph(g *serveGraph, w http.ResponseWriter, r *http.Request, uiParams
 		return
 	}
 
-	view.Graph(w, g.Graph, uiParams)
+	if q.Get("format") == "svg" {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		if err := view.SVG(w, g.Graph); err != nil {
+			log.Printf("Couldn't render SVG: %v", err)
+		}
+		return
+	}
+
+	view.Graph(w, g.Graph, g.version(), uiParams)
 }
@@ -0,0 +1,294 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/shenzhen-go/model"
+	"github.com/google/shenzhen-go/model/pin"
+	"github.com/google/shenzhen-go/parts"
+)
+
+func TestLocatePackageModule(t *testing.T) {
+	root, err := ioutil.TempDir("", "locatepackage")
+	if err != nil {
+		t.Fatalf("TempDir() = error %v", err)
+	}
+	defer os.RemoveAll(root)
+	if err := ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() = error %v", err)
+	}
+	fp := filepath.Join(root, "pipes", "foo.szgo")
+
+	pp, err := GuessPackagePath(fp)
+	if err != nil {
+		t.Fatalf("GuessPackagePath(%q) = error %v", fp, err)
+	}
+	if want := "example.com/m/pipes/foo"; pp != want {
+		t.Errorf("GuessPackagePath(%q) = %q, want %q", fp, pp, want)
+	}
+
+	tests := []struct {
+		outputDir, packagePath, wantDir string
+		wantErr                         bool
+	}{
+		{"", "example.com/m/pipes/foo", filepath.Join(root, "pipes", "foo"), false},
+		{"", "example.com/m/bar", "", true},
+		{"", "example.com/m", "", true},
+		{"", "example.com/other/foo", "", true},
+		{"foo", "example.com/m/pipes/foo", filepath.Join(root, "pipes", "foo"), false},
+		{"../bar", "example.com/m/bar", filepath.Join(root, "bar"), false},
+		{"../bar", "example.com/m/pipes/foo", "", true},
+	}
+	for _, test := range tests {
+		g := model.NewGraph(fp, "foo.szgo", test.packagePath)
+		g.OutputDir = test.outputDir
+		loc, err := locatePackage(g)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("locatePackage(OutputDir: %q, PackagePath: %q) = %v, want error", test.outputDir, test.packagePath, loc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("locatePackage(OutputDir: %q, PackagePath: %q) = error %v", test.outputDir, test.packagePath, err)
+		}
+		if !loc.Module {
+			t.Errorf("locatePackage(OutputDir: %q, PackagePath: %q).Module = false, want true", test.outputDir, test.packagePath)
+		}
+		if got, want := loc.Dir, test.wantDir; got != want {
+			t.Errorf("locatePackage(OutputDir: %q, PackagePath: %q).Dir = %q, want %q", test.outputDir, test.packagePath, got, want)
+		}
+		if got, want := loc.ImportPath, test.packagePath; got != want {
+			t.Errorf("locatePackage(OutputDir: %q, PackagePath: %q).ImportPath = %q, want %q", test.outputDir, test.packagePath, got, want)
+		}
+	}
+}
+
+func TestWritePackageSharedDir(t *testing.T) {
+	root, err := ioutil.TempDir("", "writepackage")
+	if err != nil {
+		t.Fatalf("TempDir() = error %v", err)
+	}
+	defer os.RemoveAll(root)
+	if err := ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() = error %v", err)
+	}
+	// All the graphs are generated into package p.
+	graph := func(file, node string) *model.Graph {
+		g := model.NewGraph(filepath.Join(root, file), file, "example.com/m/p")
+		od, err := filepath.Rel(filepath.Dir(file), "p")
+		if err != nil {
+			t.Fatalf("Rel() = error %v", err)
+		}
+		g.OutputDir = filepath.ToSlash(od)
+		g.Nodes[node] = &model.Node{
+			Name:         node,
+			Part:         parts.NewCode(nil, "", "", "", nil),
+			Enabled:      true,
+			Multiplicity: "1",
+		}
+		return g
+	}
+	out := ioutil.Discard
+	a := graph("a.szgo", "A")
+	if _, _, err := writePackage(out, a); err != nil {
+		t.Fatalf("writePackage(a) = error %v", err)
+	}
+	aFile := filepath.Join(root, "p", model.NodeFileName(a.Nodes["A"]))
+
+	// A stray node file from another graph is left alone.
+	stray := filepath.Join(root, "p", "generated.C.node.go")
+	if err := ioutil.WriteFile(stray, []byte("// Code generated by Shenzhen Go from node \"C\" of graph \"c.szgo\". DO NOT EDIT.\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() = error %v", err)
+	}
+	if _, _, err := writePackage(out, a); err != nil {
+		t.Fatalf("writePackage(a) again = error %v", err)
+	}
+	if _, err := os.Stat(stray); err != nil {
+		t.Errorf("node file of another graph was removed: %v", err)
+	}
+
+	// b can't use a's package directory.
+	if _, _, err := writePackage(out, graph("b.szgo", "B")); err == nil {
+		t.Error("writePackage(b) = nil error, want error for directory shared with a")
+	}
+	if _, err := os.Stat(aFile); err != nil {
+		t.Errorf("node file of a was removed: %v", err)
+	}
+
+	// Nor can another graph with the same file name.
+	if _, _, err := writePackage(out, graph(filepath.Join("x", "a.szgo"), "A")); err == nil {
+		t.Error("writePackage(x/a) = nil error, want error for directory shared with a")
+	}
+
+	// Removing a node of a removes its file.
+	delete(a.Nodes, "A")
+	if _, _, err := writePackage(out, a); err != nil {
+		t.Fatalf("writePackage(a without A) = error %v", err)
+	}
+	if _, err := os.Stat(aFile); !os.IsNotExist(err) {
+		t.Errorf("os.Stat(%s) = %v, want not exist", aFile, err)
+	}
+}
+
+func TestBuildSubgraphParameters(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skipf("go not found: %v", err)
+	}
+	root, err := ioutil.TempDir("", "subgraphparams")
+	if err != nil {
+		t.Fatalf("TempDir() = error %v", err)
+	}
+	defer os.RemoveAll(root)
+	if err := ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() = error %v", err)
+	}
+	node := func(name string, part model.Part, conns map[string]string) *model.Node {
+		return &model.Node{
+			Name:         name,
+			Part:         part,
+			Enabled:      true,
+			Multiplicity: "1",
+			Connections:  conns,
+		}
+	}
+	ports := func(g *model.Graph) {
+		g.Nodes["in"] = node("in", &parts.InputPort{}, map[string]string{"output": "a"})
+		g.Nodes["out"] = node("out", &parts.OutputPort{}, map[string]string{"input": "b"})
+		g.Channels["a"] = &model.Channel{Name: "a"}
+		g.Channels["b"] = &model.Channel{Name: "b"}
+	}
+
+	inner := model.NewGraph(filepath.Join(root, "inner.szgo"), "inner.szgo", "example.com/m/inner")
+	inner.Parameters = map[string]*model.Parameter{
+		"factor": {Name: "factor", Type: "int", Default: "3"},
+	}
+	ports(inner)
+	inner.Nodes["scale"] = node("scale", parts.NewCode(nil, "", "for x := range input {\n\toutput <- x * factor\n}", "close(output)", pin.NewMap(
+		&pin.Definition{Name: "input", Type: "int", Direction: pin.Input},
+		&pin.Definition{Name: "output", Type: "int", Direction: pin.Output},
+	)), map[string]string{"input": "a", "output": "b"})
+	inner.RefreshChannelsPins()
+	if err := SaveJSONFile(inner); err != nil {
+		t.Fatalf("SaveJSONFile(inner) = error %v", err)
+	}
+
+	outer := model.NewGraph(filepath.Join(root, "outer.szgo"), "outer.szgo", "example.com/m/outer")
+	ports(outer)
+	outer.Nodes["sub"] = node("sub", &parts.Subgraph{File: "inner.szgo"}, map[string]string{"in": "a", "out": "b"})
+	if err := outer.Link(); err != nil {
+		t.Fatalf("outer.Link() = error %v", err)
+	}
+	if ds := outer.Check(); ds.HasErrors() {
+		t.Fatalf("outer.Check() = %v", ds.Err())
+	}
+
+	out := &bytes.Buffer{}
+	if _, err := GeneratePackage(out, inner); err != nil {
+		t.Fatalf("GeneratePackage(inner) = error %v\n%s", err, out)
+	}
+	if err := Build(out, outer); err != nil {
+		t.Fatalf("Build(outer) = error %v\n%s", err, out)
+	}
+}
+
+func TestTestExtraOutput(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skipf("go not found: %v", err)
+	}
+	const testSrc = `package pipe
+
+import "testing"
+
+func TestRun(t *testing.T) {
+	RunTestCases(t, []TestCase{{Name: "echo", In: []int{1, 2}, Out: []int{1, 2}}})
+}
+`
+	tests := []struct {
+		desc, body string
+		useContext bool
+		wantErr    string
+	}{
+		{
+			desc: "exact",
+			body: "for x := range input {\n\toutput <- x\n}",
+		},
+		{
+			desc:    "extra value",
+			body:    "for x := range input {\n\toutput <- x\n}\noutput <- 99",
+			wantErr: "out got [1 2 99], want [1 2]",
+		},
+		{
+			desc:       "extra value with context",
+			body:       "for x := range input {\n\toutput <- x\n}\noutput <- 99",
+			useContext: true,
+			wantErr:    "out got [1 2 99], want [1 2]",
+		},
+	}
+	for _, test := range tests {
+		root, err := ioutil.TempDir("", "testextraoutput")
+		if err != nil {
+			t.Fatalf("TempDir() = error %v", err)
+		}
+		defer os.RemoveAll(root)
+		if err := ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0644); err != nil {
+			t.Fatalf("WriteFile() = error %v", err)
+		}
+		dir := filepath.Join(root, "pipe")
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatalf("Mkdir() = error %v", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, model.TestScaffoldFileName), []byte(testSrc), 0644); err != nil {
+			t.Fatalf("WriteFile() = error %v", err)
+		}
+
+		g := model.NewGraph(filepath.Join(root, "pipe.szgo"), "pipe.szgo", "example.com/m/pipe")
+		g.UseContext = test.useContext
+		for _, n := range []*model.Node{
+			{Name: "in", Part: &parts.InputPort{}, Connections: map[string]string{"output": "a"}},
+			{Name: "out", Part: &parts.OutputPort{}, Connections: map[string]string{"input": "b"}},
+			{Name: "echo", Part: parts.NewCode(nil, "", test.body, "close(output)", pin.NewMap(
+				&pin.Definition{Name: "input", Type: "int", Direction: pin.Input},
+				&pin.Definition{Name: "output", Type: "int", Direction: pin.Output},
+			)), Connections: map[string]string{"input": "a", "output": "b"}},
+		} {
+			n.Enabled, n.Multiplicity, n.Wait = true, "1", true
+			g.Nodes[n.Name] = n
+		}
+		g.Channels["a"] = &model.Channel{Name: "a"}
+		g.Channels["b"] = &model.Channel{Name: "b"}
+		g.RefreshChannelsPins()
+
+		out := &bytes.Buffer{}
+		err = Test(out, g)
+		if test.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: Test() = error %v\n%s", test.desc, err, out)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(out.String(), test.wantErr) {
+			t.Errorf("%s: Test() = error %v, want output containing %q\n%s", test.desc, err, test.wantErr, out)
+		}
+	}
+}
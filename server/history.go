@@ -0,0 +1,263 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"reflect"
+
+	"github.com/google/shenzhen-go/model"
+)
+
+// maxHistory is the number of edits that can be undone.
+const maxHistory = 100
+
+// graphProps are the properties of a graph set by SetGraphProperties.
+type graphProps struct {
+	Name, PackagePath, OutputDir               string
+	IsCommand, UseContext, Telemetry, Generics bool
+}
+
+// graphState is the editable state of some or all of a graph. A nil node
+// or channel means that it doesn't exist.
+type graphState struct {
+	props    graphProps
+	nodes    map[string]*model.Node
+	channels map[string]*model.Channel
+}
+
+// edit is an invertible change to a graph: undoing it restores the before
+// state, and redoing it restores the after state. Only the nodes and
+// channels that changed are recorded.
+type edit struct {
+	before, after graphState
+}
+
+// history records edits to a graph, so they can be undone and redone.
+type history struct {
+	undo, redo []*edit
+}
+
+// snapshot returns a copy of the editable state of the whole graph.
+func (sg *serveGraph) snapshot() *graphState {
+	s := &graphState{
+		props: graphProps{
+			Name:        sg.Name,
+			PackagePath: sg.PackagePath,
+			OutputDir:   sg.OutputDir,
+			IsCommand:   sg.IsCommand,
+			UseContext:  sg.UseContext,
+			Telemetry:   sg.Telemetry,
+			Generics:    sg.Generics,
+		},
+		nodes:    make(map[string]*model.Node, len(sg.Nodes)),
+		channels: make(map[string]*model.Channel, len(sg.Channels)),
+	}
+	for nn, n := range sg.Nodes {
+		s.nodes[nn] = copyNode(n)
+	}
+	for cn, c := range sg.Channels {
+		s.channels[cn] = copyChannel(c)
+	}
+	return s
+}
+
+// recordEdit records and publishes the changes made to the graph since
+// the snapshot before was taken, if there are any. Recording an edit
+// discards any edits that were undone. It returns the resulting version of
+// the graph. The graph must be locked.
+func (sg *serveGraph) recordEdit(before *graphState) uint64 {
+	e := diffStates(before, sg.snapshot())
+	if e == nil {
+		return sg.version()
+	}
+	h := &sg.history
+	h.undo = append(h.undo, e)
+	if len(h.undo) > maxHistory {
+		h.undo = h.undo[len(h.undo)-maxHistory:]
+	}
+	h.redo = nil
+	return sg.publish(e)
+}
+
+// diffStates returns an edit containing only the nodes and channels that
+// differ between two snapshots, or nil if nothing differs.
+func diffStates(before, after *graphState) *edit {
+	e := &edit{
+		before: graphState{
+			props:    before.props,
+			nodes:    make(map[string]*model.Node),
+			channels: make(map[string]*model.Channel),
+		},
+		after: graphState{
+			props:    after.props,
+			nodes:    make(map[string]*model.Node),
+			channels: make(map[string]*model.Channel),
+		},
+	}
+	changed := before.props != after.props
+	nodeNames := make(map[string]bool)
+	for nn := range before.nodes {
+		nodeNames[nn] = true
+	}
+	for nn := range after.nodes {
+		nodeNames[nn] = true
+	}
+	for nn := range nodeNames {
+		b, a := before.nodes[nn], after.nodes[nn]
+		if nodesEqual(b, a) {
+			continue
+		}
+		e.before.nodes[nn], e.after.nodes[nn] = b, a
+		changed = true
+	}
+	chanNames := make(map[string]bool)
+	for cn := range before.channels {
+		chanNames[cn] = true
+	}
+	for cn := range after.channels {
+		chanNames[cn] = true
+	}
+	for cn := range chanNames {
+		b, a := before.channels[cn], after.channels[cn]
+		if channelsEqual(b, a) {
+			continue
+		}
+		e.before.channels[cn], e.after.channels[cn] = b, a
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return e
+}
+
+// undo reverts the most recent edit, returning false if there is nothing
+// to undo. The graph must be locked.
+func (sg *serveGraph) undo() bool {
+	h := &sg.history
+	if len(h.undo) == 0 {
+		return false
+	}
+	e := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	sg.restore(&e.before)
+	sg.publish(&edit{before: e.after, after: e.before})
+	h.redo = append(h.redo, e)
+	return true
+}
+
+// redo reapplies the most recently undone edit, returning false if there
+// is nothing to redo. The graph must be locked.
+func (sg *serveGraph) redo() bool {
+	h := &sg.history
+	if len(h.redo) == 0 {
+		return false
+	}
+	e := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	sg.restore(&e.after)
+	sg.publish(e)
+	h.undo = append(h.undo, e)
+	return true
+}
+
+// restore sets the state of the graph to s, for the nodes and channels
+// in s. Copies are used, so that later changes don't affect the history.
+func (sg *serveGraph) restore(s *graphState) {
+	p := s.props
+	sg.Name, sg.PackagePath, sg.OutputDir = p.Name, p.PackagePath, p.OutputDir
+	sg.IsCommand, sg.UseContext, sg.Telemetry, sg.Generics = p.IsCommand, p.UseContext, p.Telemetry, p.Generics
+	for nn, n := range s.nodes {
+		if n == nil {
+			delete(sg.Nodes, nn)
+			continue
+		}
+		sg.Nodes[nn] = copyNode(n)
+	}
+	for cn, c := range s.channels {
+		if c == nil {
+			delete(sg.Channels, cn)
+			continue
+		}
+		sg.Channels[cn] = copyChannel(c)
+	}
+}
+
+// copyNode copies the editable fields of a node. The part is not copied,
+// because parts are replaced rather than changed.
+func copyNode(n *model.Node) *model.Node {
+	conns := make(map[string]string, len(n.Connections))
+	for pn, cn := range n.Connections {
+		conns[pn] = cn
+	}
+	return &model.Node{
+		Part:           n.Part,
+		Name:           n.Name,
+		Comment:        n.Comment,
+		Enabled:        n.Enabled,
+		Multiplicity:   n.Multiplicity,
+		Wait:           n.Wait,
+		Recover:        n.Recover,
+		Restart:        n.Restart,
+		MaxRestarts:    n.MaxRestarts,
+		RestartBackoff: n.RestartBackoff,
+		RestartMetrics: n.RestartMetrics,
+		X:              n.X,
+		Y:              n.Y,
+		Connections:    conns,
+	}
+}
+
+// copyChannel copies the editable fields of a channel.
+func copyChannel(c *model.Channel) *model.Channel {
+	pins := make(map[model.NodePin]struct{}, len(c.Pins))
+	for np := range c.Pins {
+		pins[np] = struct{}{}
+	}
+	return &model.Channel{
+		Name:     c.Name,
+		Capacity: c.Capacity,
+		Pins:     pins,
+	}
+}
+
+func nodesEqual(a, b *model.Node) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return partsEqual(a.Part, b.Part) &&
+		a.Name == b.Name &&
+		a.Comment == b.Comment &&
+		a.Enabled == b.Enabled &&
+		a.Multiplicity == b.Multiplicity &&
+		a.Wait == b.Wait &&
+		a.Recover == b.Recover &&
+		a.Restart == b.Restart &&
+		a.MaxRestarts == b.MaxRestarts &&
+		a.RestartBackoff == b.RestartBackoff &&
+		a.RestartMetrics == b.RestartMetrics &&
+		a.X == b.X &&
+		a.Y == b.Y &&
+		reflect.DeepEqual(a.Connections, b.Connections)
+}
+
+func channelsEqual(a, b *model.Channel) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Name == b.Name &&
+		a.Capacity == b.Capacity &&
+		reflect.DeepEqual(a.Pins, b.Pins)
+}
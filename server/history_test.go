@@ -0,0 +1,143 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/google/shenzhen-go/model"
+	"github.com/google/shenzhen-go/model/pin"
+	"github.com/google/shenzhen-go/parts"
+	pb "github.com/google/shenzhen-go/proto/go"
+)
+
+func TestUndoRedo(t *testing.T) {
+	node := func(name string, dir pin.Direction) *model.Node {
+		return &model.Node{
+			Name: name,
+			Part: parts.NewCode(nil, "", "", "", pin.Map{
+				"qux": &pin.Definition{Name: "qux", Type: "int", Direction: dir},
+			}),
+			Connections: map[string]string{"qux": "bar"},
+		}
+	}
+	foo := &model.Graph{
+		Name: "foo",
+		Nodes: map[string]*model.Node{
+			"baz":  node("baz", pin.Output),
+			"quux": node("quux", pin.Input),
+		},
+		Channels: map[string]*model.Channel{
+			"bar": {
+				Name: "bar",
+				Pins: map[model.NodePin]struct{}{
+					{Node: "baz", Pin: "qux"}:  {},
+					{Node: "quux", Pin: "qux"}: {},
+				},
+			},
+		},
+	}
+	c := &server{
+		loadedGraphs: map[string]*serveGraph{"foo": {Graph: foo}},
+	}
+	ctx := context.Background()
+	undo := func() error {
+		_, err := c.Undo(ctx, &pb.UndoRequest{Graph: "foo"})
+		return err
+	}
+	redo := func() error {
+		_, err := c.Redo(ctx, &pb.RedoRequest{Graph: "foo"})
+		return err
+	}
+	mustOK := func(what string, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s = %v, want nil", what, err)
+		}
+	}
+
+	if got, want := code(undo()), codes.FailedPrecondition; got != want {
+		t.Errorf("Undo with no history: code = %v, want %v", got, want)
+	}
+
+	_, err := c.SetPosition(ctx, &pb.SetPositionRequest{Graph: "foo", Node: "baz", X: 10, Y: 20})
+	mustOK("SetPosition", err)
+	_, err = c.SetNode(ctx, &pb.SetNodeRequest{Graph: "foo", Node: "baz"})
+	mustOK("SetNode(delete)", err)
+	if foo.Nodes["baz"] != nil {
+		t.Fatal("node baz still exists after deleting it")
+	}
+	if foo.Nodes["quux"].Connections["qux"] == "bar" {
+		t.Fatal("node quux is still connected to bar after deleting baz")
+	}
+
+	// Undo the deletion.
+	mustOK("Undo", undo())
+	baz := foo.Nodes["baz"]
+	if baz == nil {
+		t.Fatal("node baz doesn't exist after undoing its deletion")
+	}
+	if baz.X != 10 || baz.Y != 20 {
+		t.Errorf("baz position = (%v, %v), want (10, 20)", baz.X, baz.Y)
+	}
+	if got, want := foo.Nodes["quux"].Connections["qux"], "bar"; got != want {
+		t.Errorf("quux.Connections[qux] = %q, want %q", got, want)
+	}
+	if bar := foo.Channels["bar"]; bar == nil || !bar.HasPin("baz", "qux") || !bar.HasPin("quux", "qux") {
+		t.Errorf("channel bar = %+v, want it connected to baz.qux and quux.qux", bar)
+	}
+
+	// Undo the position change.
+	mustOK("Undo", undo())
+	if baz := foo.Nodes["baz"]; baz.X != 0 || baz.Y != 0 {
+		t.Errorf("baz position = (%v, %v), want (0, 0)", baz.X, baz.Y)
+	}
+
+	// Redo both.
+	mustOK("Redo", redo())
+	mustOK("Redo", redo())
+	if foo.Nodes["baz"] != nil {
+		t.Error("node baz exists after redoing its deletion")
+	}
+	if got, want := code(redo()), codes.FailedPrecondition; got != want {
+		t.Errorf("Redo with nothing undone: code = %v, want %v", got, want)
+	}
+
+	// A new edit discards undone edits.
+	mustOK("Undo", undo())
+	_, err = c.SetGraphProperties(ctx, &pb.SetGraphPropertiesRequest{Graph: "foo", Name: "new name"})
+	mustOK("SetGraphProperties", err)
+	if got, want := code(redo()), codes.FailedPrecondition; got != want {
+		t.Errorf("Redo after a new edit: code = %v, want %v", got, want)
+	}
+	mustOK("Undo", undo())
+	if got, want := foo.Name, "foo"; got != want {
+		t.Errorf("graph name = %q, want %q", got, want)
+	}
+
+	// Edits that change nothing aren't recorded.
+	_, err = c.SetPosition(ctx, &pb.SetPositionRequest{Graph: "foo", Node: "baz", X: 10, Y: 20})
+	mustOK("SetPosition", err)
+	mustOK("Undo", undo())
+	if baz := foo.Nodes["baz"]; baz.X != 0 || baz.Y != 0 {
+		t.Errorf("baz position = (%v, %v), want (0, 0)", baz.X, baz.Y)
+	}
+	if got, want := code(undo()), codes.FailedPrecondition; got != want {
+		t.Errorf("Undo after undoing everything: code = %v, want %v", got, want)
+	}
+}
@@ -70,6 +70,18 @@ func (c *server) createGraph(key string, graph *model.Graph) (*serveGraph, error
 type serveGraph struct {
 	*model.Graph
 	sync.Mutex
+
+	changes   changeLog
+	history   history
+	telemetry telemetryHub
+}
+
+// warnMigrated logs a warning if the graph was upgraded from an older format
+// version when it was loaded.
+func warnMigrated(g *model.Graph) {
+	if from, ok := g.Migrated(); ok {
+		log.Printf("Warning: upgraded %s from format version %d to %d; it will be saved in the new format", g.FilePath, from, model.FormatVersion)
+	}
 }
 
 func (sg *serveGraph) reload() error {
@@ -82,7 +94,16 @@ func (sg *serveGraph) reload() error {
 	if err != nil {
 		return status.Errorf(codes.FailedPrecondition, "load from JSON: %v", err)
 	}
+	warnMigrated(g)
+	if err := g.Link(); err != nil {
+		log.Printf("Couldn't link subgraphs of %s: %v", g.FilePath, err)
+	}
+	before := sg.snapshot()
 	sg.Graph = g
+	sg.history = history{}
+	if e := diffStates(before, sg.snapshot()); e != nil {
+		sg.publish(e)
+	}
 	return nil
 }
 
@@ -132,6 +153,11 @@ func (c *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			http.ServeContent(w, r, f.Name(), fi.ModTime(), f)
 			return
 		}
+		warnMigrated(g)
+		if err := g.Link(); err != nil {
+			// Not fatal; the graph can still be edited.
+			log.Printf("Couldn't link subgraphs of %s: %v", base, err)
+		}
 		sg, err := c.createGraph(r.URL.Path, g)
 		if err != nil {
 			log.Printf("Graph already created in server: %v", err)
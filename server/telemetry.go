@@ -0,0 +1,111 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"sync"
+
+	pb "github.com/google/shenzhen-go/proto/go"
+	"github.com/google/shenzhen-go/telemetry"
+)
+
+// telemetryHub passes telemetry from running programs to watchers.
+type telemetryHub struct {
+	mu       sync.Mutex
+	watchers map[chan *pb.Telemetry]struct{}
+}
+
+// watch returns a channel that receives telemetry, and a function to call
+// when no longer watching. Watchers that fall behind miss samples.
+func (h *telemetryHub) watch() (<-chan *pb.Telemetry, func()) {
+	ch := make(chan *pb.Telemetry, 1)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.watchers == nil {
+		h.watchers = make(map[chan *pb.Telemetry]struct{})
+	}
+	h.watchers[ch] = struct{}{}
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.watchers, ch)
+		h.mu.Unlock()
+	}
+}
+
+// publish sends a sample to all the watchers.
+func (h *telemetryHub) publish(t *pb.Telemetry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.watchers {
+		select {
+		case ch <- t:
+		default:
+		}
+	}
+}
+
+// serve accepts connections from running programs on l, and publishes
+// the samples they send until l is closed.
+func (h *telemetryHub) serve(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			dec := json.NewDecoder(conn)
+			for {
+				var s telemetry.Sample
+				if err := dec.Decode(&s); err != nil {
+					log.Printf("Telemetry connection ended: %v", err)
+					return
+				}
+				h.publish(telemetryProto(&s))
+			}
+		}()
+	}
+}
+
+// telemetryProto converts a telemetry sample into the proto equivalent.
+func telemetryProto(s *telemetry.Sample) *pb.Telemetry {
+	t := &pb.Telemetry{
+		Channels: make([]*pb.ChannelTelemetry, 0, len(s.Channels)),
+		Nodes:    make([]*pb.NodeTelemetry, 0, len(s.Nodes)),
+	}
+	for _, c := range s.Channels {
+		t.Channels = append(t.Channels, &pb.ChannelTelemetry{
+			Name:     c.Name,
+			Sent:     c.Sent,
+			Received: c.Received,
+			Len:      uint64(c.Len),
+			Cap:      uint64(c.Cap),
+			Blocked:  c.Blocked,
+			Closed:   c.Closed,
+		})
+	}
+	for _, n := range s.Nodes {
+		t.Nodes = append(t.Nodes, &pb.NodeTelemetry{
+			Name:     n.Name,
+			Running:  n.Running,
+			Finished: n.Finished,
+			Err:      n.Err,
+		})
+	}
+	return t
+}
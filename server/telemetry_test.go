@@ -0,0 +1,66 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/google/shenzhen-go/proto/go"
+	"github.com/google/shenzhen-go/telemetry"
+)
+
+func TestTelemetryHub(t *testing.T) {
+	var h telemetryHub
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() = %v", err)
+	}
+	defer l.Close()
+	go h.serve(l)
+
+	ch, stop := h.watch()
+	defer stop()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() = %v", err)
+	}
+	defer conn.Close()
+	sample := &telemetry.Sample{
+		Channels: []telemetry.Channel{{Name: "c", Sent: 3, Received: 1, Len: 2, Cap: 2, Blocked: true}},
+		Nodes:    []telemetry.Node{{Name: "n", Running: true}},
+	}
+	if err := json.NewEncoder(conn).Encode(sample); err != nil {
+		t.Fatalf("Encode(sample) = %v", err)
+	}
+
+	want := &pb.Telemetry{
+		Channels: []*pb.ChannelTelemetry{{Name: "c", Sent: 3, Received: 1, Len: 2, Cap: 2, Blocked: true}},
+		Nodes:    []*pb.NodeTelemetry{{Name: "n", Running: true}},
+	}
+	select {
+	case got := <-ch:
+		if !proto.Equal(got, want) {
+			t.Errorf("watched telemetry = %v, want %v", got, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for telemetry")
+	}
+}
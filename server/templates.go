@@ -18,10 +18,38 @@ import "text/template"
 
 const goRunnerTemplateSrc = `package main
 
-	import "{{.PackagePath}}"
+	import (
+		{{if .UseContext -}}
+		"context"
+		"fmt"
+		"os"
+		"os/signal"
+		"syscall"
+
+		{{end -}}
+		{{.PackageName}} "{{.ImportPath}}"
+	)
 
 	func main() {
-		{{.PackageName}}.Run()
+		{{if .UseContext -}}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sig
+			signal.Stop(sig)
+			cancel()
+		}()
+
+		if err := {{.PackageName}}.Run(ctx{{if .HasParameters}}, {{.PackageName}}.DefaultConfig(){{end}}); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		{{- else -}}
+		{{.PackageName}}.Run({{if .HasParameters}}{{.PackageName}}.DefaultConfig(){{end}})
+		{{- end}}
 	}
 `
 
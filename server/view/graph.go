@@ -29,6 +29,7 @@ type editorInput struct {
 	Params              *Params
 	Graph               *model.Graph
 	GraphJSON           string
+	Version             uint64
 	PartTypes           map[string]*model.PartType
 	PartTypesByCategory map[string]map[string]*model.PartType
 	Licenses            []license
@@ -39,8 +40,8 @@ type license struct {
 	URL       template.URL
 }
 
-// Graph displays a graph.
-func Graph(w http.ResponseWriter, g *model.Graph, params *Params) {
+// Graph displays a graph, at the given version.
+func Graph(w http.ResponseWriter, g *model.Graph, version uint64, params *Params) {
 	gj, err := json.Marshal(g)
 	if err != nil {
 		log.Printf("Could not execute graph editor template: %v", err)
@@ -52,6 +53,7 @@ func Graph(w http.ResponseWriter, g *model.Graph, params *Params) {
 		Params:              params,
 		Graph:               g,
 		GraphJSON:           string(gj),
+		Version:             version,
 		PartTypes:           model.PartTypes,
 		PartTypesByCategory: model.PartTypesByCategory,
 	}
@@ -3,8 +3,8 @@
 package view
 
 var cssResources = map[string][]byte{
-	"css/fonts.css": []byte("\x1f\x8b\b\b\x17\xa4\a\\\x02\xfffonts.css\x00̓\xb1J\xc50\x14\x86盧Ȗ{\x87{\xdb\xc5%]ĥ8t\xf1\rb\x9a\xd4\xc0i\x8e$'H\x11\xdf]Z\xdbE\x04[R\x8bc\xc2\xe1\xf0\xf1\u007f\xff\xb9\xb7\xe8\xe9j\x956\xfc\x9d\x9d\xe6G\xef`\x90\\\xd4(*v\x8aAK\x9e\x02\x9cEq\x8b\xa4\xc8\xe9b\x1c\x8bE\x8d\x8di]ꯏ\xa4\xc0\xe9\x1b\x91\x15\x17n1\xf4\x8a\u0382B24\xbc\x1aq\xa9\xe6\xbdo\xc6u/$\xf9]Y._\x91\x060\x92\xbbiA\xc5>\x18\xcb\xe2\xd9J\xe2\xc7\t\xf8+\x98\a\x84v5\xca3B\xfb\rd\xa1\xcb\x05\xf9\xb2\x94\xa1g/\x90)\x91\x8d\x8a~\xc8e/AO\xa6K\xa0B^[Vg\xc3\x1b\xf4\xbf\x9aB\u007f`m\xd6\x12\xfd\xb7\x80\x0e=\xf2M\xdav\xec\xf6g\x00\x00\x00\xff\xffP\xa3B\xc1\x98\x05\x00\x00"),
-	"css/main.css": []byte("\x1f\x8b\b\b\x17\xa4\a\\\x02\xffmain.css\x00\xacWݎ\xe3(\x13\xbd\x1e?\x05\xd2蓾\x95\xdaQ\xd2\xea\x1d\xb5<\xda'Y\xed\x056\x15\xbb\xd4\x18\x10\xe0ęU\xbf\xfb\n\f\xfe\x89\xed\xc4\xe9\xc9Uw\x8a\xe2Pu\xaa\xea\x80s\xc9.\xe4߄\x10B\x8eR\xd8\xf4Hk䗌\x9c\xa8\xfe\u007f\x9a\x8eL\u007f\xfc\xf4N\x85\xe4R\xc7e\v\xadM\x9d\xa5\xd1a9\xa7\xc5G\xa9e#X:\xf1\xbc\xb2\xf7\xfeG.\xa9͈\x90\x02:CMu\x89\"#\xfb\xee'C\xa38\xbdd\xe4ȡ\x8d[\xa0M\x8f\\\x9e3\x17LS\x8b\xce\\\x01\x96\x95\xcd\xc8a\xbf\xff_\x84jө\xf53I\x8c\xa2b\xc7Q|\x84\xa4'Q:\xfb4>\x9f!\x83BjjQ\x8aq\xa4E\xa3\x8d۪$\n\vz\x8a\xbe3\xc0\xa1\xb0\xc0\xc6ܞC0\xb9\xe4l\xea\x9eU\xf2\x04z5$\xbfz'\xb0F0\xd0\x1c]t\x93@\x18\x18\xab\x9b\xc2\xe2\tV\xf1G>\xc3)k(wb\x1dc]\xc5\xfd\x99$4sN/\t\xcdNhp\xe0\xe7ke\xf0\x80Ϧ\x8eN\x92\r\xe1Nl\xf7B_\xa3\x93>\x8d\xc6B2\xd84\xb5i-\x85\xbc3\xba\xa9C\xebpQ\xa8ƾ$]\xf3\xbe$Ήj\xa0\x0f\x1f\xe5\xcd\x06\u007f\xc1\xc4\xcf\x19\xbe\xa0\x13\xdfnhN\xf2-\x97\x9a\x05vz\x1f\x86'dcʢ\x97\xb1\x17\x0e\x191\x92#\x1b\xac\x9a2lLF\xdeT;\x18\xcf\xc8l\x95\x91\x83\xb7)\xca\x18\x8a2#\xef\ue9db\f\xcf\xd0V\xe1\xfcL\x12\x86\xa7\xddQ\xea\x9ax\x8a\xff\xb6\x17\x05\u007f\xb9T\xfey\x19\x96\"\xed\xbd\xe1\x8a\xff\x18\xd2H\xe1:\xb1|W\xedL0Q\xb8\x8eNs.\x8b\x8f\xc0\xb9l]\t|\x1e!\xc9\\\xb6\x93\xe8\x8e\b\x9c\xdd>-\xb5Re\xe4𪆝\xb9\x96g\xe3Z]X\x8a\xa2o\xea^\xca\tm\xac\xec\xa8\xf3v\x14\x91\xdd\xf7\xfd~\x84S\x01\x8d\x87\xf7\x84\xffP#\xd9wX\a\x8f\x163\nYX+\xeb\xab\xe2\xce\xd7o\xb7\xc8\xdc\u007f\xdc\x011Sن\x00o_LZ\x9e\x97\xa3\x0e8\xd7Tun\ar \u007f\x86{\x8a\xe1\xe9;CZjZψurНc\n-9\xef7(*\x80\x9b\x99\xff\xb6K4\x84\xe61\x1e\xda8$0Tf-\xc4\x0e~\xe7\xca\v˵\x0e~B2H\u007f?\x96\xd8Y\x05\b\xab\xa3dz\t\xa1\x1cK\x91\x11\xb7\x10.oKs\x0e\xa1\x99\x9f\xafx\x93\xe9\xf9\xa1\xac?\xd2ϛ\x01{51o\x81\x88\xb8\xfc]Q[Y\xa8\x15\xa7\x16\xae\xf9\xe8\xaf\xc2\x1d\xd3R1y\x16\x91Wi\xb0\xbb\xe04p\xean\x92\xbb\x12\x11\x82\xe4p\xb4\x19y\x85v\x8a\xeb\x1b\v\x84]\x8e`z$͍䍅\xdbr\xdf#\xaf\xbd\x0f\xbdfU\x94\xb9\x1a\xefU\xebZ\xc4k\x8f\xff1`\x04\x9f\xe9\u07be\xaf\xdeT\xdbQꬿR\x14\xccO\xdb4\xb9p\x1f\xdfM6\xb0\xb5\xc8K\x13\x9b\x95\xa3\xb1\x9d\x1e\xa5N\xe9o<p\xfb \xf7׃S!c \xd6O\xe2\x18\x85\xbaB\v\xa9Q\xb4\xf0\a\x9d5U?\x17۩S\x1e\x06\xc0\xd0>\xbf\xc1\x17\xc6\u0382\xaeQP~\xa7!g7\xcd\xfc\xd5~*\xa3\x1a\x06\xac\xa1a\x06E\xef\xd4r\xa9\x95\xae vLӲt\xe3\x1e\xdf_\xe1-_j\x9a\xdf\xf0\xee\xfeCQ.l\xcbQ\x94\xb3\xad\xe5\xcei\x8d\xbb5\xdc\xdf\xc89r\xbe\xfe\x05\xe5hue\xd45\xe53\xa6W?\xcb\x1a\xe3^7\xfe\xfd0\x9dG\xffm\x92\xc2\t\x845\xe3\x15/\u007f5\b\x9b\xe6ԀӁ\x8c\xd4\xc8\x18\x8f\"!]\xe5V\x97;\t\x15E\xe5ҏ\v\xab\xc9\xeb\xd1[i\x94|\xac\x18\x83#m\xb8u\xaf\x91ԭ\x87\x9c\x8c\xd5\xf2\x03n9w\x1e\x13\xf7\xfe־S\x8b,\xeb\xd8B)\x16:\xaa\xd7\xd4)\x80\xbb\x94\xb6\xa7寰\xbb\xf9x\xaf\a\x13q{\x86/\xcc\xc7\x02\x8a\xdb\xe6\x91\xc5\x13_\xd73W(H\x81\xba\xe8\ag阢\xa2B\x00_\x9d\xbe1ڐ\xc5f\xd8>\x81M\xf8\xa0\xb5\xd4\xdb\xc1\xbd\xfb\r\xe4\xe0G\xdcD\x04\xb8\xe5\xb2\xceH\xd8\xc6s\xc4w\x17\xfeoQ\x1c\x81\x9eP\xad\xe01\x94js\xf2\x1bJ5\x03ߘ\xf9W\xa0\x9f\xd9b\x11\xbc\xeb\xaf͌l\xec\xaf\x00\xbb\x91\x8b\xc7@\xbf<\v\xff\x05\x00\x00\xff\xff\xf2\xbb\x14E+\x14\x00\x00"),
-	"css/theme-darkhc.css": []byte("\x1f\x8b\b\b\x17\xa4\a\\\x02\xfftheme-darkhc.css\x00\x84\x92ю\xdb,\x10\x85\xef\xf3\x14H\xb9\xf0F\x82_6\xfe\x93:\xf6UUi\xb77ۛ>\xc1\x18\x86\x04\x850\x11\xe0\xec\xb6U\u07fd\xb2\x1b\xdbI7\xab\x15\xd2H\xe6\x9c\xef\x8c\a\xa8\x03Qb\xbf\x16\x8c\tт:\xec\x02u^\vE\x8e\xbaP\xb3e\x9e\xe7\xcdbPu\xa0\x93\xa6\x17\u007f߶\xedW3\x19E܃\xa6\x97\xc9\x10v-<\xc8\xf5\x9a\xb3\xb9\xe4\xff\x95\xab\va\xcfVc\x98\xe3\x00\xa0W\x06\xd1Y\u007f\x98\x95\x1617\xa6\x99\x95=\x9d\xafȰk\x1f\x8a\xb2䬨\xb6}\x9b\xb1\xc3`\xd5\x18S\xe8T\xb2g\xbc\x01\x86\xbf)\xfe\xef\xa9\xcd\xf6=\xe0m\x9f\x01\xdb|\xeaQ\xb9\x1a\x0f\xc9\xc2.\xc0\xf1\xee\x19\x15\x9b~57>\xb5\a\xefѽ\xcd\x1d\xcb\xea\xbe?\xa2C\x95P\u007f8\xf8\xbf \x86@\xe1\xc3\xe9GJ\xa3\x81\xce%\xd1ҫ0ֹ\x9a-ˢ\xac\n\xfd\xbe-\xa6@\a\xac\xd9Ҁ1\x88\xb7FO\x1aǠ\x1c\n%\xd5\x1d}J\xc0\xdcLw}\xeb\x18\xa7\xbfDI\xb9\x96\xd5\xf8N\r\xf9$\f\x1c\xad\xfbQ\xb3'\xe2\xd9w\xf0\xec1\x80W6*\xcax\xf6\x15\xdd\x19\x93U\xc0\xbea\x87\x19\x9f\xbe\xf9\xe7`\xc1\xf1\b>\x8a\x88\xc1^Z_\x05\x8a#y\xaaY\xf6D\xec\x99|\x1f\xf6h\x03\xb0/\xa41\xe3\xcf\xe8\x1d\xf1\xde\x11O\xa0\xf0\n\x8e\xf6'֬\x90\xa7\xf4w3\xe1k\x9a߅\x19g\xbc\xda\x16\x8a4^n\xb5\xdap&e5\x94U\xb3\xf8\xfd'\x00\x00\xff\xff\xab\xf9`ǳ\x03\x00\x00"),
-	"css/theme-default.css": []byte("\x1f\x8b\b\b\x17\xa4\a\\\x02\xfftheme-default.css\x00t\x92ώ\xd30\x10\xc6\xef}\nK{\bH62t7[\xd2\x13B\xda\xe5\xb2\\x\x82\xa9=n\xad\xba\x9ej\xecd\x17\x10\xef\x8e\x12\xf2\x8f*QN\x99\xef\xe7\x9f\xe6K\\1Q\x16\xbf7B(u\x00s>2\xd5\xd1*C\x81j\xaeĝsn\xbf\xe9R\xcbt\xb5\xf4\x1aױ\x81R\xe9\x04\x96^ǔ\x8f\ax\xa7e\xf7|ؾ\xefA\xdfx\x8b<)\x00\xa0M\xba0\xf8x\x9e\x12\xfd`\xf7\xd3\xf8D\xcd\xfc\x98~t\xb3\xd0b\xca\\\x9b\xec\x1b\x9c\x10\xab\xb7+ȍ\xcb\xe9r(\xeb\xe1\xc8pY\xee\xbas\xbb\xb1nϙ\x13Ĉa\xb6\x96\xd6\xcbD\u0080&\xe3̧?\xafȐ\x99x\xa1\xc6\xc0YtP\x87\xac\x0e\xf4\xa6\x9c\x0f\xa1\xdd\r\x9cC\\\xc7Rf:c%\xee\xcam\x89\xf7\xbb\xff\xc1H\x16\a\x11j\xa7oKv\xf9h\xb8\u007f(\xf5#,\x11C\xc3^u@\xd4\xe3-r\x14\xb3rp\xf1\xe1g%\x9eI\x16? \x8a'\x86h|2T\xc8\xe2\x1b\x86\x06\xb37 \xbec\x8d\x85\x1c\xdf\xe5\x17\xf6\x10d\x82\x98TB\xf6\xfdr3\xa1\xbaP\xa4J\x14\xcf$^(\xb6\xb2'\xcf \xbe\x92\xc5B\xbe`\f$[\"]\xc1\xe0\xecp\xf2\xbf\xb0\x12\x1f?]\xf3\xbfaƷ\xbc\xf0#gceȶ\x9f@\x97\xe5~\xf3\xe7o\x00\x00\x00\xff\xffW\xb2*BC\x03\x00\x00"),
+	"css/fonts.css": []byte("\x1f\x8b\b\b\x80R@\\\x02\xfffonts.css\x00̓\xb1J\xc50\x14\x86盧Ȗ{\x87{\xdb\xc5%]ĥ8t\xf1\rb\x9a\xd4\xc0i\x8e$'H\x11\xdf]Z\xdbE\x04[R\x8bc\xc2\xe1\xf0\xf1\x7f\xff\xb9\xb7\xe8\xe9j\x956\xfc\x9d\x9d\xe6G\xef`\x90\\\xd4(*v\x8aAK\x9e\x02\x9cEq\x8b\xa4\xc8\xe9b\x1c\x8bE\x8d\x8di]ꯏ\xa4\xc0\xe9\x1b\x91\x15\x17n1\xf4\x8a\u0382B24\xbc\x1aq\xa9\xe6\xbdo\xc6u/$\xf9]Y._\x91\x060\x92\xbbiA\xc5>\x18\xcb\xe2\xd9J\xe2\xc7\t\xf8+\x98\a\x84v5\xca3B\xfb\rd\xa1\xcb\x05\xf9\xb2\x94\xa1g/\x90)\x91\x8d\x8a~\xc8e/AO\xa6K\xa0B^[Vg\xc3\x1b\xf4\xbf\x9aB\x7f`m\xd6\x12\xfd\xb7\x80\x0e=\xf2M\xdav\xec\xf6\xe7\x00P\xa3B\xc1\x98\x05\x00\x00"),
+	"css/main.css": []byte("\x1f\x8b\b\b%\x94\xd4j\x02\xffmain.css\x00\xacWێ\xe3(\x10}\x9e|\x05\xd2h\xa5]\xa9\x1d%\xbd\xbd\xa3Y\x8f\xf6KV\xfb\x80M\xc5.5\x06\x048qf\xd4\xff\xbe\x02\x83/\xf1%\xceL\x9e\xbaS\x14\xa7\xaaN\x15\a\x9cIv%?v\x84\x10r\x92\xc2&'Z!\xbf\xa6\xe4L\xf5\xefI20\xfd\xf1\xcd;\xe5\x92K\x1d\x97-46q\x96Z\x87\xe5\x8c\xe6\uf156\xb5`\xc9\xc8\xf3\xc6\xde\xf9\x9f\xb8\xa46%B\nh\r\x15\xd5\x05\x8a\x94\x1cڟ\f\x8d\xe2\xf4\x9a\x92\x13\x87&n\x81&9qyI]2u%Zs\tX\x946%\xc7\xc3\xe1\xb7\b\xd5$c\xeb\xc7ng\x14\x15{\x8e\xe2=\x14=\xca\xd2\xd9\xc7\xf9\xf9\n\x19\xe4RS\x8bR\f3\xcdkm\xdcV%QX\xd0c\xf4\xbd\x01\x0e\xb9\x056\xe4\xf6\x12\x92\xc9$gc\xf7\xb4\x94gЋ)\xf9\xd5;\x89Ղ\x81\xe6(`\x8c\xbcg`\xac\xaes\x8bgX\xc4\x1f\xf8\xf4Q\x96P\xee\xe4:ĺ\xc9\xfbc\xb7\xa3\xa9sz\xd9\xd1\xf4\x8c\x06{~~\xae\r\x1e\xf0\xd9\xd4\xd1Q\xb1!ݑ\xed^\xeaKtҧјK\x06\x9bNmRI!\xef\x1c\xddġ\xb5\xb8(Tm_v\xed\xf0\xbe\xec\x9c\x13\xd5@\x1f\x0e\xe5\xcd\x06\xbf\xc3\xc8\xcf\x19~B'>\xadh\xce\xeeS&5\v\xect>\f\xcfȆ\x94E/c\xaf\x1cRb$G\xd6[5eX\x9b\x94\xbc\xa9\xa67^\x90\xd92%GoS\x941\x14EJ\xbe\xaa\xa6=\x19\x9e\xa1\xad\xc2\xf9\xb1\xdb1<\xefORW\xc4S\xfc\xaf\xbd*\xf8Ǖ\xf2\xdfK\xbf\x14i\xef\f7\xfcǔ\x06\n\u05ca\xe5W\xd5L\x04\x13\x85\x9b\xe8$\xe32\x7f\x0f\x9c\xcbƵ\xc0\xd7\x11\x8a\xccd3\xca\xee\x84\xc0\xd9z\xb4\xc4J\x95\x92\xe3\xab\xeawfZ^\x8c\x1bua)\x8an\xa8;)'\xb4\xb6\xb2\xa5\xce\xdbQDv\xbf\x1e\x0e\x03\x9c\x12h\f\xde\x11\xfeE\rd\xdfa\x1d=Z\xac(Ta\xad\xacn\x9a;]_\x1f\x91\xa9\xffp\x02b\xa5\xb2!?\xc6<\xcf^LZ^\xe6\xb3\x0e8\xb7T\xb5nGr$\x7f\x85{\x8a\xe1\xf93CZhZM\x88ur\xd0\xc61\xb9\x96\x9cw\x1b\x14\x15\xc0\xcd\xc4\x7f\xdb%\x1aR\xf3\x18\x0fm\xec\v\xe8;\xb3\x94b\v\xbfw\xed\x85\xf9^\a?!\x19$\xbf\x9eK\x9c\xac\x1c\x84\xd5Q2\xbd\x84P\x8e\x85HI\x0e\xdd\xe5mi\xc6!\f\xf3\xf3\x15otz\xbe(\xebC\xfa\xf3f\xc0ޜ\x987Ռ\x96?+jK\v\x95\xe2\xd4\xc2-\x1f\xddU\xb8gZ*&/\"\xf2*\r\xb6\x17\x9c\x06N\xddMrW\"B\x92\x1cN6%\xafЌq\xfd`\x81\xb0\xf3\x19\x8cC\xd2\xccH^[X\x97\xfb\x0ey\xe9}\xe85\xab\xa4\xcc\xf5\xf8\xa0\x1a7\"^{\xfc\x8f\x1e#\xf8\x8c\xf7vs\xf5\xa6\x9a\x96Rg\xfd\x9e\xa0`\xfe\xb4\x8d\x8b\v\xf7\xf1\xddb\x03[\xb3\xbc\xd4qX9\x1a\xdb\xeaQ\xe2\x94~\xe5\x81\xdb%y\xb8=8%2\x06b9\x12\xc7(\xd4%ZH\x8c\xa2\xb9\x0ft\xd1T}\x9b\x1d\xa7Vy\x18\x00C\xfb\xfc\x01\x9f9v\x16t\x85\x82\xf2;\x039\xb9i\xa6\xaf\xf6s\x11\xd50`\xf5\x03\xd3+\xba_\x9f\x1d\xa5\x1b\x88=Ӵ(\xdcq'?Fo\xf9B\xd3lŻ\xfd\x0fE1\xb3-CQL\xb6\x16{\xa75\xee\xd6p\x7f#\xe7\xc8\xf9\xf2\x17\x94\xa3յQW\x94O\x98^\xfc,\xab\x8d{\xdd\xf8\xf7\xc3\xf8<\xfao\x93\x04\xce \xac\x19\xaex\xf9\xab@\xd8$\xa3\x06\x9c\x0e\xa4\xa4B\xc6xXg\xd2unq\xb9\x95P\x91\x97R\xf7\v\x8b\xc5k\xc8犏\x1dcp\xa25\xb7\xee5\x92\xb8\xf5P\x93\xb1Z\xbeÚs\xeb1r\xefn\xed;\xbdHӖ-\x94bf\xa2:M\x1d\x03\xb8Ki{Y\xce\xfb~=\xde\xeb\xc1Bܞ\xfe\v\xf3\xb1\x84\xe2\xb6if1\xe2\xebRD]\v\xe1\x86\x7f6\xe0Jua\xdfZ\x95\x7f.\xc5<\xa1@S.U\xb9\x124n\x9c\x8dʨ)\xa9\xd6N\xcf\xdf\x16#S\xe4\x8f\xc5\xcdK*\x04\xf0\x04\xb4\x967/\xcbmՒb\xafP\x90\x1cu\xdeI\xd3\\#c\xa0%}\x1b\xa2\xf5s\xb2\x196\xee؆\xef\xab\xdd\x0e~C\xce\x049\xf8\x11\xa79\x9b\xd8^\xe1\xf9u\x05\xdf=\xa9~\x89\xe2\b\xf4\x84n\x05\x8f\xbeU\x9b\x8b\xdfЪ\t\xf8\xc6\xca\x7f\x06\xfa\x99#\x16\xc1\xdb\xf9\xda\xcc\xc8\xc6\xf9\n\xb0\x1b\xb9x\f\xf4\xd9ga\xefߜ\x8f\x8cE\xd8\xf0\x00\xf4F\"6\x03\xfbKvo\x81C\x05V_\x7f\xe5\xb9\xf9\xb7\xb2\xc1\xb6\xfap\x9a\x7f\xeb|\xfc?\x00\xf4/QR\xe1\x16\x00\x00"),
+	"css/theme-darkhc.css": []byte("\x1f\x8b\b\b%\x94\xd4j\x02\xfftheme-darkhc.css\x00\x84\x93\xdfn\xdb \x14\xc6\xef\xf3\x14H\xb9p-\xe1\tp\x93%\xf6\xd54\xa9\xddMw\xb3'\xc0pHP\b'\x02\x9cv\x9b\xf6\xee\x93\xdd\xd8ο\xaaBB\n\xdf\xf7\xfb\x0e\x9c\x1cW\x011\x91\xbf3B\x8a\xa2\x91j\xb7\t\xd8z](t؆\x8a\xcc\x19c\xf5\xacWu\xc0\x83\xc6W\x7f߶\xeeV=\x1a\x8b\xb8\x95\x1a_GC\xd84\xf2A,\x16\x94L\x1b\xfbR\xe6'\xc2\x1e\xad\x860\xc5I);\xa5\x17\x9d\xf5\xbbIi\x00\x981\xf5\xa4l\xf1xF\x86M\xf3\xc0˒\x12\xbeZwe\xca\xfc̪!\xa6Ъd\x8fp\x01\xf4\xb7\xe1\x8f\x1d\xb5\\\x7f\x04\xdc\xd6\xe9\xb1\xe5\xd7\x0e\x15\xf9\xd0$+7A\xee\xef\xf6\x88/\xbbU_\xf8\xd4Vz\x0f\xee6w\xd8\xf2\xfb\xfe\b\x0eT\x02\xfd\xe9ïA\b\x01ç\xaf\xbf\xa6\x1a\x87j\a\xfa\x96\x13\x8cQ\xc2\x19\xbb\xe24\x18ٺT4\xf8V\x18\xeb\\E\xe6%/W\\\x7fl\x8b)\xe0\x0e*27\xd2\x18\x80K\xa3G\rC\x10\x93\\\tuG\x1f\x13\x80\x99qF.\x1c\xa1\xf5\xde\xfa\xcd\xe8\xec\x9b&ة\xdb\xfc\x91\xe5w c\xbd\x8d[\xd0S\xfei:\xaf\xcb\x0f\x7f\xc9\xe9\x9eB,\xc4j\xf8x\f\xfaT\x18\xb9\xb7\xeewE\x9e\x91f\xbf\xa4'OAze\xa3\u008cf?\xc0\x1d!Y%\xc9Oh!\xa3\xe3o\xfa-X\xe9h\x94>\x16\x11\x825\xf5u`\xb1G\x8f\x15ɞ\x91\xbc\xa0\xef\u009el\x90\xe4;j\xc8\xe8\vx\x87\xb4săTp\x06G\xfb\a*\xc2\xc5!\xbd\x1f&xKӰ\x1acn\x8e\v\x85z\xe8\xdajI\x89\x10\xab~\xcb\xebٿ\xff\x03\x00\x1eə!H\x04\x00\x00"),
+	"css/theme-default.css": []byte("\x1f\x8b\b\b%\x94\xd4j\x02\xfftheme-default.css\x00t\x92\xc1\x8e\xd30\x10\x86\xef}\nK{\bH12t7\x1b\xd2\x13B\xda\xe5\xb2\\x\x82\x89=n\xad\xba3\xd5\xd8\xe9. \xde\x1d\xb5\xb4I\xa8\\\xe5\x14\xff\x9f?\xcfo\xb9\x13\xe6\xac~/\x94Һ\a\xbb]\v\x0f\xe4\xb4\xe5ȃt\xea\xce{\xbfZ\x9cR'\xbcw\xfcJ\xb7\xb1\v\xa5\xd3\x06\x1c\xbf\x8e\xa9\xac{xg\xea\xd3\xf7a\xf9\xfe\f\x86Cp(\x93\x02\x00\x8e\xc9)\x8c\x81\xb6Sb\x1e\xdcjZ\xde\xf0a\xbe\xcd<\xfaY\xe80e\x19l\x0e\a\x9c\x10g\x967\x90+\x977ͥl\x80\xb5\xc0\xaeܵ\xf5\xedX\xf7\xcc\xd9\r\x10a\x9c\x8deL\x99H\x18\xd1f\x9c\xf9\xcc\xe7\x1b2\x14a)Ը\xe6\xfa\xc8v;7b{u\xb8C\x0fC̺\xe77\xedC\x8c\xc7\x16\xe0=\xe2m,e\xe1-v\xea\xaeY6x\xdf\xfe\x0f\x12;\xbc\x88\xd0x\xe3}!\x1f\r\xf7\x0f\x8dy\x84\x02!\x03Q\xa0\xf5D\x1a0Ń(\xa4\r\xba\x89k\xdb\xd2@\xe3՞'\xeb\x11\xcd\xf8|=S\xd6\x1ev!\xfe\xec\xd43\xd7\xd5\x0f \xf5$@6$\xcbU]}\xc3x\xc0\x1c,\xa8\xef8`U\x8f\xff\xf5\x17\t\x10\xeb\x04\x94tB\t~u-\xd4;&\xeeT\xf5\xcc\xea\x85\xe9({\n\x02\xea+;\xac\xea\x17\xa4\xc8\xf5\x91H{\xb08ۜ\xc2/\xec\xd4\xc7O\xfb\xfco1\xe3[.\xbc\xa0ٲ\xb6\xecN\xf7\xd44\xabş\xbf\x03\x00\x0eJ:м\x03\x00\x00"),
 }
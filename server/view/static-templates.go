@@ -4,5 +4,5 @@ package view
 
 var templateResources = map[string][]byte{
 	"templates/browse.html": []byte("<head>\n\t<title>SHENZHEN GO</title>\n\t<link type=\"text/css\" rel=\"stylesheet\" href=\"/.static/css/fonts.css\">\n\t<link type=\"text/css\" rel=\"stylesheet\" href=\"/.static/css/theme-{{.Params.CSSTheme}}.css\">\n\t<link type=\"text/css\" rel=\"stylesheet\" href=\"/.static/css/main.css\">\n</head>\n<body>\n\t<div class=\"browse-container\">\n\t\t<h1>SHENZHEN GO</h1>\n\t\t<h2>{{$.Base}}</h2>\n\t\t<a href=\"/{{.Up}}\">Up</a>\n\t\t<div class=\"dropdown\"> \n\t\t\t<span class=\"link\">New</span>\n\t\t\t<form method=\"GET\" class=\"dropdown-content\">\n\t\t\t\t<input type=\"text\" name=\"new\" required style=\"width:200px\">\n\t\t\t\t<span class=\"link\" onclick=\"this.parentElement.submit();\">Create</span>\n\t\t\t</form>\n\t\t</div>\n\t\t<table class=\"browse\">\n\t\t\t{{range $.Entries -}}\n\t\t\t<tr>\n\t\t\t\t<td>{{if .IsDir}}&lt;dir&gt;{{end}}</td>\n\t\t\t\t<td><a href=\"{{.Path}}\">{{.Name}}</a></td>\n\t\t\t</tr>\n\t\t\t{{- end}}\n\t\t</table>\n\t</div>\n</body>"),
-	"templates/graph.html": []byte("<html>\n<head>\n\t<meta charset=\"utf-8\"/>\n\t<title>{{$.Graph.Name}}</title>\n\t<link type=\"text/css\" rel=\"stylesheet\" href=\"/.static/css/fonts.css\">\n\t<link type=\"text/css\" rel=\"stylesheet\" href=\"/.static/css/theme-{{$.Params.CSSTheme}}.css\">\n\t<link type=\"text/css\" rel=\"stylesheet\" href=\"/.static/css/main.css\">\n\t<script src=\"/.static/js/ace/ace.js\" charset=\"utf-8\"></script>\n\t<script src=\"/.static/js/hterm/hterm_all.js\" charset=\"utf-8\"></script>\n\t<script>\n\t\tvar aceTheme = '{{$.Params.AceTheme}}';\n\t\tvar graphPath = '{{$.Graph.URLPath}}';\n\t\tvar graphJSON = \"{{$.GraphJSON}}\";\n        hterm.defaultStorage = new lib.Storage.Memory();\n\t</script>\n</head>\n<body>\n\t<div class=\"head\">\n\t\t<a href=\"?up\" title=\"Go up to the files in the current directory\">Up</a>\n\t\t<div class=\"dropdown\">\n\t\t\tGraph\n\t\t\t<div class=\"dropdown-content\"><ul>\n\t\t\t\t<li><span id=\"graph-save\" class=\"link\" title=\"Save current changes to disk\">Save</span></li>\n\t\t\t\t<li><span id=\"graph-revert\" class=\"link destructive\" title=\"Revert to last saved file\">Revert</span></li>\n\t\t\t\t<li><hr/></li>\n\t\t\t\t<li><span id=\"graph-generate\" class=\"link\" title=\"Export the graph to a Go package\">Generate</span></li>\n\t\t\t\t<li><span id=\"graph-build\" class=\"link\" title=\"Export the graph to a Go package and 'go build' it\">Build</span></li>\n\t\t\t\t<li><span id=\"graph-install\" class=\"link\" title=\"Export the graph to a Go package and 'go install' it\">Install</span></li>\n\t\t\t\t<li><hr/></li>\n\t\t\t\t<li><span id=\"graph-run\" class=\"link\" title=\"Export the graph to a Go package and 'go run' it\">Run</span></li>\n\t\t\t</ul></div>\n\t\t</div>\n\t\t<div class=\"dropdown\">\n\t\t\tCreate\n\t\t\t<div class=\"dropdown-content\"><ul>\n\t\t\t{{range $cat, $types := $.PartTypesByCategory -}}\n\t\t\t\t<li>{{$cat}}<ul>\n\t\t\t{{range $t, $null := $types -}}\n\t\t\t\t<li><span class=\"link\" id=\"node-new-link:{{$t}}\">{{$t}}</span></li>\n\t\t\t{{- end}}\n\t\t\t\t</ul></li>\n\t\t\t{{- end}}\n\t\t\t</ul></div>\n\t\t</div>\n\t\t<div class=\"dropdown\">\n\t\t\tPreview \n\t\t\t<div class=\"dropdown-content\"><ul>\n\t\t\t\t<li><span id=\"preview-go-link\" class=\"link\">Preview Go</span></li>\n\t\t\t\t<li><span id=\"preview-raw-go-link\" class=\"link\">Preview Go (no <code>gofmt</code>)</span></li>\n\t\t\t\t<li><span id=\"preview-json-link\" class=\"link\">Preview JSON</span></li>\n\t\t\t</ul></div>\n\t\t</div>\n\t\t<div class=\"dropdown\">\n\t\t\tHelp \n\t\t\t<div class=\"dropdown-content\"><ul>\n\t\t\t\t<li><span id=\"help-licenses-link\" class=\"link\">View Licences</span></li>\n\t\t\t\t<li><span id=\"help-about-link\" class=\"link\">About</span></li>\n\t\t\t</ul></div>\n\t\t</div>\t\n\t</div>\n\t<div class=\"box\">\n\t\t<div class=\"container\" id=\"diagram-container\">\n\t\t\t<!-- TODO: is there a good way of organising the size? -->\n\t\t\t<svg id=\"diagram\" width=\"1600\" height=\"1600\" viewBox=\"0 0 1600 1600\" draggable=\"false\" />\n\t\t</div>\n\t\t<div class=\"container\" id=\"panels-container\">\n\t\t\t<div id=\"graph-properties\" class=\"panel padded\">\n\t\t\t\t<h3>Graph Properties</h3>\n\t\t\t\t<div class=\"form\">\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t    <label for=\"graph-prop-name\">Name</label>\n\t\t\t\t\t\t<input id=\"graph-prop-name\" name=\"graph-prop-name\" type=\"text\" required value=\"{{$.Graph.Name}}\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t    <label for=\"graph-prop-package-path\">Package path</label>\n\t\t\t\t\t\t<input id=\"graph-prop-package-path\" name=\"graph-prop-package-path\" type=\"text\" required value=\"{{$.Graph.PackagePath}}\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<input id=\"graph-prop-is-command\" name=\"graph-prop-is-command\" type=\"checkbox\" {{if $.Graph.IsCommand}}checked{{end}} title=\"Selecting this means the generated package line will be 'package main' instead of 'package [packagename]', which allows your package to run as a standalone command and be installed with 'go install'. De-selecting this causes the package to be usable as a library.\"></input>\n\t\t\t\t\t    <label for=\"graph-prop-is-command\">Is a command?</label>\n\t\t\t\t\t</div>\n\t\t\t\t</div>\n\t\t\t</div>\n\t\t\t<div id=\"hterm-panel\" class=\"panel\" style=\"display:none\">\n\t\t\t\t<div id=\"hterm-terminal\" class=\"terminal\"></div>\n\t\t\t</div>\n\t\t\t<div id=\"preview-go\" class=\"panel\" style=\"display:none\">\n\t\t\t\t<div id=\"preview-go-ace\" class=\"codeedit\"></div>\n\t\t\t</div>\n\t\t\t<div id=\"preview-json\" class=\"panel\" style=\"display:none\">\n\t\t\t\t<div id=\"preview-json-ace\" class=\"codeedit\"></div>\n\t\t\t</div>\n\t\t\t<div id=\"channel-properties\" class=\"panel padded\" style=\"display:none\">\n\t\t\t\t<h3>Channel Properties</h3>\n\t\t\t\t<div id=\"channel-actions\" class=\"head\">\n\t\t\t\t\t<span id=\"channel-delete-link\" class=\"link destructive\" title=\"Delete this channel\">Delete</a>\n\t\t\t\t</div>\n\t\t\t\t<div id=\"channel-properties-panel\" class=\"form\">\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label for=\"channel-name\">Name</label>\n\t\t\t\t\t\t<input id=\"channel-name\" name=\"channel-name\" type=\"text\" required value=\"channel\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label>Type</label>\n\t\t\t\t\t\t<code id=\"channel-type\">type</code>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label for=\"channel-capacity\">Capacity</label>\n\t\t\t\t\t\t<input id=\"channel-capacity\" name=\"channel-capacity\" type=\"number\" required pattern=\"^[0-9]+$\" title=\"Must be a whole number, at least 0.\" value=\"0\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t</div>\n\t\t\t</div>\n\t\t\t<div id=\"node-properties\" class=\"panel padded\" style=\"display:none\">\n\t\t\t\t<h3>Node Properties</h3>\n\t\t\t\t<div id=\"node-actions\" class=\"head\">\n\t\t\t\t\t<!--\n\t\t\t\t\t<span id=\"node-clone-link\" class=\"link\" title=\"Make a copy of this goroutine.\">Clone</span> | \n\t\t\t\t\t<span id=\"node-convert-link\" class=\"link destructive\" title=\"Change this goroutine into a Code goroutine; it cannot be converted back.\">Convert to Code</span> | \n\t\t\t\t    -->\n\t\t\t\t\t<span id=\"node-delete-link\" class=\"link destructive\" title=\"Delete this goroutine\">Delete</span>\n\t\t\t\t</div>\n\t\t\t\t<div id=\"node-panels\" class=\"head\">\n\t\t\t\t\t<span id=\"node-metadata-link\" class=\"link selected\">Properties</span> \n\t\t\t\t\t{{range $tk, $type := $.PartTypes}}\n\t\t\t\t\t<span id=\"node-{{$tk}}-links\" style=\"display:none\">\n\t\t\t\t\t{{range $type.Panels }}\n\t\t\t\t\t| <span id=\"node-{{$tk}}-{{.Name}}-link\" class=\"link\">{{.Name}}</span>\n\t\t\t\t\t{{end}}\n\t\t\t\t\t</span>\n\t\t\t\t\t{{end}}\n\t\t\t\t</div>\n\t\t\t\t<div id=\"node-metadata-panel\" class=\"form\">\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label for=\"node-name\">Name</label>\n\t\t\t\t\t\t<input id=\"node-name\" name=\"node-name\" type=\"text\" required value=\"{.Name}\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label for=\"node-comment\">Comment</label>\n\t\t\t\t\t\t<textarea id=\"node-comment\" name=\"node-comment\" rows=\"4\" cols=\"32\"></textarea>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<input id=\"node-enabled\" name=\"node-enabled\" type=\"checkbox\" checked></input>\n\t\t\t\t\t\t<label for=\"node-enabled\">Enabled</label>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label for=\"node-multiplicity\">Multiplicity</label>\n\t\t\t\t\t\t<input id=\"node-multiplicity\" name=\"node-multiplicity\" type=\"text\" required value=\"1\" title=\"An integer expression. You may use literals and `n`, which equals the result of runtime.NumCPU\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<input id=\"node-wait\" name=\"node-wait\" type=\"checkbox\" checked></input>\n\t\t\t\t\t\t<label for=\"node-wait\">Wait for this to finish</label>\n\t\t\t\t\t</div>\n\t\t\t\t</div>\n\t\t\t\t{{range $tk, $type := $.PartTypes}}\n\t\t\t\t{{range $type.Panels}}\n\t\t\t\t<div class=\"node-panel\" id=\"node-{{$tk}}-{{.Name}}-panel\" style=\"display:none\">\n\t\t\t\t\t{{.Editor}}\n\t\t\t\t</div>\n\t\t\t\t{{end}}\n\t\t\t\t{{end}}\n\t\t\t</div>\n\t\t\t<div id=\"help-licenses-panel\" class=\"panel padded\" style=\"display:none\">\n\t\t\t\t<h3>Licenses</h3>\n\t\t\t\t{{range $.Licenses}}\n\t\t\t\t<h4>{{.Component}}</h4>\n\t\t\t\t<iframe src=\"{{.URL}}\"></iframe>\n\t\t\t\t{{end}}\n\t\t\t</div>\n\t\t\t<div id=\"help-about-panel\" class=\"panel padded\" style=\"display:none\">\n\t\t\t\t<h3>Shenzhen Go</h3>\n\t\t\t\t(working title)\n\t\t\t\t<p>\n\t\t\t\t\tCopyright 2018 Google Inc.\n\t\t\t\t</p>\n\t\t\t\t<p>\n\t\t\t\t\tNote that this is not an official Google product.\n\t\t\t\t</p>\n\t\t\t\t<p>\n\t\t\t\t\t<a href=\"https://github.com/google/shenzhen-go\">Get the source code</a><br/>\n\t\t\t\t\t<a href=\"https://google.github.io/shenzhen-go\">Online documentation</a>\n\t\t\t\t</p>\n\t\t\t\t<!-- TODO: Put build info (git hash, etc) in here via template -->\n\t\t\t</div>\n\t\t</div>\n\t</div>\n\t<script src=\"/.static/js/client.js\"></script>\n</body>\n</html>\n"),
+	"templates/graph.html": []byte("<html>\n<head>\n\t<meta charset=\"utf-8\"/>\n\t<title>{{$.Graph.Name}}</title>\n\t<link type=\"text/css\" rel=\"stylesheet\" href=\"/.static/css/fonts.css\">\n\t<link type=\"text/css\" rel=\"stylesheet\" href=\"/.static/css/theme-{{$.Params.CSSTheme}}.css\">\n\t<link type=\"text/css\" rel=\"stylesheet\" href=\"/.static/css/main.css\">\n\t<script src=\"/.static/js/ace/ace.js\" charset=\"utf-8\"></script>\n\t<script src=\"/.static/js/hterm/hterm_all.js\" charset=\"utf-8\"></script>\n\t<script>\n\t\tvar aceTheme = '{{$.Params.AceTheme}}';\n\t\tvar graphPath = '{{$.Graph.URLPath}}';\n\t\tvar graphJSON = \"{{$.GraphJSON}}\";\n\t\tvar graphVersion = {{$.Version}};\n        hterm.defaultStorage = new lib.Storage.Memory();\n\t</script>\n</head>\n<body>\n\t<div class=\"head\">\n\t\t<a href=\"?up\" title=\"Go up to the files in the current directory\">Up</a>\n\t\t<div class=\"dropdown\">\n\t\t\tGraph\n\t\t\t<div class=\"dropdown-content\"><ul>\n\t\t\t\t<li><span id=\"graph-save\" class=\"link\" title=\"Save current changes to disk\">Save</span></li>\n\t\t\t\t<li><span id=\"graph-revert\" class=\"link destructive\" title=\"Revert to last saved file\">Revert</span></li>\n\t\t\t\t<li><hr/></li>\n\t\t\t\t<li><span id=\"graph-undo\" class=\"link\" title=\"Undo the last change (Ctrl+Z)\">Undo</span></li>\n\t\t\t\t<li><span id=\"graph-redo\" class=\"link\" title=\"Redo the last undone change (Ctrl+Shift+Z)\">Redo</span></li>\n\t\t\t\t<li><hr/></li>\n\t\t\t\t<li><span id=\"graph-check\" class=\"link\" title=\"Check the graph for problems, including likely deadlocks\">Check</span></li>\n\t\t\t\t<li><span id=\"graph-generate\" class=\"link\" title=\"Export the graph to a Go package\">Generate</span></li>\n\t\t\t\t<li><span id=\"graph-build\" class=\"link\" title=\"Export the graph to a Go package and 'go build' it\">Build</span></li>\n\t\t\t\t<li><span id=\"graph-install\" class=\"link\" title=\"Export the graph to a Go package and 'go install' it\">Install</span></li>\n\t\t\t\t<li><hr/></li>\n\t\t\t\t<li><span id=\"graph-run\" class=\"link\" title=\"Export the graph to a Go package and 'go run' it\">Run</span></li>\n\t\t\t</ul></div>\n\t\t</div>\n\t\t<div class=\"dropdown\">\n\t\t\tCreate\n\t\t\t<div class=\"dropdown-content\"><ul>\n\t\t\t{{range $cat, $types := $.PartTypesByCategory -}}\n\t\t\t\t<li>{{$cat}}<ul>\n\t\t\t{{range $t, $null := $types -}}\n\t\t\t\t<li><span class=\"link\" id=\"node-new-link:{{$t}}\">{{$t}}</span></li>\n\t\t\t{{- end}}\n\t\t\t\t</ul></li>\n\t\t\t{{- end}}\n\t\t\t</ul></div>\n\t\t</div>\n\t\t<div class=\"dropdown\">\n\t\t\tPreview \n\t\t\t<div class=\"dropdown-content\"><ul>\n\t\t\t\t<li><span id=\"preview-go-link\" class=\"link\">Preview Go</span></li>\n\t\t\t\t<li><span id=\"preview-raw-go-link\" class=\"link\">Preview Go (no <code>gofmt</code>)</span></li>\n\t\t\t\t<li><span id=\"preview-json-link\" class=\"link\">Preview JSON</span></li>\n\t\t\t</ul></div>\n\t\t</div>\n\t\t<div class=\"dropdown\">\n\t\t\tHelp \n\t\t\t<div class=\"dropdown-content\"><ul>\n\t\t\t\t<li><span id=\"help-licenses-link\" class=\"link\">View Licences</span></li>\n\t\t\t\t<li><span id=\"help-about-link\" class=\"link\">About</span></li>\n\t\t\t</ul></div>\n\t\t</div>\t\n\t</div>\n\t<div class=\"box\">\n\t\t<div class=\"container\" id=\"diagram-container\">\n\t\t\t<!-- TODO: is there a good way of organising the size? -->\n\t\t\t<svg id=\"diagram\" width=\"1600\" height=\"1600\" viewBox=\"0 0 1600 1600\" draggable=\"false\" />\n\t\t</div>\n\t\t<div class=\"container\" id=\"panels-container\">\n\t\t\t<div id=\"graph-properties\" class=\"panel padded\">\n\t\t\t\t<h3>Graph Properties</h3>\n\t\t\t\t<div class=\"form\">\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t    <label for=\"graph-prop-name\">Name</label>\n\t\t\t\t\t\t<input id=\"graph-prop-name\" name=\"graph-prop-name\" type=\"text\" required value=\"{{$.Graph.Name}}\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t    <label for=\"graph-prop-package-path\">Package path</label>\n\t\t\t\t\t\t<input id=\"graph-prop-package-path\" name=\"graph-prop-package-path\" type=\"text\" required value=\"{{$.Graph.PackagePath}}\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t    <label for=\"graph-prop-output-dir\">Output directory</label>\n\t\t\t\t\t\t<input id=\"graph-prop-output-dir\" name=\"graph-prop-output-dir\" type=\"text\" value=\"{{$.Graph.OutputDir}}\" title=\"Where to write the generated package, relative to the directory containing this file. If empty, graphs inside a Go module are generated next to this file, and other graphs are generated into the package path in GOPATH.\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<input id=\"graph-prop-is-command\" name=\"graph-prop-is-command\" type=\"checkbox\" {{if $.Graph.IsCommand}}checked{{end}} title=\"Selecting this means the generated package line will be 'package main' instead of 'package [packagename]', which allows your package to run as a standalone command and be installed with 'go install'. De-selecting this causes the package to be usable as a library.\"></input>\n\t\t\t\t\t    <label for=\"graph-prop-is-command\">Is a command?</label>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<input id=\"graph-prop-use-context\" name=\"graph-prop-use-context\" type=\"checkbox\" {{if $.Graph.UseContext}}checked{{end}} title=\"Selecting this generates 'Run(ctx context.Context) error' instead of 'Run()'. Each goroutine can use ctx, and can return an error; the first error cancels ctx for the others and is returned from Run. Commands cancel ctx on SIGINT or SIGTERM.\"></input>\n\t\t\t\t\t    <label for=\"graph-prop-use-context\">Run takes a context?</label>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<input id=\"graph-prop-telemetry\" name=\"graph-prop-telemetry\" type=\"checkbox\" {{if $.Graph.Telemetry}}checked{{end}} title=\"Selecting this generates code that counts values sent and received on each channel, and tracks which goroutines are running. When the program is started with Run, the diagram shows the counts, and highlights blocked channels, as the program runs.\"></input>\n\t\t\t\t\t    <label for=\"graph-prop-telemetry\">Show telemetry when running?</label>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<input id=\"graph-prop-generics\" name=\"graph-prop-generics\" type=\"checkbox\" {{if $.Graph.Generics}}checked{{end}} title=\"Selecting this implements the standard parts (Queue, Cache, Zip, Gather, Broadcast, Unbatch and KeyCounter) by calling generic functions in the shenzhen-go/parts/generic package, instead of generating a copy of each part for its types. The generated code requires Go 1.18 or later.\"></input>\n\t\t\t\t\t    <label for=\"graph-prop-generics\">Use generic parts (Go 1.18+)?</label>\n\t\t\t\t\t</div>\n\t\t\t\t</div>\n\t\t\t</div>\n\t\t\t<div id=\"hterm-panel\" class=\"panel\" style=\"display:none\">\n\t\t\t\t<div id=\"hterm-terminal\" class=\"terminal\"></div>\n\t\t\t</div>\n\t\t\t<div id=\"preview-go\" class=\"panel\" style=\"display:none\">\n\t\t\t\t<div id=\"preview-go-ace\" class=\"codeedit\"></div>\n\t\t\t</div>\n\t\t\t<div id=\"preview-json\" class=\"panel\" style=\"display:none\">\n\t\t\t\t<div id=\"preview-json-ace\" class=\"codeedit\"></div>\n\t\t\t</div>\n\t\t\t<div id=\"channel-properties\" class=\"panel padded\" style=\"display:none\">\n\t\t\t\t<h3>Channel Properties</h3>\n\t\t\t\t<div id=\"channel-actions\" class=\"head\">\n\t\t\t\t\t<span id=\"channel-delete-link\" class=\"link destructive\" title=\"Delete this channel\">Delete</a>\n\t\t\t\t</div>\n\t\t\t\t<div id=\"channel-properties-panel\" class=\"form\">\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label for=\"channel-name\">Name</label>\n\t\t\t\t\t\t<input id=\"channel-name\" name=\"channel-name\" type=\"text\" required value=\"channel\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label>Type</label>\n\t\t\t\t\t\t<code id=\"channel-type\">type</code>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label for=\"channel-capacity\">Capacity</label>\n\t\t\t\t\t\t<input id=\"channel-capacity\" name=\"channel-capacity\" type=\"number\" required pattern=\"^[0-9]+$\" title=\"Must be a whole number, at least 0.\" value=\"0\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t</div>\n\t\t\t</div>\n\t\t\t<div id=\"node-properties\" class=\"panel padded\" style=\"display:none\">\n\t\t\t\t<h3>Node Properties</h3>\n\t\t\t\t<div id=\"node-actions\" class=\"head\">\n\t\t\t\t\t<!--\n\t\t\t\t\t<span id=\"node-clone-link\" class=\"link\" title=\"Make a copy of this goroutine.\">Clone</span> | \n\t\t\t\t\t<span id=\"node-convert-link\" class=\"link destructive\" title=\"Change this goroutine into a Code goroutine; it cannot be converted back.\">Convert to Code</span> | \n\t\t\t\t    -->\n\t\t\t\t\t<span id=\"node-delete-link\" class=\"link destructive\" title=\"Delete this goroutine\">Delete</span>\n\t\t\t\t</div>\n\t\t\t\t<div id=\"node-panels\" class=\"head\">\n\t\t\t\t\t<span id=\"node-metadata-link\" class=\"link selected\">Properties</span> \n\t\t\t\t\t{{range $tk, $type := $.PartTypes}}\n\t\t\t\t\t<span id=\"node-{{$tk}}-links\" style=\"display:none\">\n\t\t\t\t\t{{range $type.Panels }}\n\t\t\t\t\t| <span id=\"node-{{$tk}}-{{.Name}}-link\" class=\"link\">{{.Name}}</span>\n\t\t\t\t\t{{end}}\n\t\t\t\t\t</span>\n\t\t\t\t\t{{end}}\n\t\t\t\t</div>\n\t\t\t\t<div id=\"node-metadata-panel\" class=\"form\">\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label for=\"node-name\">Name</label>\n\t\t\t\t\t\t<input id=\"node-name\" name=\"node-name\" type=\"text\" required value=\"{.Name}\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label for=\"node-comment\">Comment</label>\n\t\t\t\t\t\t<textarea id=\"node-comment\" name=\"node-comment\" rows=\"4\" cols=\"32\"></textarea>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<input id=\"node-enabled\" name=\"node-enabled\" type=\"checkbox\" checked></input>\n\t\t\t\t\t\t<label for=\"node-enabled\">Enabled</label>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label for=\"node-multiplicity\">Multiplicity</label>\n\t\t\t\t\t\t<input id=\"node-multiplicity\" name=\"node-multiplicity\" type=\"text\" required value=\"1\" title=\"An integer expression. You may use literals, graph parameters, and `n`, which equals the result of runtime.NumCPU\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<input id=\"node-wait\" name=\"node-wait\" type=\"checkbox\" checked></input>\n\t\t\t\t\t\t<label for=\"node-wait\">Wait for this to finish</label>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<input id=\"node-recover\" name=\"node-recover\" type=\"checkbox\"></input>\n\t\t\t\t\t\t<label for=\"node-recover\">Recover panics</label>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<input id=\"node-restart\" name=\"node-restart\" type=\"checkbox\"></input>\n\t\t\t\t\t\t<label for=\"node-restart\">Restart after a panic</label>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label for=\"node-max-restarts\">Maximum restarts</label>\n\t\t\t\t\t\t<input id=\"node-max-restarts\" name=\"node-max-restarts\" type=\"number\" min=\"0\" value=\"0\" title=\"Restarts allowed for each instance, or 0 for no limit\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<label for=\"node-restart-backoff\">Restart backoff</label>\n\t\t\t\t\t\t<input id=\"node-restart-backoff\" name=\"node-restart-backoff\" type=\"text\" title=\"How long to wait before the first restart, such as 100ms; 1s if empty. The wait doubles after each restart\"></input>\n\t\t\t\t\t</div>\n\t\t\t\t\t<div class=\"formfield\">\n\t\t\t\t\t\t<input id=\"node-restart-metrics\" name=\"node-restart-metrics\" type=\"checkbox\"></input>\n\t\t\t\t\t\t<label for=\"node-restart-metrics\">Count restarts in metrics</label>\n\t\t\t\t\t</div>\n\t\t\t\t</div>\n\t\t\t\t{{range $tk, $type := $.PartTypes}}\n\t\t\t\t{{range $type.Panels}}\n\t\t\t\t<div class=\"node-panel\" id=\"node-{{$tk}}-{{.Name}}-panel\" style=\"display:none\">\n\t\t\t\t\t{{.Editor}}\n\t\t\t\t</div>\n\t\t\t\t{{end}}\n\t\t\t\t{{end}}\n\t\t\t</div>\n\t\t\t<div id=\"help-licenses-panel\" class=\"panel padded\" style=\"display:none\">\n\t\t\t\t<h3>Licenses</h3>\n\t\t\t\t{{range $.Licenses}}\n\t\t\t\t<h4>{{.Component}}</h4>\n\t\t\t\t<iframe src=\"{{.URL}}\"></iframe>\n\t\t\t\t{{end}}\n\t\t\t</div>\n\t\t\t<div id=\"help-about-panel\" class=\"panel padded\" style=\"display:none\">\n\t\t\t\t<h3>Shenzhen Go</h3>\n\t\t\t\t(working title)\n\t\t\t\t<p>\n\t\t\t\t\tCopyright 2018 Google Inc.\n\t\t\t\t</p>\n\t\t\t\t<p>\n\t\t\t\t\tNote that this is not an official Google product.\n\t\t\t\t</p>\n\t\t\t\t<p>\n\t\t\t\t\t<a href=\"https://github.com/google/shenzhen-go\">Get the source code</a><br/>\n\t\t\t\t\t<a href=\"https://google.github.io/shenzhen-go\">Online documentation</a>\n\t\t\t\t</p>\n\t\t\t\t<!-- TODO: Put build info (git hash, etc) in here via template -->\n\t\t\t</div>\n\t\t</div>\n\t</div>\n\t<script src=\"/.static/js/client.js\"></script>\n</body>\n</html>\n"),
 }
@@ -0,0 +1,277 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/google/shenzhen-go/model"
+	"github.com/google/shenzhen-go/model/pin"
+)
+
+// Diagram geometry, matching client/view.
+const (
+	svgArrowSize       = 5
+	svgNodeBoxMargin   = 20
+	svgNodeHeight      = 50
+	svgNodeWidthPerPin = 20
+	svgPinRadius       = 5
+
+	svgFontSize = 16 // 12pt, as in the theme CSS
+	svgMargin   = 20 // around the whole diagram
+)
+
+// svgStyle resembles the default theme of the editor.
+const svgStyle = `
+text { fill: #000; font: normal 12pt Go,'San Francisco','Helvetica Neue',Helvetica,Arial,sans-serif; dominant-baseline: middle; text-anchor: middle; }
+g.node rect { fill: #e0f0ff; stroke: #45607a; stroke-width: 1; }
+g.node circle { fill: #000; }
+g.channel line { stroke: #000; stroke-width: 2; }
+g.channel path, g.channel circle { fill: #000; }
+`
+
+// goRegular is used to measure text, since the client uses the size the
+// browser gives the text.
+var goRegular = func() *sfnt.Font {
+	f, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}()
+
+// textWidth returns the width of s in Go Regular at the diagram font size.
+func textWidth(s string) float64 {
+	var (
+		buf   sfnt.Buffer
+		w     fixed.Int26_6
+		prev  sfnt.GlyphIndex
+		ppem  = fixed.I(svgFontSize)
+		first = true
+	)
+	for _, r := range s {
+		x, err := goRegular.GlyphIndex(&buf, r)
+		if err != nil {
+			continue
+		}
+		if !first {
+			if k, err := goRegular.Kern(&buf, prev, x, ppem, font.HintingNone); err == nil {
+				w += k
+			}
+		}
+		if a, err := goRegular.GlyphAdvance(&buf, x, ppem, font.HintingNone); err == nil {
+			w += a
+		}
+		prev, first = x, false
+	}
+	return float64(w) / 64
+}
+
+// svgNode is the layout of a node in the diagram.
+type svgNode struct {
+	name    string
+	x, y, w float64
+	pins    map[string]complex128 // absolute pin positions
+	order   []string              // pin names, inputs then outputs
+}
+
+// layoutNode places a node and its pins like the client does: pins are
+// spread evenly along the top (inputs) and bottom (outputs) of the box,
+// sorted by name.
+func layoutNode(n *model.Node) *svgNode {
+	name := n.Name
+	if n.Multiplicity != "1" {
+		name = fmt.Sprintf("%s (×%s)", n.Name, n.Multiplicity)
+	}
+	var ins, outs []string
+	for pn, p := range n.Part.Pins() {
+		if p.Direction == pin.Input {
+			ins = append(ins, pn)
+		} else {
+			outs = append(outs, pn)
+		}
+	}
+	sort.Strings(ins)
+	sort.Strings(outs)
+
+	minW := float64(svgNodeWidthPerPin * (maxInt(len(ins), len(outs)) + 1))
+	sn := &svgNode{
+		name:  name,
+		x:     n.X,
+		y:     n.Y,
+		w:     math.Max(textWidth(name)+2*svgNodeBoxMargin, minW),
+		pins:  make(map[string]complex128, len(ins)+len(outs)),
+		order: append(ins, outs...),
+	}
+	isp := sn.w / float64(len(ins)+1)
+	for i, pn := range ins {
+		sn.pins[pn] = complex(sn.x+isp*float64(i+1), sn.y-svgPinRadius)
+	}
+	osp := sn.w / float64(len(outs)+1)
+	for i, pn := range outs {
+		sn.pins[pn] = complex(sn.x+osp*float64(i+1), sn.y+svgNodeHeight+svgPinRadius)
+	}
+	return sn
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// svgBounds tracks the extent of the diagram.
+type svgBounds struct {
+	min, max complex128
+	empty    bool
+}
+
+func (b *svgBounds) add(p complex128) {
+	if b.empty {
+		b.min, b.max, b.empty = p, p, false
+		return
+	}
+	b.min = complex(math.Min(real(b.min), real(p)), math.Min(imag(b.min), imag(p)))
+	b.max = complex(math.Max(real(b.max), real(p)), math.Max(imag(b.max), imag(p)))
+}
+
+// SVG writes a standalone SVG document drawing the graph as the editor
+// does: node boxes with pins, and channels routed from output pins through
+// the average position of their pins to input pins.
+func SVG(w io.Writer, g *model.Graph) error {
+	nodes := make(map[string]*svgNode, len(g.Nodes))
+	bounds := svgBounds{empty: true}
+	for nn, n := range g.Nodes {
+		sn := layoutNode(n)
+		nodes[nn] = sn
+		bounds.add(complex(sn.x, sn.y-2*svgPinRadius))
+		bounds.add(complex(sn.x+sn.w, sn.y+svgNodeHeight+2*svgPinRadius))
+	}
+	if bounds.empty {
+		bounds.add(0)
+	}
+	bounds.min -= complex(svgMargin, svgMargin)
+	bounds.max += complex(svgMargin, svgMargin)
+	size := bounds.max - bounds.min
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="%g %g %g %g">`+"\n",
+		real(size), imag(size), real(bounds.min), imag(bounds.min), real(size), imag(size))
+	fmt.Fprintf(buf, "<title>%s</title>\n<style>%s</style>\n", html.EscapeString(g.Name), svgStyle)
+
+	cns := make([]string, 0, len(g.Channels))
+	for cn := range g.Channels {
+		cns = append(cns, cn)
+	}
+	sort.Strings(cns)
+	for _, cn := range cns {
+		writeChannel(buf, g, g.Channels[cn], nodes)
+	}
+	nns := make([]string, 0, len(nodes))
+	for nn := range nodes {
+		nns = append(nns, nn)
+	}
+	sort.Strings(nns)
+	for _, nn := range nns {
+		sn := nodes[nn]
+		fmt.Fprintf(buf, `<g class="node" transform="translate(%g, %g)">`+"\n", sn.x, sn.y)
+		fmt.Fprintf(buf, `<rect width="%g" height="%d"/><text x="%g" y="%g">%s</text>`+"\n",
+			sn.w, svgNodeHeight, sn.w/2, svgNodeHeight/2.0, html.EscapeString(sn.name))
+		for _, pn := range sn.order {
+			p := sn.pins[pn] - complex(sn.x, sn.y)
+			fmt.Fprintf(buf, `<circle cx="%g" cy="%g" r="%d"><title>%s</title></circle>`+"\n",
+				real(p), imag(p), svgPinRadius, html.EscapeString(pn))
+		}
+		buf.WriteString("</g>\n")
+	}
+	buf.WriteString("</svg>\n")
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// writeChannel draws a channel as routes between its pins and the average
+// position of its pins, with a point there if it joins more than two pins.
+func writeChannel(buf *bytes.Buffer, g *model.Graph, c *model.Channel, nodes map[string]*svgNode) {
+	type end struct {
+		pt    complex128
+		input bool
+	}
+	var ends []end
+	var mid complex128
+	nps := make([]model.NodePin, 0, len(c.Pins))
+	for np := range c.Pins {
+		nps = append(nps, np)
+	}
+	sort.Slice(nps, func(i, j int) bool {
+		if nps[i].Node != nps[j].Node {
+			return nps[i].Node < nps[j].Node
+		}
+		return nps[i].Pin < nps[j].Pin
+	})
+	for _, np := range nps {
+		n, sn := g.Nodes[np.Node], nodes[np.Node]
+		if n == nil {
+			continue
+		}
+		p, ok := sn.pins[np.Pin]
+		if !ok {
+			continue
+		}
+		ends = append(ends, end{pt: p, input: n.Part.Pins()[np.Pin].Direction == pin.Input})
+		mid += p
+	}
+	if len(ends) < 2 {
+		return
+	}
+	mid /= complex(float64(len(ends)), 0)
+
+	fmt.Fprintf(buf, `<g class="channel"><title>%s</title>`+"\n", html.EscapeString(c.Name))
+	for _, e := range ends {
+		// Routes go from source to destination.
+		a, b := mid, e.pt
+		if !e.input {
+			a, b = b, a
+		}
+		fmt.Fprintf(buf, `<line x1="%g" y1="%g" x2="%g" y2="%g"/>`+"\n", real(a), imag(a), real(b), imag(b))
+		d := b - a
+		md := cmplx.Abs(d)
+		if md < 3*svgArrowSize {
+			// Too crowded for an arrow.
+			continue
+		}
+		m := (a + b) / 2
+		d *= complex(svgArrowSize/md, 0)
+		p := d * 1i
+		c0, c1, c2 := m+d, m-d+p, m-d-p
+		fmt.Fprintf(buf, `<path d="M %g %g L %g %g L %g %g z"/>`+"\n",
+			real(c0), imag(c0), real(c1), imag(c1), real(c2), imag(c2))
+	}
+	if len(ends) > 2 {
+		fmt.Fprintf(buf, `<circle cx="%g" cy="%g" r="%d"/>`+"\n", real(mid), imag(mid), svgPinRadius)
+	}
+	buf.WriteString("</g>\n")
+}
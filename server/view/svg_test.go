@@ -0,0 +1,97 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package view
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/shenzhen-go/model"
+	"github.com/google/shenzhen-go/model/pin"
+	"github.com/google/shenzhen-go/parts"
+)
+
+func TestSVG(t *testing.T) {
+	node := func(name, mult string, x float64, pins pin.Map, conns map[string]string) *model.Node {
+		return &model.Node{
+			Name:         name,
+			Multiplicity: mult,
+			Part:         parts.NewCode(nil, "", "", "", pins),
+			X:            x,
+			Connections:  conns,
+		}
+	}
+	g := &model.Graph{
+		Name: "a <b>",
+		Nodes: map[string]*model.Node{
+			"src": node("src", "1", 0, pin.NewMap(
+				&pin.Definition{Name: "out", Type: "int", Direction: pin.Output},
+			), map[string]string{"out": "c"}),
+			"dst": node("dst", "N", 200, pin.NewMap(
+				&pin.Definition{Name: "in", Type: "int", Direction: pin.Input},
+			), map[string]string{"in": "c"}),
+		},
+		Channels: map[string]*model.Channel{
+			"c": {
+				Name: "c",
+				Pins: map[model.NodePin]struct{}{
+					{Node: "src", Pin: "out"}: {},
+					{Node: "dst", Pin: "in"}:  {},
+				},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := SVG(buf, g); err != nil {
+		t.Fatalf("SVG() = %v, want nil", err)
+	}
+	got := buf.String()
+
+	// The document should be well-formed.
+	d := xml.NewDecoder(strings.NewReader(got))
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("SVG output isn't well-formed: %v\n%s", err, got)
+		}
+	}
+
+	sw := svgNodeBoxMargin*2 + textWidth("src")
+	for _, want := range []string{
+		`<title>a &lt;b&gt;</title>`,
+		`<g class="node" transform="translate(200, 0)">`,
+		`<text x="`, `>dst (×N)</text>`,
+		`<g class="channel"><title>c</title>`,
+		// The output pin of src is at the bottom centre of its box.
+		`<circle cx="` + fmt.Sprintf("%g", sw/2) + `" cy="55" r="5"><title>out</title></circle>`,
+		// The input pin of dst is at the top centre of its box.
+		`cy="-5" r="5"><title>in</title></circle>`,
+		`<path d="M `,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("SVG output doesn't contain %q:\n%s", want, got)
+		}
+	}
+	// Two-pin channels have no steiner point.
+	if strings.Count(got, "<circle") != 2 {
+		t.Errorf("SVG output has %d circles, want 2 pins:\n%s", strings.Count(got, "<circle"), got)
+	}
+}
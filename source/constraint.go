@@ -0,0 +1,284 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"strings"
+	"unicode"
+)
+
+// constraintKind is the broad kind of a Constraint.
+type constraintKind int
+
+const (
+	constraintAny constraintKind = iota
+	constraintComparable
+	constraintInteger
+	constraintFloat
+	constraintOrdered
+	constraintInterface // an interface with methods
+)
+
+var (
+	constraintKinds = map[string]constraintKind{
+		"any":        constraintAny,
+		"comparable": constraintComparable,
+		"integer":    constraintInteger,
+		"float":      constraintFloat,
+		"ordered":    constraintOrdered,
+	}
+
+	// Types used for parameters that could not be inferred, when
+	// interface{} doesn't satisfy the constraint.
+	constraintDefaults = map[constraintKind]string{
+		constraintInteger: "int",
+		constraintFloat:   "float64",
+		constraintOrdered: "int",
+	}
+
+	integerTypes = NewStringSet(
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"uintptr", "byte", "rune",
+	)
+	floatTypes = NewStringSet("float32", "float64")
+
+	// Predeclared types without methods, which is all except error and any.
+	predeclaredTypes = Union(integerTypes, floatTypes,
+		NewStringSet("bool", "string", "complex64", "complex128"))
+)
+
+// Constraint restricts the types that a type parameter may be inferred as.
+// In a type string, a constraint follows a parameter after a colon, for
+// example map[$Key:comparable]$Value. A constraint is one of any,
+// comparable, integer, float (float32 or float64), ordered (integer,
+// float, or string), or an interface type such as fmt.Stringer or
+// interface{ Len() int }.
+//
+// Types are checked syntactically, since the types of other packages
+// aren't loaded. Named types other than the predeclared types are assumed
+// to satisfy the constraint.
+type Constraint struct {
+	scope string
+	src   string
+	kind  constraintKind
+}
+
+// NewConstraint parses a constraint. Any qualified identifiers in it
+// belong to the scope.
+func NewConstraint(scope, c string) (*Constraint, error) {
+	c = strings.TrimSpace(c)
+	if k, ok := constraintKinds[c]; ok {
+		return &Constraint{scope: scope, src: c, kind: k}, nil
+	}
+	expr, err := parser.ParseExpr(c)
+	if err != nil {
+		return nil, fmt.Errorf("parsing constraint %q: %v", c, err)
+	}
+	switch x := expr.(type) {
+	case *ast.InterfaceType:
+		if x.Methods == nil || len(x.Methods.List) == 0 {
+			return &Constraint{scope: scope, src: c, kind: constraintAny}, nil
+		}
+	case *ast.Ident:
+		if predeclaredTypes.Ni(x.Name) {
+			return nil, fmt.Errorf("constraint %q is not an interface", c)
+		}
+	case *ast.SelectorExpr:
+		if !isType(x) {
+			return nil, fmt.Errorf("constraint %q is not an interface", c)
+		}
+	default:
+		return nil, fmt.Errorf("constraint %q is not an interface", c)
+	}
+	return &Constraint{scope: scope, src: c, kind: constraintInterface}, nil
+}
+
+func (c *Constraint) String() string { return c.src }
+
+// Default returns a type satisfying the constraint, for use when the type
+// parameter could not be inferred, or nil if interface{} satisfies it.
+func (c *Constraint) Default() *Type {
+	switch c.kind {
+	case constraintInterface:
+		return MustNewType(c.scope, c.src)
+	case constraintAny, constraintComparable:
+		return nil
+	}
+	return MustNewType(c.scope, constraintDefaults[c.kind])
+}
+
+// SatisfiedBy returns an error if t doesn't satisfy the constraint.
+// Type parameters remaining in t are assumed to satisfy it.
+func (c *Constraint) SatisfiedBy(t *Type) error {
+	if t == nil {
+		return nil
+	}
+	if !c.satisfiedBy(t, t.expr) {
+		return fmt.Errorf("%s does not satisfy %s", t, c)
+	}
+	return nil
+}
+
+func (c *Constraint) satisfiedBy(t *Type, e ast.Expr) bool {
+	if p, ok := e.(*ast.ParenExpr); ok {
+		return c.satisfiedBy(t, p.X)
+	}
+	if id, ok := e.(*ast.Ident); ok {
+		if _, para := t.identToParam[id]; para {
+			return true
+		}
+	}
+	switch c.kind {
+	case constraintComparable:
+		return comparable(e)
+	case constraintInteger:
+		return numeric(e, integerTypes)
+	case constraintFloat:
+		return numeric(e, floatTypes)
+	case constraintOrdered:
+		if id, ok := e.(*ast.Ident); ok && id.Name == "string" {
+			return true
+		}
+		return numeric(e, integerTypes) || numeric(e, floatTypes)
+	case constraintInterface:
+		return mayHaveMethods(e)
+	}
+	return true
+}
+
+// comparable reports whether values of type e might be compared with ==.
+func comparable(e ast.Expr) bool {
+	switch x := e.(type) {
+	case *ast.ArrayType:
+		// Slices aren't comparable, arrays are if the elements are.
+		return x.Len != nil && comparable(x.Elt)
+	case *ast.FuncType, *ast.MapType:
+		return false
+	case *ast.ParenExpr:
+		return comparable(x.X)
+	case *ast.StructType:
+		for _, f := range x.Fields.List {
+			if !comparable(f.Type) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// numeric reports whether e might be one of the basic types in set.
+func numeric(e ast.Expr, set StringSet) bool {
+	switch x := e.(type) {
+	case *ast.Ident:
+		if set.Ni(x.Name) {
+			return true
+		}
+		// Other predeclared types definitely aren't in the set.
+		return !predeclaredTypes.Ni(x.Name) && x.Name != "error" && x.Name != "any"
+	case *ast.SelectorExpr:
+		// e.g. time.Duration.
+		return true
+	}
+	return false
+}
+
+// mayHaveMethods reports whether e might be a type with methods.
+func mayHaveMethods(e ast.Expr) bool {
+	switch x := e.(type) {
+	case *ast.Ident:
+		return !predeclaredTypes.Ni(x.Name) && x.Name != "any"
+	case *ast.InterfaceType:
+		return x.Methods != nil && len(x.Methods.List) > 0
+	case *ast.SelectorExpr, *ast.StarExpr:
+		return true
+	}
+	return false
+}
+
+// splitConstraints removes the constraints from a type string. It returns
+// the type without constraints, and the constraints following each type
+// parameter.
+func splitConstraints(t string) (string, map[string][]string, error) {
+	if !strings.Contains(t, paramPrefix) {
+		return t, nil, nil
+	}
+	var b strings.Builder
+	var cons map[string][]string
+	for i := 0; i < len(t); {
+		if !strings.HasPrefix(t[i:], paramPrefix) {
+			b.WriteByte(t[i])
+			i++
+			continue
+		}
+		j := i + len(paramPrefix)
+		for j < len(t) && isIdentRune(rune(t[j])) {
+			j++
+		}
+		param := t[i:j]
+		b.WriteString(param)
+		i = j
+		if i >= len(t) || t[i] != ':' {
+			continue
+		}
+		i++
+		for i < len(t) && t[i] == ' ' {
+			i++
+		}
+		j = i
+		if strings.HasPrefix(t[i:], "interface") {
+			k := strings.IndexByte(t[i:], '{')
+			if k < 0 {
+				return "", nil, fmt.Errorf("missing { in constraint for %s", param)
+			}
+			depth := 0
+			for j = i + k; j < len(t); j++ {
+				if t[j] == '{' {
+					depth++
+				}
+				if t[j] == '}' {
+					depth--
+				}
+				if depth == 0 {
+					j++
+					break
+				}
+			}
+			if depth != 0 {
+				return "", nil, fmt.Errorf("missing } in constraint for %s", param)
+			}
+		} else {
+			for j < len(t) && (isIdentRune(rune(t[j])) || t[j] == '.') {
+				j++
+			}
+		}
+		if i == j {
+			return "", nil, fmt.Errorf("missing constraint for %s", param)
+		}
+		if cons == nil {
+			cons = make(map[string][]string)
+		}
+		cons[param] = append(cons[param], t[i:j])
+		i = j
+	}
+	return b.String(), cons, nil
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
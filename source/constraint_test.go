@@ -0,0 +1,156 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"testing"
+
+	"gopkg.in/d4l3k/messagediff.v1"
+)
+
+func TestNewTypeConstraints(t *testing.T) {
+	tests := []struct {
+		spec     string
+		wantType string
+		want     map[string][]string
+	}{
+		{
+			spec:     "$T",
+			wantType: "$T",
+			want:     map[string][]string{},
+		},
+		{
+			spec:     "map[$K:comparable]$V",
+			wantType: "map[$K]$V",
+			want:     map[string][]string{"$K": {"comparable"}},
+		},
+		{
+			spec:     "struct{ N $N: integer; S $S:fmt.Stringer }",
+			wantType: "struct{ N $N; S $S }",
+			want:     map[string][]string{"$N": {"integer"}, "$S": {"fmt.Stringer"}},
+		},
+		{
+			spec:     "func($T:interface{ Len() int }) $T:ordered",
+			wantType: "func($T) $T",
+			want:     map[string][]string{"$T": {"interface{ Len() int }", "ordered"}},
+		},
+	}
+	for _, test := range tests {
+		typ, err := NewType("foo", test.spec)
+		if err != nil {
+			t.Fatalf("NewType(foo, %q) = error %v", test.spec, err)
+		}
+		if got, want := typ.String(), MustNewType("foo", test.wantType).String(); got != want {
+			t.Errorf("NewType(foo, %q).String() = %q, want %q", test.spec, got, want)
+		}
+		got := make(map[string][]string)
+		for tp, cs := range typ.Constraints() {
+			if tp.Scope != "foo" {
+				t.Errorf("NewType(foo, %q) constraint has scope %q, want foo", test.spec, tp.Scope)
+			}
+			for _, c := range cs {
+				got[tp.Ident] = append(got[tp.Ident], c.String())
+			}
+		}
+		if diff, equal := messagediff.PrettyDiff(got, test.want); !equal {
+			t.Errorf("NewType(foo, %q).Constraints() diff:\n%s", test.spec, diff)
+		}
+	}
+}
+
+func TestNewTypeConstraintErrors(t *testing.T) {
+	for _, spec := range []string{
+		"$T:",
+		"[]$T:int",
+		"$T:[]int",
+		"$T:interface{",
+	} {
+		if _, err := NewType("foo", spec); err == nil {
+			t.Errorf("NewType(foo, %q) = nil error, want error", spec)
+		}
+	}
+}
+
+func TestConstraintSatisfiedBy(t *testing.T) {
+	tests := []struct {
+		constraint string
+		typ        string
+		want       bool
+	}{
+		{"any", "[]func()", true},
+		{"comparable", "string", true},
+		{"comparable", "*int", true},
+		{"comparable", "[3]string", true},
+		{"comparable", "struct{ A int; B interface{} }", true},
+		{"comparable", "somepkg.Type", true},
+		{"comparable", "$T", true},
+		{"comparable", "[]byte", false},
+		{"comparable", "map[string]int", false},
+		{"comparable", "func()", false},
+		{"comparable", "[3][]int", false},
+		{"comparable", "struct{ A int; B []int }", false},
+		{"integer", "int64", true},
+		{"integer", "time.Duration", true},
+		{"integer", "MyInt", true},
+		{"integer", "float64", false},
+		{"integer", "string", false},
+		{"integer", "[]int", false},
+		{"float", "float32", true},
+		{"float", "int", false},
+		{"ordered", "string", true},
+		{"ordered", "uint8", true},
+		{"ordered", "bool", false},
+		{"fmt.Stringer", "time.Duration", true},
+		{"fmt.Stringer", "*bytes.Buffer", true},
+		{"fmt.Stringer", "int", false},
+		{"fmt.Stringer", "interface{}", false},
+		{"fmt.Stringer", "struct{}", false},
+		{"interface{ Len() int }", "interface{ Len() int }", true},
+		{"interface{ Len() int }", "[]int", false},
+		{"interface{}", "[]int", true},
+	}
+	for _, test := range tests {
+		c, err := NewConstraint("foo", test.constraint)
+		if err != nil {
+			t.Fatalf("NewConstraint(foo, %q) = error %v", test.constraint, err)
+		}
+		err = c.SatisfiedBy(MustNewType("foo", test.typ))
+		if got := err == nil; got != test.want {
+			t.Errorf("NewConstraint(foo, %q).SatisfiedBy(%s) = error %v, want satisfied = %t", test.constraint, test.typ, err, test.want)
+		}
+	}
+}
+
+func TestConstraintDefault(t *testing.T) {
+	tests := []struct {
+		constraint, want string
+	}{
+		{"any", "<unspecified>"},
+		{"comparable", "<unspecified>"},
+		{"integer", "int"},
+		{"float", "float64"},
+		{"ordered", "int"},
+		{"fmt.Stringer", "fmt.Stringer"},
+	}
+	for _, test := range tests {
+		c, err := NewConstraint("foo", test.constraint)
+		if err != nil {
+			t.Fatalf("NewConstraint(foo, %q) = error %v", test.constraint, err)
+		}
+		if got := c.Default().String(); got != test.want {
+			t.Errorf("NewConstraint(foo, %q).Default() = %s, want %s", test.constraint, got, test.want)
+		}
+	}
+}
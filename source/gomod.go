@@ -0,0 +1,80 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrNoModule is returned by FindModule when there is no enclosing module.
+var ErrNoModule = errors.New("no go.mod found in directory or any parent directory")
+
+// FindModule looks for a go.mod file in dir, or failing that, each parent of
+// dir in turn. It returns the directory containing go.mod, and the module path
+// declared in it.
+func FindModule(dir string) (modDir, modPath string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	for {
+		gm := filepath.Join(dir, "go.mod")
+		b, err := ioutil.ReadFile(gm)
+		if err == nil {
+			mp := ModulePath(b)
+			if mp == "" {
+				return "", "", fmt.Errorf("no module path declared in %s", gm)
+			}
+			return dir, mp, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", "", err
+		}
+		par := filepath.Dir(dir)
+		if par == dir {
+			return "", "", ErrNoModule
+		}
+		dir = par
+	}
+}
+
+// ModulePath returns the module path from the contents of a go.mod file,
+// or the empty string if it cannot be found.
+func ModulePath(gomod []byte) string {
+	sc := bufio.NewScanner(bytes.NewReader(gomod))
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		f := strings.Fields(line)
+		if len(f) != 2 || f[0] != "module" {
+			continue
+		}
+		if p, err := strconv.Unquote(f[1]); err == nil {
+			return p
+		}
+		return f[1]
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestModulePath(t *testing.T) {
+	tests := []struct {
+		gomod, want string
+	}{
+		{"", ""},
+		{"module example.com/foo\n", "example.com/foo"},
+		{"// A comment\nmodule   example.com/bar // trailing\n\nrequire x v1.0.0\n", "example.com/bar"},
+		{`module "example.com/quoted"`, "example.com/quoted"},
+		{"modulefoo example.com/nope\n", ""},
+	}
+	for _, test := range tests {
+		if got := ModulePath([]byte(test.gomod)); got != test.want {
+			t.Errorf("ModulePath(%q) = %q, want %q", test.gomod, got, test.want)
+		}
+	}
+}
+
+func TestFindModule(t *testing.T) {
+	root, err := ioutil.TempDir("", "findmodule")
+	if err != nil {
+		t.Fatalf("TempDir() = error %v", err)
+	}
+	defer os.RemoveAll(root)
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatalf("MkdirAll() = error %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/m\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() = error %v", err)
+	}
+
+	dir, mp, err := FindModule(sub)
+	if err != nil {
+		t.Fatalf("FindModule(%q) = error %v", sub, err)
+	}
+	if dir != root {
+		t.Errorf("FindModule(%q) dir = %q, want %q", sub, dir, root)
+	}
+	if want := "example.com/m"; mp != want {
+		t.Errorf("FindModule(%q) path = %q, want %q", sub, mp, want)
+	}
+}
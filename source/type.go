@@ -51,6 +51,9 @@ type Type struct {
 
 	// Used for tracking import usage to enable deconflicting imports.
 	selectorToScope map[*ast.SelectorExpr]string
+
+	// Constraints written in the type string given to NewType.
+	constraints map[TypeParam][]*Constraint
 }
 
 // mangle/unmangle assume that paramPrefix is invalid in a regular Go type, which
@@ -86,8 +89,25 @@ func MustNewType(scope, t string) *Type {
 
 // NewType parses a generic type string into a Type.
 // All parameters and selector expressions are assumed to belong
-// to the one given scope.
+// to the one given scope. Parameters may be followed by a constraint,
+// e.g. map[$K:comparable]$V; see Constraint.
 func NewType(scope, t string) (*Type, error) {
+	bare, cons, err := splitConstraints(t)
+	if err != nil {
+		return nil, err
+	}
+	constraints := make(map[TypeParam][]*Constraint, len(cons))
+	for param, cs := range cons {
+		tp := TypeParam{Scope: scope, Ident: param}
+		for _, c := range cs {
+			con, err := NewConstraint(scope, c)
+			if err != nil {
+				return nil, err
+			}
+			constraints[tp] = append(constraints[tp], con)
+		}
+	}
+	t = bare
 	expr, err := parser.ParseExpr(mangle(t))
 	if err != nil {
 		return nil, err
@@ -130,10 +150,20 @@ func NewType(scope, t string) (*Type, error) {
 		paramToIdents:   paramToIdents,
 		identToParam:    identToParam,
 		selectorToScope: selToScope,
+		constraints:     constraints,
 		expr:            expr,
 	}, nil
 }
 
+// Constraints returns the constraints on type parameters that were written
+// in the type string given to NewType.
+func (p *Type) Constraints() map[TypeParam][]*Constraint {
+	if p == nil {
+		return nil
+	}
+	return p.constraints
+}
+
 // clone returns a deep copy of p, unless it is nil or plain.
 // clone is only needed for parametrised types to prevent
 // parameter forgetfulness.
@@ -351,13 +381,22 @@ func (p *Type) refine1(tp TypeParam, subst *Type) error {
 		if id.ident == p.expr {
 			// Substitute the whole thing right now;
 			// the whole of p is nothing but one type parameter.
+			// Don't share the qualifiers, since p might adopt more later.
 			*p = *subst
+			p.selectorToScope = make(map[*ast.SelectorExpr]string, len(subst.selectorToScope))
+			for sel, sc := range subst.selectorToScope {
+				p.selectorToScope[sel] = sc
+			}
 			return nil
 		}
 		if err := id.refine(subst.expr); err != nil {
 			return err
 		}
 		delete(p.identToParam, id.ident)
+		// Adopt subst's qualified identifiers.
+		for sel, sc := range subst.selectorToScope {
+			p.selectorToScope[sel] = sc
+		}
 		// And adopt subt's params.
 		for sid, stp := range subst.identToParam {
 			p.identToParam[sid] = stp
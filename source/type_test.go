@@ -148,6 +148,27 @@ func TestScopedQualifiers(t *testing.T) {
 	}
 }
 
+func TestScopedQualifiersAfterRefine(t *testing.T) {
+	tests := []struct {
+		base string
+		want map[ScopedQualifier]struct{}
+	}{
+		{"$T", map[ScopedQualifier]struct{}{{"bar", "pkg"}: {}}},
+		{"[]$T", map[ScopedQualifier]struct{}{{"bar", "pkg"}: {}}},
+		{"map[other.Key]$T", map[ScopedQualifier]struct{}{{"foo", "other"}: {}, {"bar", "pkg"}: {}}},
+	}
+	for _, test := range tests {
+		p := MustNewType("foo", test.base)
+		in := TypeInferenceMap{{"foo", "$T"}: MustNewType("bar", "*pkg.Baz")}
+		if _, err := p.Refine(in); err != nil {
+			t.Fatalf("%s.Refine(%v) = error %v", test.base, in, err)
+		}
+		if diff, equal := messagediff.PrettyDiff(p.ScopedQualifiers(), test.want); !equal {
+			t.Errorf("%s.ScopedQualifiers() diff\n%s", test.base, diff)
+		}
+	}
+}
+
 func TestRefine(t *testing.T) {
 	tests := []struct {
 		base *Type
@@ -0,0 +1,267 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package telemetry records the activity of channels and nodes in programs
+// generated by Shenzhen Go with telemetry enabled, and sends it to the
+// address in the environment variable named by EnvVar (if set).
+//
+// Samples are sent as a stream of JSON-encoded Sample values over TCP.
+package telemetry
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EnvVar is the environment variable holding the address to send samples to.
+const EnvVar = "SHENZHEN_GO_TELEMETRY"
+
+// Interval is the time between samples.
+const Interval = 100 * time.Millisecond
+
+// blockedAfter is how long a relayed send has to wait before the channel
+// is reported as blocked.
+const blockedAfter = 10 * time.Millisecond
+
+// Channel describes the state of a channel.
+type Channel struct {
+	Name     string `json:"name"`
+	Sent     uint64 `json:"sent"`     // values sent so far
+	Received uint64 `json:"received"` // values received so far
+	Len      int    `json:"len"`      // values currently buffered
+	Cap      int    `json:"cap"`      // capacity of the channel
+	Blocked  bool   `json:"blocked"`  // a sender is waiting for a receiver
+	Closed   bool   `json:"closed"`
+}
+
+// Node describes the state of a node.
+type Node struct {
+	Name     string `json:"name"`
+	Running  bool   `json:"running"`
+	Finished bool   `json:"finished"`
+	Err      string `json:"err,omitempty"` // the error returned, if any
+}
+
+// Sample is a snapshot of all the channels and nodes, sorted by name.
+type Sample struct {
+	Channels []Channel `json:"channels"`
+	Nodes    []Node    `json:"nodes"`
+}
+
+// Recorder records the activity of channels and nodes.
+type Recorder struct {
+	mu       sync.Mutex
+	channels map[string]*channelStat
+	nodes    map[string]*NodeStat
+
+	enc  *json.Encoder
+	conn net.Conn
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewRecorder returns a Recorder that doesn't send samples anywhere.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		channels: make(map[string]*channelStat),
+		nodes:    make(map[string]*NodeStat),
+	}
+}
+
+// Start returns a new Recorder. If EnvVar is set, it connects to the
+// address and sends a Sample every Interval until Stop is called.
+// Telemetry is best-effort: if the connection fails, the Recorder still
+// works but doesn't send anything.
+func Start() *Recorder {
+	r := NewRecorder()
+	addr := os.Getenv(EnvVar)
+	if addr == "" {
+		return r
+	}
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return r
+	}
+	r.conn, r.enc = conn, json.NewEncoder(conn)
+	r.stop, r.done = make(chan struct{}), make(chan struct{})
+	go r.send()
+	return r
+}
+
+func (r *Recorder) send() {
+	defer close(r.done)
+	t := time.NewTicker(Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-r.stop:
+			r.enc.Encode(r.Sample())
+			return
+		case <-t.C:
+			if err := r.enc.Encode(r.Sample()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Stop sends a final Sample and closes the connection, if there is one.
+func (r *Recorder) Stop() {
+	if r.conn == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+	r.conn.Close()
+}
+
+// Channel starts recording a channel, which must be a bidirectional
+// channel. It returns a new unbuffered channel of the same type, to be used
+// by senders instead of ch. Values sent to the new channel are counted and
+// relayed to ch, and closing it closes ch after the last value is relayed.
+// Note that the relay holds one value while it waits, so the effective
+// capacity is one more than the capacity of ch.
+func (r *Recorder) Channel(name string, ch interface{}) interface{} {
+	out := reflect.ValueOf(ch)
+	in := reflect.MakeChan(out.Type(), 0)
+	s := &channelStat{ch: out}
+	r.mu.Lock()
+	r.channels[name] = s
+	r.mu.Unlock()
+	go s.relay(in)
+	return in.Interface()
+}
+
+// Node returns the NodeStat for recording the state of the named node.
+func (r *Recorder) Node(name string) *NodeStat {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := r.nodes[name]
+	if n == nil {
+		n = new(NodeStat)
+		r.nodes[name] = n
+	}
+	return n
+}
+
+// Sample returns the current state of all the channels and nodes.
+func (r *Recorder) Sample() Sample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now().UnixNano()
+	s := Sample{
+		Channels: make([]Channel, 0, len(r.channels)),
+		Nodes:    make([]Node, 0, len(r.nodes)),
+	}
+	for name, c := range r.channels {
+		s.Channels = append(s.Channels, c.sample(name, now))
+	}
+	for name, n := range r.nodes {
+		s.Nodes = append(s.Nodes, n.sample(name))
+	}
+	sort.Slice(s.Channels, func(i, j int) bool { return s.Channels[i].Name < s.Channels[j].Name })
+	sort.Slice(s.Nodes, func(i, j int) bool { return s.Nodes[i].Name < s.Nodes[j].Name })
+	return s
+}
+
+type channelStat struct {
+	ch        reflect.Value
+	sent      uint64
+	delivered uint64
+	waiting   int64 // UnixNano time a relayed send started waiting, or 0
+	closed    int32
+}
+
+func (s *channelStat) relay(in reflect.Value) {
+	for {
+		v, ok := in.Recv()
+		if !ok {
+			atomic.StoreInt32(&s.closed, 1)
+			s.ch.Close()
+			return
+		}
+		atomic.AddUint64(&s.sent, 1)
+		atomic.StoreInt64(&s.waiting, time.Now().UnixNano())
+		s.ch.Send(v)
+		atomic.StoreInt64(&s.waiting, 0)
+		atomic.AddUint64(&s.delivered, 1)
+	}
+}
+
+func (s *channelStat) sample(name string, now int64) Channel {
+	c := Channel{
+		Name:   name,
+		Sent:   atomic.LoadUint64(&s.sent),
+		Len:    s.ch.Len(),
+		Cap:    s.ch.Cap(),
+		Closed: atomic.LoadInt32(&s.closed) != 0,
+	}
+	// Values still in the buffer have been delivered but not received.
+	if d := atomic.LoadUint64(&s.delivered); d > uint64(c.Len) {
+		c.Received = d - uint64(c.Len)
+	}
+	if w := atomic.LoadInt64(&s.waiting); w != 0 && time.Duration(now-w) >= blockedAfter {
+		c.Blocked = true
+	}
+	return c
+}
+
+// NodeStat records the state of a node.
+type NodeStat struct {
+	mu       sync.Mutex
+	running  bool
+	finished bool
+	err      error
+}
+
+// Start marks the node as running.
+func (n *NodeStat) Start() {
+	n.mu.Lock()
+	n.running = true
+	n.mu.Unlock()
+}
+
+// Finish marks the node as finished.
+func (n *NodeStat) Finish() {
+	n.mu.Lock()
+	n.running, n.finished = false, true
+	n.mu.Unlock()
+}
+
+// Fail records the error returned by the node.
+func (n *NodeStat) Fail(err error) {
+	n.mu.Lock()
+	n.err = err
+	n.mu.Unlock()
+}
+
+func (n *NodeStat) sample(name string) Node {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	s := Node{
+		Name:     name,
+		Running:  n.running,
+		Finished: n.finished,
+	}
+	if n.err != nil {
+		s.Err = n.err.Error()
+	}
+	return s
+}
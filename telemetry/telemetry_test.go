@@ -0,0 +1,136 @@
+// Copyright 2018 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"gopkg.in/d4l3k/messagediff.v1"
+)
+
+func TestRecorderChannel(t *testing.T) {
+	r := NewRecorder()
+	c := make(chan int, 3)
+	send := r.Channel("c", c).(chan int)
+	// 3 values go in the buffer, and 1 is held by the relay.
+	for i := 0; i < 4; i++ {
+		send <- i
+	}
+	<-c
+	close(send)
+	time.Sleep(2 * blockedAfter)
+
+	got := r.Sample().Channels
+	want := []Channel{{Name: "c", Sent: 4, Received: 1, Len: 3, Cap: 3, Closed: true}}
+	if diff, equal := messagediff.PrettyDiff(got, want); !equal {
+		t.Errorf("Sample().Channels diff:\n%s", diff)
+	}
+
+	// Draining c should reach the end, because closing send closes c.
+	n := 0
+	for range c {
+		n++
+	}
+	if got, want := n, 3; got != want {
+		t.Errorf("received %d more values, want %d", got, want)
+	}
+	got = r.Sample().Channels
+	want = []Channel{{Name: "c", Sent: 4, Received: 4, Len: 0, Cap: 3, Closed: true}}
+	if diff, equal := messagediff.PrettyDiff(got, want); !equal {
+		t.Errorf("Sample().Channels diff:\n%s", diff)
+	}
+}
+
+func TestRecorderChannelBlocked(t *testing.T) {
+	r := NewRecorder()
+	c := make(chan int)
+	send := r.Channel("c", c).(chan int)
+	send <- 1
+	time.Sleep(2 * blockedAfter)
+	if got := r.Sample().Channels[0]; !got.Blocked {
+		t.Errorf("Sample().Channels[0].Blocked = false, want true")
+	}
+	<-c
+	time.Sleep(2 * blockedAfter)
+	if got := r.Sample().Channels[0]; got.Blocked {
+		t.Errorf("Sample().Channels[0].Blocked = true, want false")
+	}
+}
+
+func TestRecorderNode(t *testing.T) {
+	r := NewRecorder()
+	r.Node("a").Start()
+	r.Node("b").Start()
+	r.Node("b").Fail(errors.New("oops"))
+	r.Node("b").Finish()
+	got := r.Sample().Nodes
+	want := []Node{
+		{Name: "a", Running: true},
+		{Name: "b", Finished: true, Err: "oops"},
+	}
+	if diff, equal := messagediff.PrettyDiff(got, want); !equal {
+		t.Errorf("Sample().Nodes diff:\n%s", diff)
+	}
+}
+
+func TestStart(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() = %v", err)
+	}
+	defer l.Close()
+	os.Setenv(EnvVar, l.Addr().String())
+	defer os.Unsetenv(EnvVar)
+
+	r := Start()
+	conn, err := l.Accept()
+	if err != nil {
+		t.Fatalf("Accept() = %v", err)
+	}
+	defer conn.Close()
+	r.Node("a").Start()
+	r.Node("a").Finish()
+	r.Stop()
+
+	// The last sample sent is the final one, sent by Stop.
+	var last Sample
+	dec := json.NewDecoder(conn)
+	for {
+		var s Sample
+		if err := dec.Decode(&s); err != nil {
+			break
+		}
+		last = s
+	}
+	want := Sample{
+		Channels: []Channel{},
+		Nodes:    []Node{{Name: "a", Finished: true}},
+	}
+	if diff, equal := messagediff.PrettyDiff(last, want); !equal {
+		t.Errorf("last sample diff:\n%s", diff)
+	}
+}
+
+func TestStartWithoutEnvVar(t *testing.T) {
+	os.Unsetenv(EnvVar)
+	r := Start()
+	r.Node("a").Start()
+	r.Stop() // shouldn't block or panic
+}